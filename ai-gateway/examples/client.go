@@ -12,11 +12,16 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const gatewayURL = "http://localhost:8000"
@@ -67,6 +72,180 @@ func Health() (*HealthResponse, error) {
 	return &h, json.Unmarshal(raw, &h)
 }
 
+// ---------------------------------------------------------------------------
+// Streaming (SSE)
+
+// Token is one incremental chunk of a streamed response. Backend lets
+// callers detect a mid-stream failover between copilot/cloud/local.
+type Token struct {
+	Content string `json:"content"`
+	Backend string `json:"backend"`
+}
+
+// QueryStream streams a plain prompt from /ai/query/stream, delivering
+// tokens as they arrive. Cancel ctx to stop the stream early (this is the
+// client's Close()).
+func QueryStream(ctx context.Context, prompt string) (<-chan Token, <-chan error) {
+	body := map[string]string{"prompt": prompt}
+	return startStream(ctx, body)
+}
+
+// QueryRAGStream streams a RAG-augmented prompt from /ai/query/stream.
+// Cancel ctx to stop the stream early (this is the client's Close()).
+func QueryRAGStream(ctx context.Context, prompt string, documents []string) (<-chan Token, <-chan error) {
+	body := map[string]any{
+		"prompt":    prompt,
+		"documents": documents,
+	}
+	return startStream(ctx, body)
+}
+
+// startStream launches the reconnecting SSE loop and returns its token and
+// error channels. Both channels are closed once ctx is canceled.
+func startStream(ctx context.Context, payload any) (<-chan Token, <-chan error) {
+	tokens := make(chan Token)
+	errs := make(chan error, 8)
+	go streamLoop(ctx, payload, tokens, errs)
+	return tokens, errs
+}
+
+// streamLoop reconnects to /ai/query/stream with exponential backoff. While
+// the gateway's circuit breaker is open, it pauses reconnects and polls
+// Health() until the breaker reports anything other than "open" (i.e.
+// half-open or closed) before retrying.
+func streamLoop(ctx context.Context, payload any, tokens chan<- Token, errs chan<- error) {
+	defer close(tokens)
+	defer close(errs)
+
+	const minBackoff = time.Second
+	const maxBackoff = 30 * time.Second
+	backoff := minBackoff
+	lastEventID := ""
+
+	for ctx.Err() == nil {
+		if h, err := Health(); err == nil && h.CircuitState == "open" {
+			waitForBreakerToClear(ctx)
+		}
+
+		retryHint, err := streamOnce(ctx, payload, &lastEventID, tokens)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			select {
+			case errs <- err:
+			default: // drop if the caller isn't keeping up
+			}
+		}
+
+		wait := backoff
+		if retryHint > 0 {
+			wait = retryHint
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// waitForBreakerToClear polls Health() until the circuit breaker leaves the
+// "open" state or ctx is canceled.
+func waitForBreakerToClear(ctx context.Context) {
+	for ctx.Err() == nil {
+		if h, err := Health(); err == nil && h.CircuitState != "open" {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// streamOnce opens a single SSE connection to /ai/query/stream, parsing
+// "data:"/"event:"/"id:"/"retry:" framing and emitting a Token per event.
+// It returns the server's retry hint (zero if none was sent) and any error
+// that ended the connection (nil on a clean EOF).
+func streamOnce(ctx context.Context, payload any, lastEventID *string, tokens chan<- Token) (time.Duration, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gatewayURL+"/ai/query/stream", bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	if *lastEventID != "" {
+		req.Header.Set("Last-Event-ID", *lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("stream request failed: %s", resp.Status)
+	}
+
+	var retryHint time.Duration
+	var dataBuf, eventID string
+	reader := bufio.NewReader(resp.Body)
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			if dataBuf != "" {
+				var tok Token
+				if jsonErr := json.Unmarshal([]byte(dataBuf), &tok); jsonErr == nil {
+					select {
+					case tokens <- tok:
+					case <-ctx.Done():
+						return retryHint, ctx.Err()
+					}
+				}
+				if eventID != "" {
+					*lastEventID = eventID
+				}
+			}
+			dataBuf, eventID = "", ""
+		case strings.HasPrefix(line, "data:"):
+			if dataBuf != "" {
+				dataBuf += "\n"
+			}
+			dataBuf += strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")
+		case strings.HasPrefix(line, "id:"):
+			eventID = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+		case strings.HasPrefix(line, "retry:"):
+			if ms, convErr := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "retry:"))); convErr == nil {
+				retryHint = time.Duration(ms) * time.Millisecond
+			}
+		}
+
+		if readErr != nil {
+			if readErr == io.EOF {
+				return retryHint, nil
+			}
+			return retryHint, readErr
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 
 func postJSON[T any](path string, payload any) (*T, error) {
@@ -116,4 +295,26 @@ func main() {
 	} else {
 		fmt.Printf("Gateway: %s | circuit: %s\n", h.Status, h.CircuitState)
 	}
+
+	// --- streaming query ---
+	streamCtx, closeStream := context.WithCancel(context.Background())
+	tokens, streamErrs := QueryStream(streamCtx, "Stream a haiku about circuit breakers.")
+	for tokens != nil || streamErrs != nil {
+		select {
+		case tok, ok := <-tokens:
+			if !ok {
+				tokens = nil
+				continue
+			}
+			fmt.Printf("[%s] %s", tok.Backend, tok.Content)
+		case streamErr, ok := <-streamErrs:
+			if !ok {
+				streamErrs = nil
+				continue
+			}
+			fmt.Println("Stream error:", streamErr)
+		}
+	}
+	closeStream()
+	fmt.Println()
 }
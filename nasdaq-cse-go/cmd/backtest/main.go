@@ -0,0 +1,190 @@
+// Package main is the backtest CLI entry point. Given a config declaring an
+// "atrpin" block, it replays historical ticks through a strategy.Strategy
+// via pkg/backtest; given an "aibot" block instead, it replays historical
+// market data and trades through the AI assistant via internal/backtest.
+// Either way it prints the resulting PnL report, the offline counterpart to
+// cmd/server.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	aibacktest "github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/backtest"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/marketdata"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/pkg/backtest"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/pkg/strategy"
+)
+
+// config mirrors the atrpin/drift strategy-config pattern: top-level
+// backtest parameters plus one named strategy (or AI assistant) block.
+type config struct {
+	Symbol       string   `yaml:"symbol"`
+	Symbols      []string `yaml:"symbols"`
+	StartTime    string   `yaml:"startTime"`
+	EndTime      string   `yaml:"endTime"`
+	Interval     string   `yaml:"interval"`
+	TickStoreDir string   `yaml:"tickStoreDir"`
+	MakerFeeRate float64  `yaml:"makerFeeRate"`
+	TakerFeeRate float64  `yaml:"takerFeeRate"`
+	SlippageBps  float64  `yaml:"slippageBps"`
+
+	AtrPin *atrPinConfig `yaml:"atrpin"`
+	AIBot  *aiBotConfig  `yaml:"aibot"`
+}
+
+type atrPinConfig struct {
+	Quantity      float64 `yaml:"quantity"`
+	Multiplier    float64 `yaml:"multiplier"`
+	MinPriceRange float64 `yaml:"minPriceRange"`
+	AtrWindow     int     `yaml:"atrWindow"`
+}
+
+// aiBotConfig declares the accounts and historical-data directory an
+// "aibot" backtest replays against; Symbols/StartTime/EndTime/fee rates
+// come from the shared top-level config fields above.
+type aiBotConfig struct {
+	HistoryDir string             `yaml:"historyDir"`
+	Accounts   []aiBotAccountYAML `yaml:"accounts"`
+}
+
+type aiBotAccountYAML struct {
+	UserID          uint    `yaml:"userId"`
+	StartingBalance float64 `yaml:"startingBalance"`
+}
+
+func main() {
+	configPath := flag.String("config", "", "path to a backtest config (e.g. atrpin.yaml or aibot.yaml)")
+	flag.Parse()
+
+	if *configPath == "" {
+		log.Fatal("backtest: --config is required")
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("backtest: %v", err)
+	}
+
+	if cfg.AIBot != nil {
+		runAIBot(cfg)
+		return
+	}
+
+	report, err := runFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("backtest: %v", err)
+	}
+
+	fmt.Printf("Symbol: %s\n", cfg.Symbol)
+	fmt.Printf("Realized PnL:   %.2f\n", report.RealizedPnL)
+	fmt.Printf("Unrealized PnL: %.2f\n", report.UnrealizedPnL)
+	fmt.Printf("Max Drawdown:   %.2f\n", report.MaxDrawdown)
+	fmt.Printf("Sharpe:         %.4f\n", report.Sharpe)
+	fmt.Printf("Fills:          %d\n", len(report.Fills))
+
+	const chartPath = "backtest_pnl.png"
+	if err := backtest.SaveCumulativePnLChart(report, chartPath); err != nil {
+		log.Printf("backtest: failed to write PnL chart: %v", err)
+	} else {
+		fmt.Printf("PnL chart written to %s\n", chartPath)
+	}
+}
+
+func loadConfig(path string) (config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return config{}, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	var cfg config
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		return config{}, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if cfg.AtrPin == nil && cfg.AIBot == nil {
+		return config{}, fmt.Errorf("config %s must declare an atrpin or aibot block", path)
+	}
+	return cfg, nil
+}
+
+// runAIBot replays cfg's aibot block through internal/backtest and prints
+// one report section per configured account.
+func runAIBot(cfg config) {
+	startTime, err := time.Parse(time.RFC3339, cfg.StartTime)
+	if err != nil {
+		log.Fatalf("backtest: invalid startTime %q: %v", cfg.StartTime, err)
+	}
+	endTime, err := time.Parse(time.RFC3339, cfg.EndTime)
+	if err != nil {
+		log.Fatalf("backtest: invalid endTime %q: %v", cfg.EndTime, err)
+	}
+
+	accounts := make([]aibacktest.AccountConfig, 0, len(cfg.AIBot.Accounts))
+	for _, account := range cfg.AIBot.Accounts {
+		accounts = append(accounts, aibacktest.AccountConfig{
+			UserID:          account.UserID,
+			StartingBalance: account.StartingBalance,
+		})
+	}
+
+	report, err := aibacktest.Run(aibacktest.Config{
+		Symbols:      cfg.Symbols,
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Accounts:     accounts,
+		MakerFeeRate: cfg.MakerFeeRate,
+		TakerFeeRate: cfg.TakerFeeRate,
+	}, aibacktest.NewJSONMarketDataSource(cfg.AIBot.HistoryDir), aibacktest.NewJSONTradeSource(cfg.AIBot.HistoryDir))
+	if err != nil {
+		log.Fatalf("backtest: %v", err)
+	}
+
+	for _, account := range report.Accounts {
+		fmt.Printf("Account %d:\n", account.UserID)
+		fmt.Printf("  Realized PnL:   %.2f\n", account.RealizedPnL)
+		fmt.Printf("  Unrealized PnL: %.2f\n", account.UnrealizedPnL)
+		fmt.Printf("  Max Drawdown:   %.2f\n", account.MaxDrawdown)
+		fmt.Printf("  Sharpe:         %.4f\n", account.Sharpe)
+		fmt.Printf("  Win Rate:       %.2f%%\n", account.WinRate*100)
+	}
+}
+
+func runFromConfig(cfg config) (backtest.Report, error) {
+	startTime, err := time.Parse(time.RFC3339, cfg.StartTime)
+	if err != nil {
+		return backtest.Report{}, fmt.Errorf("invalid startTime %q: %w", cfg.StartTime, err)
+	}
+	endTime, err := time.Parse(time.RFC3339, cfg.EndTime)
+	if err != nil {
+		return backtest.Report{}, fmt.Errorf("invalid endTime %q: %w", cfg.EndTime, err)
+	}
+	interval, err := time.ParseDuration(cfg.Interval)
+	if err != nil {
+		return backtest.Report{}, fmt.Errorf("invalid interval %q: %w", cfg.Interval, err)
+	}
+
+	store, err := marketdata.NewJSONFileStore(cfg.TickStoreDir)
+	if err != nil {
+		return backtest.Report{}, fmt.Errorf("failed to open tick store %s: %w", cfg.TickStoreDir, err)
+	}
+
+	broker := backtest.NewSimulatedBroker(cfg.MakerFeeRate, cfg.TakerFeeRate, cfg.SlippageBps)
+	strat := strategy.NewAtrPinStrategy(cfg.Symbol, cfg.AtrPin.Quantity, cfg.AtrPin.Multiplier, cfg.AtrPin.MinPriceRange, cfg.AtrPin.AtrWindow, broker)
+
+	return backtest.Run(backtest.BacktestConfig{
+		Symbol:       cfg.Symbol,
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Interval:     interval,
+		Store:        store,
+		MakerFeeRate: cfg.MakerFeeRate,
+		TakerFeeRate: cfg.TakerFeeRate,
+		SlippageBps:  cfg.SlippageBps,
+		Broker:       broker,
+	}, strat)
+}
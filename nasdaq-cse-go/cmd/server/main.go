@@ -15,25 +15,34 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/aiassistant"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/auth"
 	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/communication"
 	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/exchange"
 	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/marketdata"
 	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/oms"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/persistence"
 	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/rms"
 	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/storage"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/strategy"
 )
 
 // Server holds all service dependencies
 type Server struct {
-	db              *storage.DatabaseManager
-	jsonStorage     *storage.JSONStorage
-	marketData      *marketdata.MarketDataService
-	orderManager    *oms.OrderManager
-	riskManager     *rms.RiskManager
-	tradingBot      *aiassistant.TradingBot
-	commManager     *communication.CommunicationManager
-	wsConnections   map[*websocket.Conn]bool
-	wsUpgrader      websocket.Upgrader
+	db                 *storage.DatabaseManager
+	jsonStorage        *storage.JSONStorage
+	marketData         *marketdata.MarketDataService
+	orderManager       *oms.OrderManager
+	riskManager        *rms.RiskManager
+	tradingBot         *aiassistant.TradingBot
+	commManager        *communication.CommunicationManager
+	exchangeManager    *exchange.Manager
+	instrumentRegistry *core.InstrumentRegistry
+	authService        *auth.Service
+	setupGate          *auth.SetupGate
+	strategyRunner     *strategy.StrategyRunner
+	wsConnections      map[*websocket.Conn]bool
+	wsUpgrader         websocket.Upgrader
 }
 
 // NewServer creates a new server instance
@@ -51,11 +60,53 @@ func NewServer() (*Server, error) {
 	}
 
 	// Initialize services
-	marketData := marketdata.NewMarketDataService()
 	orderManager := oms.NewOrderManager(db.GetDB())
+	if err := orderManager.RestoreOpenOrders(); err != nil {
+		log.Printf("failed to restore open orders into the matching engine: %v", err)
+	}
 	riskManager := rms.NewRiskManager(db.GetDB())
-	tradingBot := aiassistant.NewTradingBot()
+	aiStore, err := persistence.NewJSONStore("./data/ai_state")
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize AI assistant persistence store: %w", err)
+	}
+	tradingBot := aiassistant.NewTradingBotWithStore("./data/trading_bot_state.json", aiStore)
 	commManager := communication.NewCommunicationManager()
+	commManager.SetSessionStore(communication.NewDatabaseSessionStore(db.GetDB()))
+
+	fixGateway := communication.NewFIXOrderGateway(orderManager, 1)
+	if err := commManager.StartFIXAcceptor(":9878", "CSE_TRADING",
+		communication.NewDatabaseSessionStore(db.GetDB()), fixGateway); err != nil {
+		log.Printf("FIX acceptor not started: %v", err)
+	}
+
+	exchangeManager, err := loadExchangeManager("./configs/exchanges.yaml")
+	if err != nil {
+		log.Printf("exchange sessions not loaded: %v", err)
+	}
+	marketData := newMarketDataServiceWithExchanges(exchangeManager)
+
+	instrumentRegistry, err := loadInstrumentRegistry(jsonStorage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load instrument registry: %w", err)
+	}
+
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		log.Printf("JWT_SECRET not set; using an insecure development default")
+		jwtSecret = "dev-only-insecure-secret"
+	}
+	authService := auth.NewService(db.GetDB(), jwtSecret)
+
+	setupGate, setupToken, err := auth.NewSetupGate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize setup gate: %w", err)
+	}
+	fmt.Printf("admin setup token (POST /api/auth/setup): %s\n", setupToken)
+
+	strategyRunner := strategy.NewStrategyRunner(marketData, orderManager, riskManager, db.GetDB())
+	if err := loadConfiguredStrategies(strategyRunner, "./configs/strategies.yaml"); err != nil {
+		log.Printf("strategy config not loaded: %v", err)
+	}
 
 	// WebSocket upgrader
 	wsUpgrader := websocket.Upgrader{
@@ -65,15 +116,20 @@ func NewServer() (*Server, error) {
 	}
 
 	server := &Server{
-		db:            db,
-		jsonStorage:   jsonStorage,
-		marketData:    marketData,
-		orderManager:  orderManager,
-		riskManager:   riskManager,
-		tradingBot:    tradingBot,
-		commManager:   commManager,
-		wsConnections: make(map[*websocket.Conn]bool),
-		wsUpgrader:    wsUpgrader,
+		db:                 db,
+		jsonStorage:        jsonStorage,
+		marketData:         marketData,
+		orderManager:       orderManager,
+		riskManager:        riskManager,
+		tradingBot:         tradingBot,
+		commManager:        commManager,
+		exchangeManager:    exchangeManager,
+		instrumentRegistry: instrumentRegistry,
+		authService:        authService,
+		setupGate:          setupGate,
+		strategyRunner:     strategyRunner,
+		wsConnections:      make(map[*websocket.Conn]bool),
+		wsUpgrader:         wsUpgrader,
 	}
 
 	// Start background tasks
@@ -91,6 +147,121 @@ func (s *Server) Close() error {
 	return s.db.Close()
 }
 
+// loadExchangeManager loads a sessions YAML config (see
+// configs/exchanges.yaml) and authenticates its connectors, returning a nil
+// Manager (not an error) when the config file is absent so live exchange
+// connectivity stays opt-in.
+func loadExchangeManager(path string) (*exchange.Manager, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	cfg, err := exchange.LoadSessionsConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return exchange.NewManager(context.Background(), cfg)
+}
+
+// newMarketDataServiceWithExchanges builds the default MarketDataService,
+// then layers any exchangeManager sessions marked as market-data sources in
+// front of the GOLD*/Binance defaults so a configured live connector takes
+// priority over the built-in simulation.
+func newMarketDataServiceWithExchanges(exchangeManager *exchange.Manager) *marketdata.MarketDataService {
+	routes := []marketdata.SymbolRoute{
+		{Pattern: "GOLD*", Sources: []marketdata.PriceSource{marketdata.NewGoldPriceProvider()}},
+	}
+	if exchangeManager != nil {
+		for pattern, source := range exchangeManager.MarketDataSources() {
+			routes = append(routes, marketdata.SymbolRoute{Pattern: pattern, Sources: []marketdata.PriceSource{source}})
+		}
+	}
+	routes = append(routes, marketdata.SymbolRoute{Pattern: "*", Sources: []marketdata.PriceSource{marketdata.NewBinanceSource()}})
+
+	return marketdata.NewMarketDataServiceWithRoutes(routes)
+}
+
+// loadInstrumentRegistry loads contract-level tick/lot size constraints
+// from jsonStorage, seeding it with the simulator's default GOLD futures
+// listings (mirroring DatabaseManager's sample Contract rows) on first run.
+func loadInstrumentRegistry(jsonStorage *storage.JSONStorage) (*core.InstrumentRegistry, error) {
+	registry := core.NewInstrumentRegistry()
+
+	instruments, err := jsonStorage.LoadInstruments()
+	if err != nil {
+		return nil, err
+	}
+	if len(instruments) == 0 {
+		instruments = defaultInstruments()
+		if err := jsonStorage.SaveInstruments(instruments); err != nil {
+			return nil, fmt.Errorf("failed to seed instrument registry: %w", err)
+		}
+	}
+
+	registry.Load(instruments)
+	return registry, nil
+}
+
+// defaultInstruments is the simulator's default GOLD futures listings,
+// matching the symbols DatabaseManager.initSampleData seeds as
+// core.Contract rows.
+func defaultInstruments() []core.InstrumentInfo {
+	return []core.InstrumentInfo{
+		{
+			Symbol:         "GOLD2024DEC",
+			PriceTickSize:  0.01,
+			AmountTickSize: 1,
+			ContractVal:    100.0,
+			Delivery:       time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+			ContractType:   core.InstrumentContractThisWeek,
+		},
+		{
+			Symbol:         "GOLD2025MAR",
+			PriceTickSize:  0.01,
+			AmountTickSize: 1,
+			ContractVal:    100.0,
+			Delivery:       time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+			ContractType:   core.InstrumentContractNextWeek,
+		},
+		{
+			Symbol:         "GOLD2025JUN",
+			PriceTickSize:  0.01,
+			AmountTickSize: 1,
+			ContractVal:    100.0,
+			Delivery:       time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+			ContractType:   core.InstrumentContractQuarter,
+		},
+	}
+}
+
+// loadConfiguredStrategies reads a strategy config YAML file (see
+// configs/strategies.yaml) and registers each entry under runner, skipping
+// (not failing on) entries whose type hasn't been registered via
+// strategy.Register, since this build may not link any concrete strategy
+// packages. It's a no-op, not an error, when the config file is absent.
+func loadConfiguredStrategies(runner *strategy.StrategyRunner, path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	cfg, err := strategy.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range cfg.ExchangeStrategies {
+		s, err := strategy.NewStrategy(entry.Type, entry.Params)
+		if err != nil {
+			log.Printf("strategy %s not registered: %v", entry.ID, err)
+			continue
+		}
+		if err := runner.Register(entry.ID, 1, s); err != nil {
+			log.Printf("strategy %s not registered: %v", entry.ID, err)
+		}
+	}
+	return nil
+}
+
 // setupRoutes sets up all HTTP routes
 func (s *Server) setupRoutes() *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
@@ -101,12 +272,12 @@ func (s *Server) setupRoutes() *gin.Engine {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
-		
+
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
 		}
-		
+
 		c.Next()
 	})
 
@@ -116,13 +287,23 @@ func (s *Server) setupRoutes() *gin.Engine {
 	// Main trading interface
 	router.GET("/", s.handleRoot)
 
-	// API routes
+	// Auth routes (unauthenticated)
+	authRoutes := router.Group("/api/auth")
+	{
+		authRoutes.POST("/login", s.handleLogin)
+		authRoutes.POST("/refresh", s.handleRefresh)
+		authRoutes.POST("/setup", s.handleSetup)
+	}
+
+	// API routes (require a valid access token)
 	api := router.Group("/api")
+	api.Use(auth.RequireAuth(s.authService))
 	{
 		// Market data
 		api.GET("/market-data", s.handleGetMarketData)
 		api.GET("/charts/price", s.handleGetPriceChart)
 		api.GET("/charts/pnl", s.handleGetPnLChart)
+		api.GET("/charts/orderflow", s.handleGetOrderFlowChart)
 
 		// Orders
 		api.POST("/orders", s.handleSubmitOrder)
@@ -131,6 +312,7 @@ func (s *Server) setupRoutes() *gin.Engine {
 
 		// Trades and positions
 		api.GET("/trades", s.handleGetTrades)
+		api.GET("/trades/pnl-by-strategy", s.handleGetPnLByStrategy)
 		api.GET("/positions", s.handleGetPositions)
 
 		// AI assistant
@@ -140,6 +322,24 @@ func (s *Server) setupRoutes() *gin.Engine {
 		// Risk management
 		api.GET("/risk/report", s.handleRiskReport)
 		api.GET("/risk/margin", s.handleMarginStatus)
+
+		// FIX gateway
+		api.GET("/fix/sessions", s.handleFIXSessions)
+
+		// Exchange connectors
+		api.GET("/exchanges", s.handleExchangeStatus)
+
+		// Instrument registry
+		api.GET("/contracts", s.handleGetContracts)
+		api.GET("/contracts/:symbol", s.handleGetContract)
+
+		// Order book depth
+		api.GET("/orderbook", s.handleGetOrderBook)
+
+		// Strategy runtime
+		api.POST("/strategies/:id/start", s.handleStrategyStart)
+		api.POST("/strategies/:id/stop", s.handleStrategyStop)
+		api.POST("/strategies/:id/backtest", s.handleStrategyBacktest)
 	}
 
 	// WebSocket endpoint
@@ -355,11 +555,7 @@ func (s *Server) handleGetPriceChart(c *gin.Context) {
 
 // handleGetPnLChart returns P&L chart data
 func (s *Server) handleGetPnLChart(c *gin.Context) {
-	userIDStr := c.DefaultQuery("user_id", "1")
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		userID = 1
-	}
+	userID := s.authUserID(c)
 
 	// Get user positions for P&L calculation
 	positions, err := s.orderManager.GetUserPositions(uint(userID))
@@ -374,6 +570,14 @@ func (s *Server) handleGetPnLChart(c *gin.Context) {
 	})
 }
 
+// handleGetOrderFlowChart returns order-flow imbalance chart data
+func (s *Server) handleGetOrderFlowChart(c *gin.Context) {
+	chartData := s.tradingBot.OrderFlowChartData()
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"chart": chartData,
+	})
+}
+
 // handleSubmitOrder handles order submission
 func (s *Server) handleSubmitOrder(c *gin.Context) {
 	var orderRequest core.OrderCreateRequest
@@ -382,10 +586,14 @@ func (s *Server) handleSubmitOrder(c *gin.Context) {
 		return
 	}
 
-	userIDStr := c.DefaultQuery("user_id", "1")
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		userID = 1
+	userID := s.authUserID(c)
+
+	if err := s.validateInstrumentConstraints(orderRequest); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
 	}
 
 	// Check pre-trade risk
@@ -399,17 +607,82 @@ func (s *Server) handleSubmitOrder(c *gin.Context) {
 	}
 
 	result := s.orderManager.SubmitOrder(uint(userID), orderRequest)
+	s.forwardOrderToExchange(orderRequest)
+	s.applyOrderToDepthBook(orderRequest)
 	c.JSON(http.StatusOK, result)
 }
 
+// applyOrderToDepthBook adds a resting limit order's quantity to the
+// in-memory depth book and broadcasts the resulting diff, so WebSocket
+// clients can update their local order book without re-fetching
+// /api/orderbook. Market orders (no price) don't rest in the book, so
+// they're skipped.
+func (s *Server) applyOrderToDepthBook(orderRequest core.OrderCreateRequest) {
+	if orderRequest.Price == nil {
+		return
+	}
+
+	book := s.marketData.DepthBookFor(orderRequest.ContractSymbol)
+	update := book.ApplyOrder(orderRequest.Side, *orderRequest.Price, orderRequest.Quantity)
+
+	s.broadcastToWebSockets(map[string]interface{}{
+		"type": "depth",
+		"data": update,
+	})
+}
+
+// validateInstrumentConstraints rejects orderRequest if the registered
+// instrument for its contract symbol is unknown, expired, or the
+// requested price/quantity doesn't align to its tick sizes. Unregistered
+// symbols pass through unchecked, since not every symbol traded by the
+// simulator (e.g. spot crypto routed through the exchange package) has an
+// InstrumentInfo entry.
+func (s *Server) validateInstrumentConstraints(orderRequest core.OrderCreateRequest) error {
+	inst, ok := s.instrumentRegistry.Get(orderRequest.ContractSymbol)
+	if !ok {
+		return nil
+	}
+
+	if inst.IsExpired(time.Now()) {
+		return fmt.Errorf("contract %s has expired (delivery %s)", inst.Symbol, inst.Delivery.Format("2006-01-02"))
+	}
+	if orderRequest.Price != nil && !inst.AlignsToPriceTick(*orderRequest.Price) {
+		return fmt.Errorf("price %.4f does not align to %s's tick size %.4f", *orderRequest.Price, inst.Symbol, inst.PriceTickSize)
+	}
+	if !inst.AlignsToAmountTick(orderRequest.Quantity) {
+		return fmt.Errorf("quantity %.4f does not align to %s's lot size %.4f", orderRequest.Quantity, inst.Symbol, inst.AmountTickSize)
+	}
+	return nil
+}
+
+// forwardOrderToExchange best-effort mirrors orderRequest onto whichever
+// exchange session is configured to receive live orders. Forwarding
+// failures are logged, not surfaced to the caller, since the OMS fill this
+// request already produced is the simulator's system of record.
+func (s *Server) forwardOrderToExchange(orderRequest core.OrderCreateRequest) {
+	if s.exchangeManager == nil {
+		return
+	}
+	if _, ok := s.exchangeManager.OrderConnector(); !ok {
+		return
+	}
+
+	order := exchange.OrderRequest{
+		Symbol:    orderRequest.ContractSymbol,
+		Side:      string(orderRequest.Side),
+		OrderType: string(orderRequest.OrderType),
+		Quantity:  orderRequest.Quantity,
+		Price:     orderRequest.Price,
+	}
+	if _, err := s.exchangeManager.ForwardOrder(context.Background(), order); err != nil {
+		log.Printf("exchange order forwarding failed: %v", err)
+	}
+}
+
 // handleCancelOrder handles order cancellation
 func (s *Server) handleCancelOrder(c *gin.Context) {
 	orderID := c.Param("order_id")
-	userIDStr := c.DefaultQuery("user_id", "1")
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		userID = 1
-	}
+	userID := s.authUserID(c)
 
 	result := s.orderManager.CancelOrder(orderID, uint(userID))
 	c.JSON(http.StatusOK, result)
@@ -417,11 +690,7 @@ func (s *Server) handleCancelOrder(c *gin.Context) {
 
 // handleGetOrders returns user orders
 func (s *Server) handleGetOrders(c *gin.Context) {
-	userIDStr := c.DefaultQuery("user_id", "1")
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		userID = 1
-	}
+	userID := s.authUserID(c)
 
 	limitStr := c.DefaultQuery("limit", "100")
 	limit, err := strconv.Atoi(limitStr)
@@ -440,11 +709,7 @@ func (s *Server) handleGetOrders(c *gin.Context) {
 
 // handleGetTrades returns user trades
 func (s *Server) handleGetTrades(c *gin.Context) {
-	userIDStr := c.DefaultQuery("user_id", "1")
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		userID = 1
-	}
+	userID := s.authUserID(c)
 
 	limitStr := c.DefaultQuery("limit", "100")
 	limit, err := strconv.Atoi(limitStr)
@@ -461,14 +726,35 @@ func (s *Server) handleGetTrades(c *gin.Context) {
 	c.JSON(http.StatusOK, trades)
 }
 
-// handleGetPositions returns user positions
-func (s *Server) handleGetPositions(c *gin.Context) {
-	userIDStr := c.DefaultQuery("user_id", "1")
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
+// handleGetPnLByStrategy returns the caller's realized trade P&L between
+// ?from and ?to (RFC3339 timestamps), grouped by strategy tag.
+func (s *Server) handleGetPnLByStrategy(c *gin.Context) {
+	userID := s.authUserID(c)
+
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or missing 'from' (expected RFC3339)"})
+		return
+	}
+	to, err := time.Parse(time.RFC3339, c.Query("to"))
 	if err != nil {
-		userID = 1
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or missing 'to' (expected RFC3339)"})
+		return
+	}
+
+	breakdown, err := s.orderManager.GetUserPnLByStrategy(uint(userID), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
+	c.JSON(http.StatusOK, breakdown)
+}
+
+// handleGetPositions returns user positions
+func (s *Server) handleGetPositions(c *gin.Context) {
+	userID := s.authUserID(c)
+
 	positions, err := s.orderManager.GetUserPositions(uint(userID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -486,11 +772,14 @@ func (s *Server) handleAIChat(c *gin.Context) {
 		return
 	}
 
+	userID := s.authUserID(c)
+
 	// Get trading context
 	context := make(map[string]interface{})
 	context["market_data"] = s.marketData.GetCurrentPrice()
-	
-	positions, err := s.orderManager.GetUserPositions(chatRequest.UserID)
+	context["user_id"] = userID
+
+	positions, err := s.orderManager.GetUserPositions(userID)
 	if err == nil {
 		context["positions"] = positions
 	}
@@ -507,8 +796,10 @@ func (s *Server) handleAIAnalyze(c *gin.Context) {
 		return
 	}
 
+	userID := s.authUserID(c)
+
 	marketData := s.marketData.GetCurrentPrice()
-	positions, err := s.orderManager.GetUserPositions(contextRequest.UserID)
+	positions, err := s.orderManager.GetUserPositions(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -520,11 +811,7 @@ func (s *Server) handleAIAnalyze(c *gin.Context) {
 
 // handleRiskReport returns risk analysis report
 func (s *Server) handleRiskReport(c *gin.Context) {
-	userIDStr := c.DefaultQuery("user_id", "1")
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		userID = 1
-	}
+	userID := s.authUserID(c)
 
 	// Mock current prices for risk calculation
 	currentPrices := map[uint]float64{
@@ -539,11 +826,7 @@ func (s *Server) handleRiskReport(c *gin.Context) {
 
 // handleMarginStatus returns margin status
 func (s *Server) handleMarginStatus(c *gin.Context) {
-	userIDStr := c.DefaultQuery("user_id", "1")
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		userID = 1
-	}
+	userID := s.authUserID(c)
 
 	currentPrices := map[uint]float64{
 		1: s.marketData.GetCurrentPrice().Price,
@@ -555,8 +838,192 @@ func (s *Server) handleMarginStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, marginStatus)
 }
 
+// handleFIXSessions lists FIX counterparties currently connected to this
+// server's FIX acceptor.
+func (s *Server) handleFIXSessions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"sessions": s.commManager.FIXSessions()})
+}
+
+// handleExchangeStatus reports every configured exchange session's
+// connectivity.
+func (s *Server) handleExchangeStatus(c *gin.Context) {
+	if s.exchangeManager == nil {
+		c.JSON(http.StatusOK, gin.H{"sessions": []exchange.SessionStatus{}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": s.exchangeManager.Statuses()})
+}
+
+// handleGetContracts returns every registered instrument.
+func (s *Server) handleGetContracts(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"contracts": s.instrumentRegistry.All()})
+}
+
+// handleGetContract returns the registered instrument for :symbol, or 404
+// if it isn't registered.
+func (s *Server) handleGetContract(c *gin.Context) {
+	symbol := c.Param("symbol")
+	inst, ok := s.instrumentRegistry.Get(symbol)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "contract not found"})
+		return
+	}
+	c.JSON(http.StatusOK, inst)
+}
+
+// handleGetOrderBook returns the top price levels of symbol's order book.
+// limit defaults to 20 and caps the number of levels returned per side.
+func (s *Server) handleGetOrderBook(c *gin.Context) {
+	symbol := c.Query("symbol")
+	if symbol == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "symbol is required"})
+		return
+	}
+
+	limit := 20
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	c.JSON(http.StatusOK, s.marketData.SnapshotDepth(symbol, limit))
+}
+
+// handleStrategyStart starts routing live market data into :id's strategy.
+func (s *Server) handleStrategyStart(c *gin.Context) {
+	if err := s.strategyRunner.Start(c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "running"})
+}
+
+// handleStrategyStop halts :id's strategy.
+func (s *Server) handleStrategyStop(c *gin.Context) {
+	if err := s.strategyRunner.Stop(c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "stopped"})
+}
+
+// backtestRequest is the body handleStrategyBacktest expects.
+type backtestRequest struct {
+	Symbol string    `json:"symbol" binding:"required"`
+	From   time.Time `json:"from" binding:"required"`
+	To     time.Time `json:"to" binding:"required"`
+}
+
+// handleStrategyBacktest replays stored price history for req.Symbol
+// between req.From and req.To into :id's strategy and returns the
+// resulting P&L report.
+func (s *Server) handleStrategyBacktest(c *gin.Context) {
+	var req backtestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := s.strategyRunner.Backtest(c.Param("id"), req.Symbol, req.From, req.To)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// loginRequest is the body handleLogin expects.
+type loginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// handleLogin authenticates username/password and issues an access/refresh
+// token pair.
+func (s *Server) handleLogin(c *gin.Context) {
+	var req loginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := s.authService.Login(req.Username, req.Password)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// refreshRequest is the body handleRefresh expects.
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// handleRefresh exchanges a valid refresh token for a new token pair.
+func (s *Server) handleRefresh(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := s.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// setupRequest is the body handleSetup expects.
+type setupRequest struct {
+	SetupToken string `json:"setup_token" binding:"required"`
+	Username   string `json:"username" binding:"required"`
+	Email      string `json:"email" binding:"required"`
+	Password   string `json:"password" binding:"required"`
+}
+
+// handleSetup creates the first admin user, gated behind the one-time
+// token NewServer printed to stdout at startup.
+func (s *Server) handleSetup(c *gin.Context) {
+	var req setupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.setupGate.CompleteSetup(req.SetupToken); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := s.authService.CreateUser(req.Username, req.Email, req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, user)
+}
+
+// authUserID returns the authenticated user ID auth.RequireAuth set on c.
+func (s *Server) authUserID(c *gin.Context) uint {
+	userID, _ := c.Get("user_id")
+	id, _ := userID.(uint)
+	return id
+}
+
 // handleWebSocket handles WebSocket connections
 func (s *Server) handleWebSocket(c *gin.Context) {
+	if _, err := auth.VerifyWebSocketUpgrade(s.authService, c.Request); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
 	conn, err := s.wsUpgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
@@ -594,12 +1061,12 @@ func (s *Server) marketDataUpdater() {
 
 	for range ticker.C {
 		marketData := s.marketData.GetCurrentPrice()
-		
+
 		message := map[string]interface{}{
 			"type": "market_data",
 			"data": marketData,
 		}
-		
+
 		s.broadcastToWebSockets(message)
 	}
 }
@@ -645,7 +1112,7 @@ func main() {
 		fmt.Println("üìä Server running on http://localhost:8080")
 		fmt.Println("üåê WebSocket endpoint: ws://localhost:8080/ws")
 		fmt.Println("üìñ API documentation available at endpoints")
-		
+
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server failed to start: %v", err)
 		}
@@ -666,4 +1133,4 @@ func main() {
 	}
 
 	fmt.Println("‚úÖ Server shutdown complete")
-}
\ No newline at end of file
+}
@@ -0,0 +1,76 @@
+package aiassistant
+
+import "math"
+
+// defaultLeverage is the notional-to-margin divisor AccountValueCalculator
+// applies when TradingBot isn't told a position's actual leverage. The bot
+// only sees contract-agnostic positions (see SuggestHedgingStrategy), so it
+// conservatively treats every position as 1x (margin == notional) until
+// per-contract leverage is threaded through.
+const defaultLeverage = 1.0
+
+// MarginStatus is a snapshot of account health, computed by
+// AccountValueCalculator from a balance, a set of positions, and a mark
+// price.
+type MarginStatus struct {
+	Equity      float64
+	UsedMargin  float64
+	FreeMargin  float64
+	MarginLevel float64
+}
+
+// AccountValueCalculator computes MarginStatus from account balance, open
+// positions, and a current mark price, porting the MinMarginLevel /
+// AccountValueCalculator idea from bbgo's xmaker margin-credit handling.
+type AccountValueCalculator struct {
+	Leverage float64
+}
+
+// NewAccountValueCalculator creates an AccountValueCalculator using
+// leverage as the notional-to-margin divisor. leverage <= 0 defaults to 1x.
+func NewAccountValueCalculator(leverage float64) *AccountValueCalculator {
+	if leverage <= 0 {
+		leverage = defaultLeverage
+	}
+	return &AccountValueCalculator{Leverage: leverage}
+}
+
+// Compute returns equity = balance + unrealizedPnL, usedMargin =
+// sum(|qty*price| / Leverage) across positions (falling back to a
+// position's own avg_entry_price when markPrice is 0), and marginLevel =
+// equity / usedMargin (+Inf when nothing is used).
+func (avc *AccountValueCalculator) Compute(balance float64, positions []map[string]interface{}, markPrice float64) MarginStatus {
+	equity := balance
+	usedMargin := 0.0
+
+	for _, pos := range positions {
+		qty, ok := pos["quantity"].(float64)
+		if !ok {
+			continue
+		}
+
+		price := markPrice
+		if price <= 0 {
+			if avgPrice, ok := pos["avg_entry_price"].(float64); ok {
+				price = avgPrice
+			}
+		}
+		usedMargin += math.Abs(qty*price) / avc.Leverage
+
+		if unrealizedPnL, ok := pos["unrealized_pnl"].(float64); ok {
+			equity += unrealizedPnL
+		}
+	}
+
+	marginLevel := math.Inf(1)
+	if usedMargin > 0 {
+		marginLevel = equity / usedMargin
+	}
+
+	return MarginStatus{
+		Equity:      equity,
+		UsedMargin:  usedMargin,
+		FreeMargin:  equity - usedMargin,
+		MarginLevel: marginLevel,
+	}
+}
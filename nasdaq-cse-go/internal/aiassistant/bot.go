@@ -4,81 +4,270 @@ package aiassistant
 import (
 	"fmt"
 	"math"
-	"math/rand"
 	"strings"
 	"time"
 
 	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/persistence"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/risk/circuitbreaker"
 )
 
+// DefaultSymbol is the contract TradingBot's PriceSeries tracks. The bot's
+// public API (AnalyzeTradeOpportunity, ChatResponse, ...) doesn't carry a
+// contract symbol today, so the bot keeps its per-symbol PriceSeries map
+// keyed off this single symbol until callers start passing one explicitly.
+const DefaultSymbol = "GOLD2024DEC"
+
+// rsiPeriod and volPeriodsPerYear parameterize the real RSI/volatility
+// calculations PriceSeries performs for TradingBot.
+const (
+	rsiPeriod         = 14
+	bollingerPeriod   = 20
+	volPeriodsPerYear = 252.0 // trading days/year, matches daily-bar cadence
+)
+
+// PositionSizeConfig parameterizes SuggestPositionSize's ATR-pinning sizing
+// model: the bar window ATR is computed over, the multiplier that turns ATR
+// into a pinning price range, and the minimum current-bar range (as a
+// fraction of price) required before a position is sized at all.
+type PositionSizeConfig struct {
+	Window        int
+	Multiplier    float64
+	MinPriceRange float64
+}
+
+// DefaultPositionSizeConfig returns the ATR-pinning defaults: a 14-bar
+// window (matching rsiPeriod), ATR*100 as the pinning range, and a current
+// bar range of at least 0.1% of price before sizing a trade.
+func DefaultPositionSizeConfig() PositionSizeConfig {
+	return PositionSizeConfig{Window: 14, Multiplier: 100, MinPriceRange: 0.001}
+}
+
 // TechnicalIndicators holds calculated technical indicators
 type TechnicalIndicators struct {
-	RSI               float64 `json:"rsi"`
-	Volatility        float64 `json:"volatility"`
+	RSI                float64 `json:"rsi"`
+	Volatility         float64 `json:"volatility"`
 	MovingAverageRatio float64 `json:"moving_avg_ratio"`
+	BBUpper            float64 `json:"bb_upper"`
+	BBLower            float64 `json:"bb_lower"`
+	PercentB           float64 `json:"percent_b"`
+	VWAP               float64 `json:"vwap"`
+	ATR                float64 `json:"atr"`
+	PinningRange       float64 `json:"pinning_range"`
+}
+
+// PositionSizeSuggestion is what SuggestPositionSize returns: the ATR-sized
+// quantity, along with the ATR and pinning range it was derived from.
+// Quantity is 0 and Skipped is true when the current bar's range doesn't
+// clear PositionSizeConfig.MinPriceRange - too tight a bar to pin a
+// meaningful stop distance against.
+type PositionSizeSuggestion struct {
+	Quantity      float64 `json:"quantity"`
+	ATR           float64 `json:"atr"`
+	PinningRange  float64 `json:"pinning_range"`
+	CurrentRange  float64 `json:"current_range"`
+	Skipped       bool    `json:"skipped"`
+	SkippedReason string  `json:"skipped_reason,omitempty"`
 }
 
 // TradeAnalysis represents AI analysis for trade opportunities
 type TradeAnalysis struct {
-	Timestamp          string              `json:"timestamp"`
-	AnalysisType       string              `json:"analysis_type"`
-	CurrentPrice       float64             `json:"current_price"`
-	PredictedDirection string              `json:"predicted_direction"`
-	ConfidenceScore    float64             `json:"confidence_score"`
+	Timestamp           string              `json:"timestamp"`
+	AnalysisType        string              `json:"analysis_type"`
+	CurrentPrice        float64             `json:"current_price"`
+	PredictedDirection  string              `json:"predicted_direction"`
+	ConfidenceScore     float64             `json:"confidence_score"`
 	TechnicalIndicators TechnicalIndicators `json:"technical_indicators"`
-	Suggestion         string              `json:"suggestion"`
-	RiskLevel          string              `json:"risk_level"`
+	Suggestion          string              `json:"suggestion"`
+	RiskLevel           string              `json:"risk_level"`
+	CooldownUntil       string              `json:"cooldown_until,omitempty"`
+	DataQuality         DataQuality         `json:"data_quality"`
 }
 
 // RiskAnalysis represents risk assessment analysis
 type RiskAnalysis struct {
-	Timestamp          string    `json:"timestamp"`
-	AnalysisType       string    `json:"analysis_type"`
-	RiskLevel          string    `json:"risk_level"`
-	ExposureRatio      float64   `json:"exposure_ratio"`
-	ConcentrationRatio float64   `json:"concentration_ratio"`
-	TotalUnrealizedPnL float64   `json:"total_unrealized_pnl"`
-	TotalRealizedPnL   float64   `json:"total_realized_pnl"`
-	RiskWarnings       []string  `json:"risk_warnings"`
-	Recommendations    []string  `json:"recommendations"`
-	ConfidenceScore    float64   `json:"confidence_score"`
+	Timestamp          string      `json:"timestamp"`
+	AnalysisType       string      `json:"analysis_type"`
+	RiskLevel          string      `json:"risk_level"`
+	ExposureRatio      float64     `json:"exposure_ratio"`
+	ConcentrationRatio float64     `json:"concentration_ratio"`
+	TotalUnrealizedPnL float64     `json:"total_unrealized_pnl"`
+	TotalRealizedPnL   float64     `json:"total_realized_pnl"`
+	RiskWarnings       []string    `json:"risk_warnings"`
+	Recommendations    []string    `json:"recommendations"`
+	ConfidenceScore    float64     `json:"confidence_score"`
+	CooldownUntil      string      `json:"cooldown_until,omitempty"`
+	DataQuality        DataQuality `json:"data_quality"`
+	MarginLevel        float64     `json:"margin_level"`
+	UsedMargin         float64     `json:"used_margin"`
+	FreeMargin         float64     `json:"free_margin"`
 }
 
 // HedgingStrategy represents hedging suggestions
 type HedgingStrategy struct {
-	Timestamp           string                   `json:"timestamp"`
-	AnalysisType        string                   `json:"analysis_type"`
-	NetExposure         float64                  `json:"net_exposure"`
-	CurrentVolatility   float64                  `json:"current_volatility"`
-	HedgingSuggestions  []HedgingSuggestion      `json:"hedging_suggestions"`
-	ConfidenceScore     float64                  `json:"confidence_score"`
+	Timestamp          string              `json:"timestamp"`
+	AnalysisType       string              `json:"analysis_type"`
+	NetExposure        float64             `json:"net_exposure"`
+	CurrentVolatility  float64             `json:"current_volatility"`
+	HedgingSuggestions []HedgingSuggestion `json:"hedging_suggestions"`
+	ConfidenceScore    float64             `json:"confidence_score"`
+	DataQuality        DataQuality         `json:"data_quality"`
 }
 
 // HedgingSuggestion represents a single hedging suggestion
 type HedgingSuggestion struct {
-	Action   string  `json:"action"`
-	Quantity float64 `json:"quantity"`
-	Reason   string  `json:"reason"`
-	Contract string  `json:"contract"`
+	SuggestionID   string  `json:"suggestion_id"`
+	Action         string  `json:"action"`
+	Quantity       float64 `json:"quantity"`
+	Reason         string  `json:"reason"`
+	Contract       string  `json:"contract"`
+	SuggestedPrice float64 `json:"suggested_price"`
 }
 
 // TradingBot provides AI-powered trading assistance
 type TradingBot struct {
 	analysisHistory []interface{}
 	riskThresholds  map[string]float64
+	priceSeries     map[string]*PriceSeries
+	circuitBreaker  *CircuitBreaker
+	heartbeat       *PriceHeartbeat
+	accountCalc     *AccountValueCalculator
+	positionSizeCfg PositionSizeConfig
+	orderFlow       *OrderFlowAnalyzer
+
+	// statePath is where coveredPosition/profitStats are persisted as JSON.
+	// An empty path keeps the bot in-memory only (used by tests).
+	statePath       string
+	coveredPosition CoveredPosition
+	profitStats     ProfitStats
+	pendingHedges   map[string]pendingHedge
+	hedgeSeq        int
+
+	// persistStore, if non-nil, backs priceSeries/analysisHistory/
+	// chatContext across restarts (see persistence.go). nil keeps that
+	// state in-memory only.
+	persistStore persistence.Store
+	chatContext  map[uint][]ChatTurn
+
+	// riskBreaker halts SuggestHedgingStrategy/AnalyzeRisk per user once
+	// realized losses breach circuitbreaker.Config's guards.
+	riskBreaker *circuitbreaker.CircuitBreaker
 }
 
-// NewTradingBot creates a new trading bot instance
-func NewTradingBot() *TradingBot {
-	return &TradingBot{
+// NewTradingBot creates a new trading bot instance. statePath, if non-empty,
+// is a JSON file the bot loads its CoveredPosition/ProfitStats state from on
+// startup and persists to on every ConfirmHedge call; pass "" to keep that
+// state in-memory only.
+func NewTradingBot(statePath string) *TradingBot {
+	return NewTradingBotWithStore(statePath, nil)
+}
+
+// NewTradingBotWithStore creates a TradingBot the same way NewTradingBot
+// does, additionally backing its rolling price series, analysis history and
+// per-user chat context with store (see persistence.go); pass a nil store to
+// keep that state in-memory only, as NewTradingBot does.
+func NewTradingBotWithStore(statePath string, store persistence.Store) *TradingBot {
+	state := loadState(statePath)
+	tb := &TradingBot{
 		analysisHistory: make([]interface{}, 0),
 		riskThresholds: map[string]float64{
-			"high_exposure":       0.7,   // 70% of account
-			"high_concentration":  0.5,   // 50% in single position
-			"volatility_threshold": 0.05, // 5% daily volatility
-			"margin_warning":      0.8,   // 80% margin utilization
+			"high_exposure":            0.7,  // 70% of account
+			"high_concentration":       0.5,  // 50% in single position
+			"volatility_threshold":     0.05, // 5% daily volatility
+			"margin_warning":           0.8,  // 80% margin utilization
+			"min_margin_level":         1.5,  // below this, gate hedges to reduce-only
+			"liquidation_margin_level": 1.1,  // below this, MARGIN_CALL_IMMINENT
 		},
+		priceSeries:     make(map[string]*PriceSeries),
+		circuitBreaker:  NewCircuitBreaker(DefaultCircuitBreakerConfig()),
+		heartbeat:       NewPriceHeartbeat(defaultStaleAfter),
+		accountCalc:     NewAccountValueCalculator(defaultLeverage),
+		positionSizeCfg: DefaultPositionSizeConfig(),
+		orderFlow:       NewOrderFlowAnalyzer(defaultOrderFlowInterval, defaultOrderFlowCapacity),
+		statePath:       statePath,
+		coveredPosition: state.CoveredPosition,
+		profitStats:     state.ProfitStats,
+		pendingHedges:   state.PendingHedges,
+		hedgeSeq:        state.HedgeSeq,
+		persistStore:    store,
+		chatContext:     make(map[uint][]ChatTurn),
+		riskBreaker:     circuitbreaker.NewCircuitBreakerWithStore(circuitbreaker.DefaultConfig(), store, "trading_bot_risk_breaker"),
+	}
+	if store != nil {
+		tb.loadPersistentState()
+	}
+	return tb
+}
+
+// series returns DefaultSymbol's PriceSeries, or an empty one if no tick has
+// been ingested yet, so indicator calculations never dereference a nil map
+// entry before the first IngestMarketData call.
+func (tb *TradingBot) series() *PriceSeries {
+	if s, ok := tb.priceSeries[DefaultSymbol]; ok {
+		return s
 	}
+	return NewPriceSeries(bollingerPeriod*3, rsiPeriod)
+}
+
+// IngestMarketData feeds a market data tick into DefaultSymbol's
+// PriceSeries so RSI/volatility/moving-average/Bollinger calculations have
+// real history to draw on. AnalyzeTradeOpportunity calls this itself;
+// external feeders may also call it directly to warm up the series ahead
+// of the first analysis.
+func (tb *TradingBot) IngestMarketData(marketData core.MarketDataResponse) {
+	series, ok := tb.priceSeries[DefaultSymbol]
+	if !ok {
+		series = NewPriceSeries(bollingerPeriod*3, rsiPeriod)
+		tb.priceSeries[DefaultSymbol] = series
+	}
+
+	timestamp := marketData.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	series.Add(PricePoint{
+		Timestamp: timestamp,
+		Price:     marketData.Price,
+		Volume:    float64(marketData.Volume),
+		High:      marketData.Ask,
+		Low:       marketData.Bid,
+	})
+
+	tb.heartbeat.Notify(DefaultSymbol, marketData.Price)
+}
+
+// NotifyPriceUpdate records a tick for symbol's PriceHeartbeat without
+// feeding DefaultSymbol's PriceSeries, for external feeders (and tests)
+// that want to advance data-freshness tracking independently of the
+// indicator history IngestMarketData maintains.
+func (tb *TradingBot) NotifyPriceUpdate(symbol string, marketData core.MarketDataResponse) {
+	tb.heartbeat.Notify(symbol, marketData.Price)
+}
+
+// SetClock overrides the clock TradingBot's PriceHeartbeat uses to judge
+// data freshness, so tests can fast-forward time instead of sleeping.
+func (tb *TradingBot) SetClock(clock func() time.Time) {
+	tb.heartbeat.SetClock(clock)
+}
+
+// RecordTrade classifies trade as buy- or sell-initiated against bid/ask and
+// folds it into DefaultSymbol's OrderFlowAnalyzer, so AnalyzeTradeOpportunity
+// can factor the latest order-flow imbalance into its prediction.
+func (tb *TradingBot) RecordTrade(trade core.Trade, bid, ask float64) {
+	tb.orderFlow.Record(trade, bid, ask)
+}
+
+// LatestOrderFlow returns DefaultSymbol's in-progress order-flow imbalance
+// interval.
+func (tb *TradingBot) LatestOrderFlow() OrderFlowImbalance {
+	return tb.orderFlow.Latest()
+}
+
+// OrderFlowChartData renders DefaultSymbol's order-flow imbalance history as
+// a core.ChartDataResponse, for handlers that expose it the same way
+// marketdata.ChartGenerator exposes price/P&L chart data.
+func (tb *TradingBot) OrderFlowChartData() core.ChartDataResponse {
+	return tb.orderFlow.ChartData()
 }
 
 // AnalyzeTradeOpportunity analyzes current market conditions and suggests trading opportunities
@@ -86,29 +275,76 @@ func (tb *TradingBot) AnalyzeTradeOpportunity(marketData core.MarketDataResponse
 	currentPrice := marketData.Price
 	volume := float64(marketData.Volume)
 
-	// Calculate technical indicators
+	tb.IngestMarketData(marketData)
+
+	// Calculate technical indicators from the real price series
 	rsi := tb.calculateRSI(marketData)
 	volatility := tb.calculateVolatility(marketData)
 	movingAvgRatio := tb.calculateMovingAverageRatio(marketData)
+	_, bbUpper, bbLower := tb.series().BollingerBands(bollingerPeriod, 2)
+	percentB := 0.5
+	if bbUpper != bbLower {
+		percentB = (currentPrice - bbLower) / (bbUpper - bbLower)
+	}
+	vwap := tb.series().VWAP()
+	atr := tb.series().ATR(tb.positionSizeCfg.Window)
+	pinningRange := atr * tb.positionSizeCfg.Multiplier
 
 	// Simple ML prediction simulation (replacing scikit-learn)
-	priceMovementPrediction := tb.predictPriceMovement(marketData, rsi, volatility, volume)
+	priceMovementPrediction := tb.predictPriceMovement(marketData, rsi, volatility, volume, percentB)
 
 	// Generate trading suggestion
 	suggestion := tb.generateTradingSuggestion(currentPrice, priceMovementPrediction, rsi, volatility, userPositions)
 
-	// Determine predicted direction and confidence
+	// Determine predicted direction and confidence. The decision band widens
+	// in high-volatility regimes (the same BollBandMargin idea bbgo uses for
+	// its maker spread) so a single noisy tick in a wide-band market doesn't
+	// flip the call.
+	decisionBand := 0.01 * (1 + volatility*10)
 	predictedDirection := "NEUTRAL"
 	confidenceScore := 50.0
 
-	if priceMovementPrediction > 0.01 {
+	if priceMovementPrediction > decisionBand {
 		predictedDirection = "BULLISH"
 		confidenceScore = math.Min(math.Abs(priceMovementPrediction)*1000, 95)
-	} else if priceMovementPrediction < -0.01 {
+	} else if priceMovementPrediction < -decisionBand {
 		predictedDirection = "BEARISH"
 		confidenceScore = math.Min(math.Abs(priceMovementPrediction)*1000, 95)
 	}
 
+	// Order-flow imbalance can confirm or override a weak/neutral prediction:
+	// a strong imbalance in one direction still moves the call even when the
+	// price-movement model itself came back neutral, and whenever the final
+	// call is BULLISH or BEARISH it boosts confidence toward how one-sided
+	// the tape has been.
+	imbalance := tb.orderFlow.Latest().ImbalanceRatio
+	if imbalance > orderFlowImbalanceThreshold {
+		predictedDirection = "BULLISH"
+	} else if imbalance < -orderFlowImbalanceThreshold {
+		predictedDirection = "BEARISH"
+	}
+	if predictedDirection == "BULLISH" || predictedDirection == "BEARISH" {
+		confidenceScore = math.Min(confidenceScore+math.Abs(imbalance)*20, 95)
+	}
+
+	riskLevel := tb.assessRiskLevel(userPositions, marketData)
+	cooldownUntil := ""
+
+	dataQuality := tb.heartbeat.Check(DefaultSymbol)
+
+	if state, reason, until := tb.circuitBreaker.Status(); state == CircuitStateOpen {
+		predictedDirection = "HALT"
+		confidenceScore = 0
+		riskLevel = "CRITICAL"
+		suggestion = fmt.Sprintf("Circuit breaker tripped (%s) - trade suggestions are halted until cooldown expires", reason)
+		cooldownUntil = until.Format(time.RFC3339)
+	} else if dataQuality.Stale {
+		predictedDirection = "HALT"
+		confidenceScore = 0
+		riskLevel = "CRITICAL"
+		suggestion = fmt.Sprintf("Market data looks stale (%s) - trade suggestions are halted until fresh data arrives", dataQuality.Reason)
+	}
+
 	analysis := TradeAnalysis{
 		Timestamp:          time.Now().Format(time.RFC3339),
 		AnalysisType:       "trade_suggestion",
@@ -119,17 +355,87 @@ func (tb *TradingBot) AnalyzeTradeOpportunity(marketData core.MarketDataResponse
 			RSI:                rsi,
 			Volatility:         volatility,
 			MovingAverageRatio: movingAvgRatio,
+			BBUpper:            bbUpper,
+			BBLower:            bbLower,
+			PercentB:           percentB,
+			VWAP:               vwap,
+			ATR:                atr,
+			PinningRange:       pinningRange,
 		},
-		Suggestion: suggestion,
-		RiskLevel:  tb.assessRiskLevel(userPositions, marketData),
+		Suggestion:    suggestion,
+		RiskLevel:     riskLevel,
+		CooldownUntil: cooldownUntil,
+		DataQuality:   dataQuality,
 	}
 
 	tb.analysisHistory = append(tb.analysisHistory, analysis)
 	return analysis
 }
 
+// SuggestPositionSize sizes a trade using ATR-pinning: quantity is
+// amount/ATR, clamped so quantity*contract.InitialMargin never exceeds
+// accountBalance. No position is sized (Quantity 0, Skipped true) unless
+// the current bid/ask range clears PositionSizeConfig.MinPriceRange of
+// price - a bar too tight to have traded through isn't worth pinning a
+// stop distance against yet.
+func (tb *TradingBot) SuggestPositionSize(marketData core.MarketDataResponse, amount, accountBalance float64, contract core.Contract) PositionSizeSuggestion {
+	atr := tb.series().ATR(tb.positionSizeCfg.Window)
+	pinningRange := atr * tb.positionSizeCfg.Multiplier
+
+	currentRange := math.Abs(marketData.Ask - marketData.Bid)
+	minRange := tb.positionSizeCfg.MinPriceRange * marketData.Price
+
+	if currentRange < minRange {
+		return PositionSizeSuggestion{
+			ATR:           atr,
+			PinningRange:  pinningRange,
+			CurrentRange:  currentRange,
+			Skipped:       true,
+			SkippedReason: fmt.Sprintf("current bar range %.4f is below the %.4f minimum", currentRange, minRange),
+		}
+	}
+
+	if atr == 0 {
+		return PositionSizeSuggestion{
+			ATR:           atr,
+			PinningRange:  pinningRange,
+			CurrentRange:  currentRange,
+			Skipped:       true,
+			SkippedReason: "ATR is zero - not enough price history yet",
+		}
+	}
+
+	quantity := amount / atr
+	if contract.InitialMargin > 0 && accountBalance > 0 {
+		maxQuantity := accountBalance / contract.InitialMargin
+		if quantity > maxQuantity {
+			quantity = maxQuantity
+		}
+	}
+
+	return PositionSizeSuggestion{
+		Quantity:     quantity,
+		ATR:          atr,
+		PinningRange: pinningRange,
+		CurrentRange: currentRange,
+	}
+}
+
+// ObserveTradeResult folds trade's realized PnL into userID's risk circuit
+// breaker, so repeated losses can halt that user's future suggestions (see
+// riskBreaker).
+func (tb *TradingBot) ObserveTradeResult(userID uint, trade core.Trade, accountBalance float64) {
+	tb.riskBreaker.RecordTrade(userID, trade, accountBalance)
+}
+
+// ResetRiskBreaker manually closes userID's risk circuit breaker ahead of
+// its cooldown expiring.
+func (tb *TradingBot) ResetRiskBreaker(userID uint) {
+	tb.riskBreaker.Reset(userID)
+}
+
 // AnalyzeRisk analyzes risk exposure and provides warnings/suggestions
-func (tb *TradingBot) AnalyzeRisk(userPositions []map[string]interface{}, accountBalance float64) RiskAnalysis {
+func (tb *TradingBot) AnalyzeRisk(userID uint, userPositions []map[string]interface{}, accountBalance float64) RiskAnalysis {
 	totalExposure := 0.0
 	totalUnrealizedPnL := 0.0
 	totalRealizedPnL := 0.0
@@ -196,6 +502,48 @@ func (tb *TradingBot) AnalyzeRisk(userPositions []map[string]interface{}, accoun
 		riskLevel = "MEDIUM"
 	}
 
+	tb.circuitBreaker.Observe(totalUnrealizedPnL+totalRealizedPnL, accountBalance)
+
+	cooldownUntil := ""
+	confidenceScore := 85.0
+	if state, reason, until := tb.circuitBreaker.Status(); state == CircuitStateOpen {
+		riskLevel = "CRITICAL"
+		cooldownUntil = until.Format(time.RFC3339)
+		riskWarnings = append(riskWarnings, fmt.Sprintf("Circuit breaker tripped: %s", reason))
+		recommendations = append(recommendations, "Stop trading until the circuit breaker cooldown expires")
+	}
+
+	if allowed, reason := tb.riskBreaker.CanTrade(userID); !allowed {
+		if riskLevel != "CRITICAL" {
+			riskLevel = "HIGH"
+		}
+		riskWarnings = append(riskWarnings, fmt.Sprintf("Risk circuit breaker tripped: %s", reason))
+		recommendations = append(recommendations, "Stop trading this account until the risk circuit breaker cooldown expires")
+	}
+
+	dataQuality := tb.heartbeat.Check(DefaultSymbol)
+	if dataQuality.Stale {
+		riskLevel = "CRITICAL"
+		confidenceScore = 0
+		riskWarnings = append(riskWarnings, fmt.Sprintf("Market data is stale: %s", dataQuality.Reason))
+		recommendations = append(recommendations, "Wait for fresh market data before acting on this risk assessment")
+	}
+
+	markPrice := 0.0
+	if latest, ok := tb.series().Latest(); ok {
+		markPrice = latest.Price
+	}
+	marginStatus := tb.accountCalc.Compute(accountBalance, userPositions, markPrice)
+
+	if marginStatus.MarginLevel < tb.riskThresholds["liquidation_margin_level"] {
+		riskLevel = "CRITICAL"
+		riskWarnings = append(riskWarnings, fmt.Sprintf("MARGIN_CALL_IMMINENT: margin level %.2f is below the liquidation threshold of %.2f", marginStatus.MarginLevel, tb.riskThresholds["liquidation_margin_level"]))
+		recommendations = append(recommendations, "Deposit funds or close positions immediately to avoid liquidation")
+	} else if marginStatus.MarginLevel < tb.riskThresholds["min_margin_level"] {
+		riskWarnings = append(riskWarnings, fmt.Sprintf("Margin level %.2f is below the %.2f minimum", marginStatus.MarginLevel, tb.riskThresholds["min_margin_level"]))
+		recommendations = append(recommendations, "Reduce position size instead of adding new hedges until margin level recovers")
+	}
+
 	analysis := RiskAnalysis{
 		Timestamp:          time.Now().Format(time.RFC3339),
 		AnalysisType:       "risk_analysis",
@@ -206,7 +554,12 @@ func (tb *TradingBot) AnalyzeRisk(userPositions []map[string]interface{}, accoun
 		TotalRealizedPnL:   totalRealizedPnL,
 		RiskWarnings:       riskWarnings,
 		Recommendations:    recommendations,
-		ConfidenceScore:    85.0,
+		ConfidenceScore:    confidenceScore,
+		CooldownUntil:      cooldownUntil,
+		DataQuality:        dataQuality,
+		MarginLevel:        marginStatus.MarginLevel,
+		UsedMargin:         marginStatus.UsedMargin,
+		FreeMargin:         marginStatus.FreeMargin,
 	}
 
 	tb.analysisHistory = append(tb.analysisHistory, analysis)
@@ -214,12 +567,33 @@ func (tb *TradingBot) AnalyzeRisk(userPositions []map[string]interface{}, accoun
 }
 
 // SuggestHedgingStrategy suggests hedging strategies based on current positions
-func (tb *TradingBot) SuggestHedgingStrategy(userPositions []map[string]interface{}, marketData core.MarketDataResponse) HedgingStrategy {
+func (tb *TradingBot) SuggestHedgingStrategy(userID uint, userPositions []map[string]interface{}, marketData core.MarketDataResponse, accountBalance float64) HedgingStrategy {
+	tb.heartbeat.Notify(DefaultSymbol, marketData.Price)
+	dataQuality := tb.heartbeat.Check(DefaultSymbol)
+
+	if allowed, reason := tb.riskBreaker.CanTrade(userID); !allowed {
+		analysis := HedgingStrategy{
+			Timestamp:    time.Now().Format(time.RFC3339),
+			AnalysisType: "hedging_strategy",
+			HedgingSuggestions: []HedgingSuggestion{{
+				Action:   "HALT",
+				Quantity: 0,
+				Reason:   fmt.Sprintf("Risk circuit breaker tripped (%s) - hedging actions are suspended until cooldown expires", reason),
+				Contract: "ALL",
+			}},
+			ConfidenceScore: 0,
+			DataQuality:     dataQuality,
+		}
+		tb.analysisHistory = append(tb.analysisHistory, analysis)
+		return analysis
+	}
+
 	if len(userPositions) == 0 {
 		return HedgingStrategy{
 			Timestamp:       time.Now().Format(time.RFC3339),
 			AnalysisType:    "hedging_strategy",
 			ConfidenceScore: 0.0,
+			DataQuality:     dataQuality,
 		}
 	}
 
@@ -239,26 +613,94 @@ func (tb *TradingBot) SuggestHedgingStrategy(userPositions []map[string]interfac
 
 	netExposure := netLongExposure - netShortExposure
 	volatility := tb.calculateVolatility(marketData)
+	uncoveredExposure := tb.uncoveredExposure(DefaultSymbol, netExposure)
 
 	var hedgingSuggestions []HedgingSuggestion
 
-	if math.Abs(netExposure) > 0 {
-		hedgeRatio := math.Min(math.Abs(netExposure)*0.5, math.Abs(netExposure)) // 50% hedge
+	if state, reason, _ := tb.circuitBreaker.Status(); state == CircuitStateOpen {
+		hedgingSuggestions = append(hedgingSuggestions, HedgingSuggestion{
+			Action:   "HALT",
+			Quantity: 0,
+			Reason:   fmt.Sprintf("Circuit breaker tripped (%s) - hedging actions are suspended until cooldown expires", reason),
+			Contract: "ALL",
+		})
 
-		if netExposure > 0 { // Net long, suggest short hedge
-			hedgingSuggestions = append(hedgingSuggestions, HedgingSuggestion{
-				Action:   "SELL",
-				Quantity: hedgeRatio,
-				Reason:   "Hedge against long exposure",
-				Contract: "GOLD2024DEC",
-			})
-		} else { // Net short, suggest long hedge
+		analysis := HedgingStrategy{
+			Timestamp:          time.Now().Format(time.RFC3339),
+			AnalysisType:       "hedging_strategy",
+			NetExposure:        netExposure,
+			CurrentVolatility:  volatility,
+			HedgingSuggestions: hedgingSuggestions,
+			ConfidenceScore:    0,
+			DataQuality:        dataQuality,
+		}
+		tb.analysisHistory = append(tb.analysisHistory, analysis)
+		return analysis
+	}
+
+	if dataQuality.Stale {
+		hedgingSuggestions = append(hedgingSuggestions, HedgingSuggestion{
+			Action:   "HALT",
+			Quantity: 0,
+			Reason:   fmt.Sprintf("Market data is stale (%s) - hedging actions are suspended until fresh data arrives", dataQuality.Reason),
+			Contract: "ALL",
+		})
+
+		analysis := HedgingStrategy{
+			Timestamp:          time.Now().Format(time.RFC3339),
+			AnalysisType:       "hedging_strategy",
+			NetExposure:        netExposure,
+			CurrentVolatility:  volatility,
+			HedgingSuggestions: hedgingSuggestions,
+			ConfidenceScore:    0,
+			DataQuality:        dataQuality,
+		}
+		tb.analysisHistory = append(tb.analysisHistory, analysis)
+		return analysis
+	}
+
+	marginStatus := tb.accountCalc.Compute(accountBalance, userPositions, marketData.Price)
+	minMarginLevel := tb.riskThresholds["min_margin_level"]
+
+	if math.Abs(uncoveredExposure) > 0 {
+		hedgeRatio := math.Min(math.Abs(uncoveredExposure)*0.5, math.Abs(uncoveredExposure)) // 50% hedge
+
+		if marginStatus.MarginLevel < minMarginLevel {
+			// A BUY/SELL hedge adds a new position, which would only push
+			// used margin higher while the account is already below
+			// MinMarginLevel - reduce existing exposure instead.
 			hedgingSuggestions = append(hedgingSuggestions, HedgingSuggestion{
-				Action:   "BUY",
+				Action:   "REDUCE_POSITION",
 				Quantity: hedgeRatio,
-				Reason:   "Hedge against short exposure",
-				Contract: "GOLD2024DEC",
+				Reason:   fmt.Sprintf("Margin level %.2f is below the %.2f minimum - reducing exposure instead of adding a new hedge position", marginStatus.MarginLevel, minMarginLevel),
+				Contract: DefaultSymbol,
 			})
+		} else {
+			action := "BUY"
+			reason := "Hedge against short exposure"
+			if uncoveredExposure > 0 { // Net long, suggest short hedge
+				action = "SELL"
+				reason = "Hedge against long exposure"
+			}
+			if tb.coveredPosition[DefaultSymbol] > 0 {
+				reason += " (residual after prior confirmed hedges)"
+			}
+
+			suggestion := HedgingSuggestion{
+				SuggestionID:   tb.nextSuggestionID(),
+				Action:         action,
+				Quantity:       hedgeRatio,
+				Reason:         reason,
+				Contract:       DefaultSymbol,
+				SuggestedPrice: marketData.Price,
+			}
+			hedgingSuggestions = append(hedgingSuggestions, suggestion)
+			tb.pendingHedges[suggestion.SuggestionID] = pendingHedge{
+				Symbol:         DefaultSymbol,
+				Action:         action,
+				SuggestedQty:   hedgeRatio,
+				SuggestedPrice: marketData.Price,
+			}
 		}
 	}
 
@@ -278,6 +720,7 @@ func (tb *TradingBot) SuggestHedgingStrategy(userPositions []map[string]interfac
 		CurrentVolatility:  volatility,
 		HedgingSuggestions: hedgingSuggestions,
 		ConfidenceScore:    80.0,
+		DataQuality:        dataQuality,
 	}
 
 	tb.analysisHistory = append(tb.analysisHistory, analysis)
@@ -286,11 +729,48 @@ func (tb *TradingBot) SuggestHedgingStrategy(userPositions []map[string]interfac
 
 // ChatResponse generates a chat response based on user message and trading context
 func (tb *TradingBot) ChatResponse(userMessage string, context map[string]interface{}) string {
+	response := tb.chatResponse(userMessage, context)
+	tb.recordChatTurn(contextUserID(context), userMessage, response)
+	return response
+}
+
+// contextUserID reads the "user_id" entry ChatResponse's context map
+// carries (set by handlers like handleAIChat from the authenticated user), or 0
+// if it's absent - the zero value simply buckets chat history/risk-breaker
+// state under an anonymous user rather than failing.
+func contextUserID(context map[string]interface{}) uint {
+	if id, ok := context["user_id"].(uint); ok {
+		return id
+	}
+	return 0
+}
+
+// chatResponse is ChatResponse's keyword-based response logic, split out so
+// ChatResponse can wrap it with chat-context bookkeeping without this
+// function needing to record a turn on every one of its early returns.
+func (tb *TradingBot) chatResponse(userMessage string, context map[string]interface{}) string {
 	userMessageLower := strings.ToLower(userMessage)
 
+	if strings.Contains(userMessageLower, "can i trade") || strings.Contains(userMessageLower, "circuit breaker") {
+		state, reason, until := tb.circuitBreaker.Status()
+		if state == CircuitStateOpen {
+			return fmt.Sprintf("No - the circuit breaker is open (%s) and trading is halted until %s.", reason, until.Format(time.RFC3339))
+		}
+		return "Yes - the circuit breaker is closed, so you can trade normally."
+	}
+
 	// Simple keyword-based responses
 	if strings.Contains(userMessageLower, "price") || strings.Contains(userMessageLower, "gold") || strings.Contains(userMessageLower, "current") {
 		if marketData, ok := context["market_data"].(core.MarketDataResponse); ok {
+			// The context's market_data is itself a fresh tick for this
+			// request, so notify the heartbeat before checking staleness -
+			// only a feed that has gone genuinely quiet (repeated prices,
+			// or no tick at all) reports stale here.
+			tb.heartbeat.Notify(DefaultSymbol, marketData.Price)
+			if dataQuality := tb.heartbeat.Check(DefaultSymbol); dataQuality.Stale {
+				return fmt.Sprintf("I can't give you a fresh quote right now - the last tick is %dms old (%s).", dataQuality.AgeMs, dataQuality.Reason)
+			}
+
 			sentiment := "bearish"
 			if marketData.ChangePercent > 0 {
 				sentiment = "bullish"
@@ -306,7 +786,7 @@ func (tb *TradingBot) ChatResponse(userMessage string, context map[string]interf
 			if balance, ok := context["account_balance"].(float64); ok {
 				accountBalance = balance
 			}
-			riskAnalysis := tb.AnalyzeRisk(positions, accountBalance)
+			riskAnalysis := tb.AnalyzeRisk(contextUserID(context), positions, accountBalance)
 			recommendation := "Risk levels are acceptable."
 			if len(riskAnalysis.Recommendations) > 0 {
 				recommendation = riskAnalysis.Recommendations[0]
@@ -331,7 +811,11 @@ func (tb *TradingBot) ChatResponse(userMessage string, context map[string]interf
 	if strings.Contains(userMessageLower, "hedge") || strings.Contains(userMessageLower, "protect") || strings.Contains(userMessageLower, "cover") {
 		if positions, ok := context["positions"].([]map[string]interface{}); ok {
 			if marketData, ok := context["market_data"].(core.MarketDataResponse); ok {
-				hedging := tb.SuggestHedgingStrategy(positions, marketData)
+				accountBalance := 100000.0 // Default
+				if balance, ok := context["account_balance"].(float64); ok {
+					accountBalance = balance
+				}
+				hedging := tb.SuggestHedgingStrategy(contextUserID(context), positions, marketData, accountBalance)
 				if len(hedging.HedgingSuggestions) > 0 {
 					suggestion := hedging.HedgingSuggestions[0]
 					return fmt.Sprintf("For hedging, consider %s %.0f units. Reason: %s", suggestion.Action, suggestion.Quantity, suggestion.Reason)
@@ -341,6 +825,12 @@ func (tb *TradingBot) ChatResponse(userMessage string, context map[string]interf
 		return "No hedging required at this time based on your current positions."
 	}
 
+	if strings.Contains(userMessageLower, "performance") || strings.Contains(userMessageLower, "pnl") {
+		stats := tb.GetProfitStats()
+		return fmt.Sprintf("Today's realized PnL is $%.2f ($%.2f accumulated) from %d confirmed hedge(s) totaling %.1f units, with average slippage of $%.2f versus the suggested price.",
+			stats.DailyRealizedPnL, stats.AccumulatedRealizedPnL, stats.HedgeCount, stats.HedgeVolume, stats.AverageSlippage)
+	}
+
 	if strings.Contains(userMessageLower, "help") || strings.Contains(userMessageLower, "guide") || strings.Contains(userMessageLower, "how") {
 		return "I can help you with: 1) Current gold prices and market analysis, 2) Risk assessment of your positions, 3) Trading suggestions based on technical indicators, 4) Hedging strategies to protect your portfolio. Just ask me about any of these topics!"
 	}
@@ -348,52 +838,51 @@ func (tb *TradingBot) ChatResponse(userMessage string, context map[string]interf
 	return "I'm here to help with your gold derivatives trading. Ask me about current prices, risk analysis, trading suggestions, or hedging strategies. What would you like to know?"
 }
 
-// calculateRSI calculates RSI indicator (simplified version)
+// calculateRSI returns the Wilder-smoothed RSI from DefaultSymbol's
+// PriceSeries (see IngestMarketData).
 func (tb *TradingBot) calculateRSI(marketData core.MarketDataResponse) float64 {
-	// Simplified RSI calculation for demonstration
-	changePercent := marketData.ChangePercent
-	// Convert to 0-100 scale with some randomness for demonstration
-	rsi := 50 + (changePercent*1000) + (rand.Float64()*20 - 10)
-	return math.Max(0, math.Min(100, rsi))
+	return tb.series().RSI()
 }
 
-// calculateVolatility calculates price volatility
+// calculateVolatility returns the rolling standard deviation of returns
+// from DefaultSymbol's PriceSeries, annualized for a daily-bar cadence.
 func (tb *TradingBot) calculateVolatility(marketData core.MarketDataResponse) float64 {
-	// Simplified volatility calculation
-	return math.Abs(marketData.ChangePercent) + rand.Float64()*0.02 + 0.01
+	return tb.series().Volatility(volPeriodsPerYear)
 }
 
-// calculateMovingAverageRatio calculates ratio of current price to moving average
+// calculateMovingAverageRatio returns current price divided by the SMA
+// over bollingerPeriod points of DefaultSymbol's PriceSeries.
 func (tb *TradingBot) calculateMovingAverageRatio(marketData core.MarketDataResponse) float64 {
-	currentPrice := marketData.Price
-	// Simulated moving average
-	maPrice := currentPrice * (rand.Float64()*0.04 + 0.98) // Â±2% from current price
-	return currentPrice / maPrice
+	sma := tb.series().SMA(bollingerPeriod)
+	if sma == 0 {
+		return 1.0
+	}
+	return marketData.Price / sma
 }
 
 // predictPriceMovement predicts price movement using simplified ML logic
-func (tb *TradingBot) predictPriceMovement(marketData core.MarketDataResponse, rsi, volatility, volume float64) float64 {
+func (tb *TradingBot) predictPriceMovement(marketData core.MarketDataResponse, rsi, volatility, volume, percentB float64) float64 {
 	// Simplified prediction logic (replacing scikit-learn)
-	
-	// Features: change_percent, volume_normalized, volatility, rsi_normalized, price_trend
+
+	// Features: change_percent, volume_normalized, volatility, rsi_normalized,
+	// price_trend, %b deviation from the Bollinger mid (0.5)
 	features := []float64{
 		marketData.ChangePercent,
 		volume / 1000.0, // Normalize volume
 		volatility,
-		(rsi - 50) / 50, // Normalize RSI to -1 to 1
+		(rsi - 50) / 50,                          // Normalize RSI to -1 to 1
 		math.Tanh(marketData.ChangePercent * 10), // Price trend indicator
+		(percentB - 0.5) * 2,                     // %b deviation, -1 to 1
 	}
 
 	// Simple weighted prediction (replacing RandomForest)
-	weights := []float64{0.3, 0.1, 0.2, 0.3, 0.1}
+	weights := []float64{0.3, 0.1, 0.2, 0.25, 0.1, 0.05}
 	prediction := 0.0
-	
+
 	for i, feature := range features {
 		prediction += feature * weights[i]
 	}
 
-	// Add some noise and bounds
-	prediction += (rand.Float64() - 0.5) * 0.02
 	return math.Max(-0.1, math.Min(0.1, prediction))
 }
 
@@ -456,4 +945,4 @@ func (tb *TradingBot) assessRiskLevel(positions []map[string]interface{}, market
 	default:
 		return "LOW"
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,128 @@
+package aiassistant
+
+import (
+	"fmt"
+	"time"
+)
+
+// CircuitBreaker states, mirroring the circuit breaker naming rms.RiskManager
+// uses (internal/rms/circuitbreaker.go), though this breaker is in-memory
+// and has no half-open probe step.
+const (
+	CircuitStateClosed = "closed"
+	CircuitStateOpen   = "open"
+)
+
+// CircuitBreakerConfig configures TradingBot's risk circuit breaker.
+type CircuitBreakerConfig struct {
+	// MaxConsecutiveLosses trips the breaker once that many Observe calls
+	// in a row report a worse combined PnL than the one before.
+	MaxConsecutiveLosses int
+	// MaxDrawdownPercent trips the breaker once peak-to-trough drawdown on
+	// combined PnL exceeds this fraction of account balance.
+	MaxDrawdownPercent float64
+	// LossCooldown is how long the breaker stays open once tripped.
+	LossCooldown time.Duration
+}
+
+// DefaultCircuitBreakerConfig returns a 3-consecutive-loss, 20%-drawdown,
+// 15-minute cooldown configuration.
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		MaxConsecutiveLosses: 3,
+		MaxDrawdownPercent:   0.20,
+		LossCooldown:         15 * time.Minute,
+	}
+}
+
+// CircuitBreaker tracks a losing streak and peak-to-trough drawdown on
+// combined realized+unrealized PnL, tripping into a cooldown that forces
+// TradingBot's suggestions to HALT until it expires. Inspired by bbgo's
+// risk/circuitbreaker package.
+type CircuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	state         string
+	reason        string
+	cooldownUntil time.Time
+
+	havePnL           bool
+	lastCombinedPnL   float64
+	consecutiveLosses int
+
+	havePeak bool
+	peakPnL  float64
+}
+
+// NewCircuitBreaker creates a closed CircuitBreaker with the given config.
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{cfg: cfg, state: CircuitStateClosed}
+}
+
+// Observe folds a new combined-PnL sample into the losing-streak and
+// drawdown tracking, tripping the breaker when MaxConsecutiveLosses or
+// MaxDrawdownPercent is breached. It no-ops while the breaker is already
+// open and its cooldown hasn't elapsed.
+func (cb *CircuitBreaker) Observe(combinedPnL, accountBalance float64) {
+	if state, _, _ := cb.Status(); state == CircuitStateOpen {
+		return
+	}
+	if cb.state == CircuitStateOpen {
+		cb.Reset()
+	}
+
+	if cb.havePnL {
+		if combinedPnL < cb.lastCombinedPnL {
+			cb.consecutiveLosses++
+		} else {
+			cb.consecutiveLosses = 0
+		}
+	}
+	cb.lastCombinedPnL = combinedPnL
+	cb.havePnL = true
+
+	if !cb.havePeak || combinedPnL > cb.peakPnL {
+		cb.peakPnL = combinedPnL
+		cb.havePeak = true
+	}
+
+	if cb.consecutiveLosses >= cb.cfg.MaxConsecutiveLosses {
+		cb.Trip(fmt.Sprintf("%d consecutive losing updates", cb.consecutiveLosses))
+		return
+	}
+
+	if accountBalance > 0 {
+		drawdown := (cb.peakPnL - combinedPnL) / accountBalance
+		if drawdown >= cb.cfg.MaxDrawdownPercent {
+			cb.Trip(fmt.Sprintf("drawdown of %.1f%% breached the %.1f%% threshold", drawdown*100, cb.cfg.MaxDrawdownPercent*100))
+		}
+	}
+}
+
+// Trip opens the circuit breaker for LossCooldown, recording reason.
+func (cb *CircuitBreaker) Trip(reason string) {
+	cb.state = CircuitStateOpen
+	cb.reason = reason
+	cb.cooldownUntil = time.Now().Add(cb.cfg.LossCooldown)
+}
+
+// Reset closes the circuit breaker and clears its losing-streak/drawdown
+// tracking.
+func (cb *CircuitBreaker) Reset() {
+	cb.state = CircuitStateClosed
+	cb.reason = ""
+	cb.cooldownUntil = time.Time{}
+	cb.havePnL = false
+	cb.consecutiveLosses = 0
+	cb.havePeak = false
+}
+
+// Status reports the breaker's current state, reason, and (if open) the
+// time its cooldown expires. An open breaker whose cooldown has elapsed
+// reports closed without requiring a further Observe call.
+func (cb *CircuitBreaker) Status() (state, reason string, cooldownUntil time.Time) {
+	if cb.state == CircuitStateOpen && !time.Now().Before(cb.cooldownUntil) {
+		return CircuitStateClosed, "", time.Time{}
+	}
+	return cb.state, cb.reason, cb.cooldownUntil
+}
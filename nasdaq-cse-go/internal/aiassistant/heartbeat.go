@@ -0,0 +1,110 @@
+package aiassistant
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultStaleAfter is how long a symbol can go without a fresh tick before
+// PriceHeartbeat considers it stale.
+const defaultStaleAfter = 30 * time.Second
+
+// maxRepeatedTicks is how many consecutive ticks may report the exact same
+// price before PriceHeartbeat treats the feed as frozen, even though ticks
+// are still arriving within StaleAfter of each other.
+const maxRepeatedTicks = 5
+
+// DataQuality reports the freshness of the market data an analysis was
+// computed from.
+type DataQuality struct {
+	LastUpdate time.Time `json:"last_update"`
+	AgeMs      int64     `json:"age_ms"`
+	Stale      bool      `json:"stale"`
+	Reason     string    `json:"reason,omitempty"`
+}
+
+// PriceHeartbeat tracks, per symbol, when the last tick arrived and whether
+// the feed looks frozen, following bbgo's PriceHeartBeat /
+// priceUpdateTimeout pattern. Its clock is injectable so tests can
+// fast-forward time without sleeping.
+type PriceHeartbeat struct {
+	mu         sync.Mutex
+	staleAfter time.Duration
+	clock      func() time.Time
+
+	lastSeen    map[string]time.Time
+	lastPrice   map[string]float64
+	repeatCount map[string]int
+}
+
+// NewPriceHeartbeat creates a PriceHeartbeat that considers a symbol stale
+// after staleAfter without a tick. staleAfter <= 0 defaults to 30s.
+func NewPriceHeartbeat(staleAfter time.Duration) *PriceHeartbeat {
+	if staleAfter <= 0 {
+		staleAfter = defaultStaleAfter
+	}
+	return &PriceHeartbeat{
+		staleAfter:  staleAfter,
+		clock:       time.Now,
+		lastSeen:    make(map[string]time.Time),
+		lastPrice:   make(map[string]float64),
+		repeatCount: make(map[string]int),
+	}
+}
+
+// SetClock overrides the clock PriceHeartbeat uses to timestamp ticks and
+// judge staleness, for tests that need to fast-forward time.
+func (ph *PriceHeartbeat) SetClock(clock func() time.Time) {
+	ph.mu.Lock()
+	defer ph.mu.Unlock()
+	ph.clock = clock
+}
+
+// Notify records a tick for symbol at price, bumping its repeated-price
+// counter when price is identical to the last tick seen for symbol. The
+// staleness clock (lastSeen) only advances when price is genuinely new: a
+// tick that just repeats the last known price doesn't prove the feed is
+// still alive, so it must not reset how stale Check considers the data -
+// otherwise a caller that re-analyzes the same cached tick on every call
+// would keep the age pinned at ~0 and Stale could never fire.
+func (ph *PriceHeartbeat) Notify(symbol string, price float64) {
+	ph.mu.Lock()
+	defer ph.mu.Unlock()
+
+	if last, ok := ph.lastPrice[symbol]; ok && last == price {
+		ph.repeatCount[symbol]++
+		return
+	}
+	ph.repeatCount[symbol] = 0
+	ph.lastPrice[symbol] = price
+	ph.lastSeen[symbol] = ph.clock()
+}
+
+// Check reports symbol's current DataQuality: stale if no tick has ever
+// been seen, the last tick is older than StaleAfter, or more than
+// maxRepeatedTicks consecutive ticks reported the same price.
+func (ph *PriceHeartbeat) Check(symbol string) DataQuality {
+	ph.mu.Lock()
+	defer ph.mu.Unlock()
+
+	now := ph.clock()
+	last, ok := ph.lastSeen[symbol]
+	if !ok {
+		return DataQuality{Stale: true, Reason: "no market data received yet"}
+	}
+
+	age := now.Sub(last)
+	dq := DataQuality{LastUpdate: last, AgeMs: age.Milliseconds()}
+
+	if age > ph.staleAfter {
+		dq.Stale = true
+		dq.Reason = fmt.Sprintf("no tick in %s, exceeding the %s stale-after threshold", age.Round(time.Millisecond), ph.staleAfter)
+		return dq
+	}
+	if ph.repeatCount[symbol] >= maxRepeatedTicks {
+		dq.Stale = true
+		dq.Reason = fmt.Sprintf("price unchanged for %d consecutive ticks, feed may be frozen", ph.repeatCount[symbol]+1)
+	}
+	return dq
+}
@@ -0,0 +1,169 @@
+package aiassistant
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// CoveredPosition tracks, per contract symbol, how much of TradingBot's net
+// exposure has already been hedged by a confirmed SuggestHedgingStrategy
+// suggestion. SuggestHedgingStrategy sizes new hedges off the residual
+// (netExposure - CoveredPosition[symbol]) so a confirmed hedge is never
+// suggested again, mirroring the covered-position bookkeeping bbgo's
+// CrossExchangeMarketMakingStrategy keeps per symbol.
+type CoveredPosition map[string]float64
+
+// ProfitStats summarizes the realized PnL and hedge activity TradingBot has
+// produced. DailyRealizedPnL resets the first time ConfirmHedge is called on
+// a new calendar day; AccumulatedRealizedPnL never resets.
+type ProfitStats struct {
+	DailyRealizedPnL       float64 `json:"daily_realized_pnl"`
+	AccumulatedRealizedPnL float64 `json:"accumulated_realized_pnl"`
+	HedgeVolume            float64 `json:"hedge_volume"`
+	HedgeCount             int     `json:"hedge_count"`
+	AverageSlippage        float64 `json:"average_slippage"`
+	StatsDay               string  `json:"stats_day,omitempty"`
+}
+
+// pendingHedge is the bookkeeping TradingBot keeps between suggesting a
+// hedge and ConfirmHedge acknowledging how much of it actually filled.
+type pendingHedge struct {
+	Symbol         string  `json:"symbol"`
+	Action         string  `json:"action"`
+	SuggestedQty   float64 `json:"suggested_qty"`
+	SuggestedPrice float64 `json:"suggested_price"`
+}
+
+// tradingBotState is the subset of TradingBot persisted to StatePath.
+type tradingBotState struct {
+	CoveredPosition CoveredPosition         `json:"covered_position"`
+	ProfitStats     ProfitStats             `json:"profit_stats"`
+	PendingHedges   map[string]pendingHedge `json:"pending_hedges"`
+	HedgeSeq        int                     `json:"hedge_seq"`
+}
+
+// loadState reads TradingBot's persisted covered-position and profit-stats
+// state from statePath. A missing file is not an error - the bot simply
+// starts with no covered positions and zeroed stats.
+func loadState(statePath string) tradingBotState {
+	state := tradingBotState{
+		CoveredPosition: make(CoveredPosition),
+		PendingHedges:   make(map[string]pendingHedge),
+	}
+	if statePath == "" {
+		return state
+	}
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return tradingBotState{CoveredPosition: make(CoveredPosition), PendingHedges: make(map[string]pendingHedge)}
+	}
+	if state.CoveredPosition == nil {
+		state.CoveredPosition = make(CoveredPosition)
+	}
+	if state.PendingHedges == nil {
+		state.PendingHedges = make(map[string]pendingHedge)
+	}
+	return state
+}
+
+// saveState persists TradingBot's covered-position and profit-stats state
+// to StatePath. It is a no-op when no path was configured on NewTradingBot.
+func (tb *TradingBot) saveState() error {
+	if tb.statePath == "" {
+		return nil
+	}
+	state := tradingBotState{
+		CoveredPosition: tb.coveredPosition,
+		ProfitStats:     tb.profitStats,
+		PendingHedges:   tb.pendingHedges,
+		HedgeSeq:        tb.hedgeSeq,
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal trading bot state: %w", err)
+	}
+	if err := os.WriteFile(tb.statePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write trading bot state file %s: %w", tb.statePath, err)
+	}
+	return nil
+}
+
+// uncoveredExposure returns netExposure less the portion of symbol's
+// exposure already covered by confirmed hedges, preserving netExposure's
+// sign so callers can still tell long exposure from short.
+func (tb *TradingBot) uncoveredExposure(symbol string, netExposure float64) float64 {
+	covered := tb.coveredPosition[symbol]
+	if covered <= 0 {
+		return netExposure
+	}
+	if netExposure > 0 {
+		return math.Max(0, netExposure-covered)
+	}
+	if netExposure < 0 {
+		return -math.Max(0, -netExposure-covered)
+	}
+	return 0
+}
+
+// nextSuggestionID returns a unique, monotonically increasing ID for a new
+// hedging suggestion, used later to look up the pendingHedge in ConfirmHedge.
+func (tb *TradingBot) nextSuggestionID() string {
+	tb.hedgeSeq++
+	return fmt.Sprintf("hedge-%d", tb.hedgeSeq)
+}
+
+// ConfirmHedge acknowledges that suggestionID (as returned on a
+// HedgingSuggestion by SuggestHedgingStrategy) filled for filledQty at
+// filledPrice. It updates CoveredPosition so the next SuggestHedgingStrategy
+// call only sizes hedges off the residual exposure, and folds the fill into
+// GetProfitStats: hedge volume/count always advance, and the price
+// improvement versus the suggested price (a positive number means the fill
+// was better than suggested) is recorded as both slippage and realized PnL.
+func (tb *TradingBot) ConfirmHedge(suggestionID string, filledQty, filledPrice float64) error {
+	pending, ok := tb.pendingHedges[suggestionID]
+	if !ok {
+		return fmt.Errorf("no pending hedge suggestion with id %q", suggestionID)
+	}
+	delete(tb.pendingHedges, suggestionID)
+
+	tb.coveredPosition[pending.Symbol] += math.Abs(filledQty)
+
+	priceImprovement := pending.SuggestedPrice - filledPrice
+	if pending.Action == "SELL" {
+		priceImprovement = filledPrice - pending.SuggestedPrice
+	}
+	realizedPnL := priceImprovement * math.Abs(filledQty)
+
+	tb.rollProfitStatsDay()
+	tb.profitStats.DailyRealizedPnL += realizedPnL
+	tb.profitStats.AccumulatedRealizedPnL += realizedPnL
+	tb.profitStats.HedgeVolume += math.Abs(filledQty)
+
+	totalSlippage := tb.profitStats.AverageSlippage * float64(tb.profitStats.HedgeCount)
+	tb.profitStats.HedgeCount++
+	tb.profitStats.AverageSlippage = (totalSlippage + math.Abs(priceImprovement)) / float64(tb.profitStats.HedgeCount)
+
+	return tb.saveState()
+}
+
+// rollProfitStatsDay zeroes DailyRealizedPnL the first time it is called on
+// a new calendar day relative to the last recorded StatsDay.
+func (tb *TradingBot) rollProfitStatsDay() {
+	today := time.Now().Format("2006-01-02")
+	if tb.profitStats.StatsDay != today {
+		tb.profitStats.StatsDay = today
+		tb.profitStats.DailyRealizedPnL = 0
+	}
+}
+
+// GetProfitStats returns a snapshot of TradingBot's accumulated hedge and
+// realized PnL performance.
+func (tb *TradingBot) GetProfitStats() ProfitStats {
+	return tb.profitStats
+}
@@ -0,0 +1,174 @@
+package aiassistant
+
+import (
+	"sync"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"gorm.io/gorm"
+)
+
+// defaultOrderFlowInterval and defaultOrderFlowCapacity parameterize the
+// OrderFlowAnalyzer TradingBot keeps for DefaultSymbol.
+const (
+	defaultOrderFlowInterval = time.Minute
+	defaultOrderFlowCapacity = 60
+)
+
+// orderFlowImbalanceThreshold gates how strong an interval's imbalance ratio
+// must be before AnalyzeTradeOpportunity lets it flip PredictedDirection.
+const orderFlowImbalanceThreshold = 0.2
+
+// OrderFlowImbalance is one interval's buy/sell aggressor classification:
+// BuyVolume/SellVolume are that interval's totals, CVD is the cumulative
+// signed volume (buy-sell) across every interval up to and including this
+// one, and ImbalanceRatio is (buy-sell)/(buy+sell) for this interval alone.
+type OrderFlowImbalance struct {
+	IntervalStart  time.Time
+	BuyVolume      float64
+	SellVolume     float64
+	CVD            float64
+	ImbalanceRatio float64
+}
+
+// Persist writes ofi as a core.OrderFlowSnapshot row for contractID.
+func (ofi OrderFlowImbalance) Persist(db *gorm.DB, contractID uint) error {
+	snapshot := core.OrderFlowSnapshot{
+		ContractID:     contractID,
+		IntervalStart:  ofi.IntervalStart,
+		BuyVolume:      ofi.BuyVolume,
+		SellVolume:     ofi.SellVolume,
+		CVD:            ofi.CVD,
+		ImbalanceRatio: ofi.ImbalanceRatio,
+	}
+	return db.Create(&snapshot).Error
+}
+
+// OrderFlowAnalyzer consumes a trade stream and computes tick-by-tick
+// buy/sell aggressor imbalance bucketed into fixed-width intervals: a trade
+// is buy-initiated if its price is at or above the prevailing ask and
+// sell-initiated if at or below the prevailing bid. Completed intervals are
+// kept in a capacity-bounded history alongside a running CVD, so
+// TradingBot can read the latest imbalance without waiting on an interval
+// rollover.
+type OrderFlowAnalyzer struct {
+	mu       sync.Mutex
+	interval time.Duration
+	capacity int
+
+	cvd     float64
+	current OrderFlowImbalance
+	history []OrderFlowImbalance
+}
+
+// NewOrderFlowAnalyzer creates an OrderFlowAnalyzer bucketing trades into
+// interval-wide windows and retaining up to capacity completed intervals.
+func NewOrderFlowAnalyzer(interval time.Duration, capacity int) *OrderFlowAnalyzer {
+	return &OrderFlowAnalyzer{
+		interval: interval,
+		capacity: capacity,
+	}
+}
+
+// Record classifies trade against the bid/ask prevailing when it printed and
+// folds it into the current interval, archiving the prior interval first if
+// trade.TradeTime has rolled into a new bucket. A trade that's neither
+// buy-initiated (Price >= ask) nor sell-initiated (Price <= bid) - one that
+// printed strictly inside the spread - is folded into the bucket without
+// moving either side's volume.
+func (ofa *OrderFlowAnalyzer) Record(trade core.Trade, bid, ask float64) {
+	ofa.mu.Lock()
+	defer ofa.mu.Unlock()
+
+	tradeTime := trade.TradeTime
+	if tradeTime.IsZero() {
+		tradeTime = time.Now()
+	}
+	bucket := tradeTime.Truncate(ofa.interval)
+
+	if ofa.current.IntervalStart.IsZero() {
+		ofa.current.IntervalStart = bucket
+	} else if bucket.After(ofa.current.IntervalStart) {
+		ofa.archiveLocked()
+		ofa.current.IntervalStart = bucket
+	}
+
+	switch {
+	case trade.Price >= ask:
+		ofa.current.BuyVolume += trade.Quantity
+	case trade.Price <= bid:
+		ofa.current.SellVolume += trade.Quantity
+	}
+
+	total := ofa.current.BuyVolume + ofa.current.SellVolume
+	if total > 0 {
+		ofa.current.ImbalanceRatio = (ofa.current.BuyVolume - ofa.current.SellVolume) / total
+	} else {
+		ofa.current.ImbalanceRatio = 0
+	}
+	ofa.current.CVD = ofa.cvd + ofa.current.BuyVolume - ofa.current.SellVolume
+}
+
+// archiveLocked pushes the in-progress interval onto history, trimmed to
+// capacity, and rolls its buy/sell-adjusted volume into the running CVD so
+// the next interval starts counting from zero. Callers must hold ofa.mu.
+func (ofa *OrderFlowAnalyzer) archiveLocked() {
+	if ofa.current.IntervalStart.IsZero() {
+		return
+	}
+	ofa.cvd = ofa.current.CVD
+	ofa.history = append(ofa.history, ofa.current)
+	if len(ofa.history) > ofa.capacity {
+		ofa.history = ofa.history[len(ofa.history)-ofa.capacity:]
+	}
+	ofa.current = OrderFlowImbalance{}
+}
+
+// Latest returns the in-progress interval's imbalance snapshot, or a zero
+// value if no trade has been recorded yet.
+func (ofa *OrderFlowAnalyzer) Latest() OrderFlowImbalance {
+	ofa.mu.Lock()
+	defer ofa.mu.Unlock()
+	return ofa.current
+}
+
+// History returns every completed interval still within capacity, oldest
+// first.
+func (ofa *OrderFlowAnalyzer) History() []OrderFlowImbalance {
+	ofa.mu.Lock()
+	defer ofa.mu.Unlock()
+	history := make([]OrderFlowImbalance, len(ofa.history))
+	copy(history, ofa.history)
+	return history
+}
+
+// ChartData renders the completed interval history (plus the in-progress
+// interval) as a core.ChartDataResponse: Price carries each interval's
+// ImbalanceRatio and Volume carries its total buy+sell volume, matching how
+// ChartGenerator.CreatePnLChartData reuses ChartDataPoint.Price for a
+// non-price series.
+func (ofa *OrderFlowAnalyzer) ChartData() core.ChartDataResponse {
+	ofa.mu.Lock()
+	defer ofa.mu.Unlock()
+
+	points := make([]core.ChartDataPoint, 0, len(ofa.history)+1)
+	for _, snapshot := range ofa.history {
+		points = append(points, core.ChartDataPoint{
+			Timestamp: snapshot.IntervalStart,
+			Price:     snapshot.ImbalanceRatio,
+			Volume:    int64(snapshot.BuyVolume + snapshot.SellVolume),
+		})
+	}
+	if !ofa.current.IntervalStart.IsZero() {
+		points = append(points, core.ChartDataPoint{
+			Timestamp: ofa.current.IntervalStart,
+			Price:     ofa.current.ImbalanceRatio,
+			Volume:    int64(ofa.current.BuyVolume + ofa.current.SellVolume),
+		})
+	}
+
+	return core.ChartDataResponse{
+		Data: points,
+		Type: "order_flow_imbalance",
+	}
+}
@@ -0,0 +1,92 @@
+package aiassistant
+
+import (
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/persistence"
+)
+
+// chatContextLimit caps how many prior turns ChatResponse keeps per user,
+// so a long-lived bot's chat context doesn't grow unbounded.
+const chatContextLimit = 20
+
+// ChatTurn is one remembered exchange with a user, kept so ChatResponse can
+// read recent history back into context on a later call.
+type ChatTurn struct {
+	Message   string    `json:"message"`
+	Response  string    `json:"response"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// persistentState is the subset of TradingBot's state a persistence.Store
+// backs across restarts: rolling technical-indicator windows, the AI
+// analysis history, and per-user chat context. SaveTagged/LoadTagged
+// discover the three fields below via their `persistence` tags rather than
+// TradingBot hard-coding one Save/Load call per field.
+type persistentState struct {
+	PriceSeries     map[string]PriceSeriesSnapshot `persistence:"trading_bot_price_series"`
+	AnalysisHistory []interface{}                  `persistence:"trading_bot_analysis_history"`
+	ChatContext     map[uint][]ChatTurn            `persistence:"trading_bot_chat_context"`
+}
+
+// loadPersistentState restores priceSeries/analysisHistory/chatContext from
+// store, leaving TradingBot's zero-value in-memory state in place for any
+// field that has never been saved.
+func (tb *TradingBot) loadPersistentState() {
+	state := persistentState{}
+	if err := persistence.LoadTagged(tb.persistStore, &state); err != nil {
+		return
+	}
+
+	for symbol, snap := range state.PriceSeries {
+		tb.priceSeries[symbol] = RestorePriceSeries(snap)
+	}
+	if state.AnalysisHistory != nil {
+		tb.analysisHistory = state.AnalysisHistory
+	}
+	if state.ChatContext != nil {
+		tb.chatContext = state.ChatContext
+	}
+}
+
+// PersistState snapshots priceSeries/analysisHistory/chatContext and saves
+// them to tb's persistence.Store. It is a no-op when NewTradingBot was used
+// instead of NewTradingBotWithStore.
+func (tb *TradingBot) PersistState() error {
+	if tb.persistStore == nil {
+		return nil
+	}
+
+	seriesSnapshots := make(map[string]PriceSeriesSnapshot, len(tb.priceSeries))
+	for symbol, series := range tb.priceSeries {
+		seriesSnapshots[symbol] = series.Snapshot()
+	}
+
+	state := persistentState{
+		PriceSeries:     seriesSnapshots,
+		AnalysisHistory: tb.analysisHistory,
+		ChatContext:     tb.chatContext,
+	}
+	return persistence.SaveTagged(tb.persistStore, &state)
+}
+
+// recordChatTurn appends message/response to userID's chat context,
+// trimming it to chatContextLimit, and persists it.
+func (tb *TradingBot) recordChatTurn(userID uint, message, response string) {
+	turns := append(tb.chatContext[userID], ChatTurn{
+		Message:   message,
+		Response:  response,
+		Timestamp: time.Now(),
+	})
+	if len(turns) > chatContextLimit {
+		turns = turns[len(turns)-chatContextLimit:]
+	}
+	tb.chatContext[userID] = turns
+
+	_ = tb.PersistState()
+}
+
+// ChatHistory returns userID's remembered chat turns, oldest first.
+func (tb *TradingBot) ChatHistory(userID uint) []ChatTurn {
+	return tb.chatContext[userID]
+}
@@ -0,0 +1,337 @@
+package aiassistant
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// PricePoint is a single tick ingested into a PriceSeries. High and Low
+// default to Price when a feeder has no better bar bounds to offer, but
+// IngestMarketData fills them from the tick's bid/ask so ATR sees a real
+// intra-tick range rather than a single point.
+type PricePoint struct {
+	Timestamp time.Time
+	Price     float64
+	Volume    float64
+	High      float64
+	Low       float64
+}
+
+// PriceSeries is a fixed-capacity ring buffer of PricePoints for one
+// contract symbol, plus the running state a Wilder-smoothed RSI needs
+// (avgGain/avgLoss carry forward across the whole history, not just the
+// points still held in the buffer).
+type PriceSeries struct {
+	mu        sync.Mutex
+	capacity  int
+	rsiPeriod int
+
+	points []PricePoint
+
+	rsiInit bool
+	avgGain float64
+	avgLoss float64
+}
+
+// NewPriceSeries creates a PriceSeries holding up to capacity points and
+// computing RSI over rsiPeriod periods.
+func NewPriceSeries(capacity, rsiPeriod int) *PriceSeries {
+	return &PriceSeries{
+		capacity:  capacity,
+		rsiPeriod: rsiPeriod,
+		points:    make([]PricePoint, 0, capacity),
+	}
+}
+
+// Add appends a PricePoint, rolling Wilder's smoothed average gain/loss
+// forward and trimming the buffer to capacity.
+func (ps *PriceSeries) Add(p PricePoint) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if p.High == 0 {
+		p.High = p.Price
+	}
+	if p.Low == 0 {
+		p.Low = p.Price
+	}
+
+	if len(ps.points) > 0 {
+		prev := ps.points[len(ps.points)-1].Price
+		change := p.Price - prev
+
+		gain, loss := 0.0, 0.0
+		if change > 0 {
+			gain = change
+		} else {
+			loss = -change
+		}
+
+		n := float64(ps.rsiPeriod)
+		if !ps.rsiInit {
+			ps.avgGain = gain
+			ps.avgLoss = loss
+			ps.rsiInit = true
+		} else {
+			ps.avgGain = (ps.avgGain*(n-1) + gain) / n
+			ps.avgLoss = (ps.avgLoss*(n-1) + loss) / n
+		}
+	}
+
+	ps.points = append(ps.points, p)
+	if len(ps.points) > ps.capacity {
+		ps.points = ps.points[len(ps.points)-ps.capacity:]
+	}
+}
+
+// Len returns the number of points currently held.
+func (ps *PriceSeries) Len() int {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return len(ps.points)
+}
+
+// Latest returns the most recently added point, or false if the series is
+// empty.
+func (ps *PriceSeries) Latest() (PricePoint, bool) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if len(ps.points) == 0 {
+		return PricePoint{}, false
+	}
+	return ps.points[len(ps.points)-1], true
+}
+
+// RSI returns the Wilder-smoothed Relative Strength Index: 100 - 100/(1+RS)
+// where RS = avgGain/avgLoss. A flat series (no gains or losses yet) reads
+// as neutral (50); an all-gain series saturates at 100.
+func (ps *PriceSeries) RSI() float64 {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.avgLoss == 0 {
+		if ps.avgGain == 0 {
+			return 50
+		}
+		return 100
+	}
+	rs := ps.avgGain / ps.avgLoss
+	return 100 - 100/(1+rs)
+}
+
+// Volatility returns the rolling standard deviation of simple returns over
+// the buffered points, annualized by sqrt(periodsPerYear).
+func (ps *PriceSeries) Volatility(periodsPerYear float64) float64 {
+	ps.mu.Lock()
+	returns := ps.returnsLocked()
+	ps.mu.Unlock()
+
+	if len(returns) < 2 {
+		return 0
+	}
+	return stddev(returns) * math.Sqrt(periodsPerYear)
+}
+
+// ATR returns the Average True Range over the last window bars: the simple
+// average of True Range = max(high-low, |high-prevClose|, |low-prevClose|)
+// computed across consecutive buffered points. Unlike pkg/indicator's
+// close-only ATR, this uses each bar's real High/Low (see PricePoint), so it
+// reflects the bid/ask range a tick actually traded through rather than
+// approximating it from the close alone.
+func (ps *PriceSeries) ATR(window int) float64 {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if len(ps.points) < 2 {
+		return 0
+	}
+
+	points := ps.points
+	if window > 0 && window+1 < len(points) {
+		points = points[len(points)-(window+1):]
+	}
+
+	sum := 0.0
+	for i := 1; i < len(points); i++ {
+		high, low, prevClose := points[i].High, points[i].Low, points[i-1].Price
+		tr := math.Max(high-low, math.Max(math.Abs(high-prevClose), math.Abs(low-prevClose)))
+		sum += tr
+	}
+	return sum / float64(len(points)-1)
+}
+
+// SMA returns the simple moving average of the last period prices (or of
+// every buffered price, if fewer than period are available).
+func (ps *PriceSeries) SMA(period int) float64 {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	prices := ps.pricesLocked(period)
+	if len(prices) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, p := range prices {
+		sum += p
+	}
+	return sum / float64(len(prices))
+}
+
+// EMA returns the exponential moving average of the buffered prices using
+// a standard 2/(period+1) smoothing factor.
+func (ps *PriceSeries) EMA(period int) float64 {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	prices := ps.pricesLocked(0)
+	if len(prices) == 0 {
+		return 0
+	}
+
+	alpha := 2.0 / (float64(period) + 1)
+	ema := prices[0]
+	for _, p := range prices[1:] {
+		ema = alpha*p + (1-alpha)*ema
+	}
+	return ema
+}
+
+// BollingerBands returns the SMA-based mid, upper and lower bands over the
+// last period prices: mid +/- k*stddev.
+func (ps *PriceSeries) BollingerBands(period int, k float64) (mid, upper, lower float64) {
+	ps.mu.Lock()
+	prices := ps.pricesLocked(period)
+	ps.mu.Unlock()
+
+	if len(prices) == 0 {
+		return 0, 0, 0
+	}
+
+	sum := 0.0
+	for _, p := range prices {
+		sum += p
+	}
+	mid = sum / float64(len(prices))
+
+	sd := stddev(prices)
+	upper = mid + k*sd
+	lower = mid - k*sd
+	return mid, upper, lower
+}
+
+// VWAP returns the volume-weighted average price over every buffered
+// point, falling back to the simple average when no volume was recorded.
+func (ps *PriceSeries) VWAP() float64 {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if len(ps.points) == 0 {
+		return 0
+	}
+
+	totalValue, totalVolume := 0.0, 0.0
+	for _, p := range ps.points {
+		totalValue += p.Price * p.Volume
+		totalVolume += p.Volume
+	}
+	if totalVolume == 0 {
+		sum := 0.0
+		for _, p := range ps.points {
+			sum += p.Price
+		}
+		return sum / float64(len(ps.points))
+	}
+	return totalValue / totalVolume
+}
+
+// pricesLocked returns the last n buffered prices (or all of them if
+// n <= 0 or there are fewer than n). Callers must hold ps.mu.
+func (ps *PriceSeries) pricesLocked(n int) []float64 {
+	points := ps.points
+	if n > 0 && n < len(points) {
+		points = points[len(points)-n:]
+	}
+	prices := make([]float64, len(points))
+	for i, p := range points {
+		prices[i] = p.Price
+	}
+	return prices
+}
+
+// returnsLocked returns simple period-over-period returns for the buffered
+// prices. Callers must hold ps.mu.
+func (ps *PriceSeries) returnsLocked() []float64 {
+	if len(ps.points) < 2 {
+		return nil
+	}
+	returns := make([]float64, 0, len(ps.points)-1)
+	for i := 1; i < len(ps.points); i++ {
+		prev := ps.points[i-1].Price
+		if prev == 0 {
+			continue
+		}
+		returns = append(returns, (ps.points[i].Price-prev)/prev)
+	}
+	return returns
+}
+
+// PriceSeriesSnapshot is PriceSeries's state rendered into exported fields
+// so it can round-trip through persistence.Store, which needs JSON-visible
+// fields to marshal - PriceSeries itself keeps everything unexported behind
+// its mutex.
+type PriceSeriesSnapshot struct {
+	Capacity  int          `json:"capacity"`
+	RSIPeriod int          `json:"rsi_period"`
+	Points    []PricePoint `json:"points"`
+	RSIInit   bool         `json:"rsi_init"`
+	AvgGain   float64      `json:"avg_gain"`
+	AvgLoss   float64      `json:"avg_loss"`
+}
+
+// Snapshot renders ps's state as a PriceSeriesSnapshot.
+func (ps *PriceSeries) Snapshot() PriceSeriesSnapshot {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	points := make([]PricePoint, len(ps.points))
+	copy(points, ps.points)
+	return PriceSeriesSnapshot{
+		Capacity:  ps.capacity,
+		RSIPeriod: ps.rsiPeriod,
+		Points:    points,
+		RSIInit:   ps.rsiInit,
+		AvgGain:   ps.avgGain,
+		AvgLoss:   ps.avgLoss,
+	}
+}
+
+// RestorePriceSeries rebuilds a PriceSeries from a snapshot previously
+// produced by Snapshot.
+func RestorePriceSeries(snap PriceSeriesSnapshot) *PriceSeries {
+	ps := NewPriceSeries(snap.Capacity, snap.RSIPeriod)
+	ps.points = append(ps.points, snap.Points...)
+	ps.rsiInit = snap.RSIInit
+	ps.avgGain = snap.AvgGain
+	ps.avgLoss = snap.AvgLoss
+	return ps
+}
+
+// stddev returns the population standard deviation of values.
+func stddev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(values))
+	return math.Sqrt(variance)
+}
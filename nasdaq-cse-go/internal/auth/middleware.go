@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAuth returns gin middleware that validates the "Authorization:
+// Bearer <token>" header against service and populates c.Set("user_id", ...)
+// for downstream handlers, replacing the simulator's previous
+// default-to-1 query-param convention.
+func RequireAuth(service *Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := verifyFromHeader(service, c.GetHeader("Authorization"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.Set("user_id", userID)
+		c.Next()
+	}
+}
+
+// VerifyWebSocketUpgrade authorizes a WebSocket upgrade request, reading
+// the access token from the Sec-WebSocket-Protocol header (the convention
+// browser WebSocket clients use to carry a bearer token, since they can't
+// set arbitrary headers) or, failing that, a "token" query parameter.
+func VerifyWebSocketUpgrade(service *Service, r *http.Request) (uint, error) {
+	if protocol := r.Header.Get("Sec-WebSocket-Protocol"); protocol != "" {
+		return service.VerifyAccessToken(protocol)
+	}
+	return service.VerifyAccessToken(r.URL.Query().Get("token"))
+}
+
+func verifyFromHeader(service *Service, header string) (uint, error) {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return 0, ErrMissingToken
+	}
+	return service.VerifyAccessToken(strings.TrimPrefix(header, prefix))
+}
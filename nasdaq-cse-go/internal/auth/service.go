@@ -0,0 +1,175 @@
+// Package auth provides JWT-based login for multi-user deployments,
+// replacing the simulator's previous default-to-user-1 query-param
+// convention with bcrypt-hashed credentials and HMAC-signed tokens.
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCredentials is returned by Login when the username is unknown
+// or the password doesn't match.
+var ErrInvalidCredentials = errors.New("auth: invalid username or password")
+
+// ErrMissingToken is returned when a request has no (or a malformed)
+// Authorization header.
+var ErrMissingToken = errors.New("auth: missing or malformed bearer token")
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// Claims is the JWT payload for both access and refresh tokens,
+// distinguished by TokenType.
+type Claims struct {
+	UserID    uint   `json:"user_id"`
+	TokenType string `json:"token_type"` // "access" or "refresh"
+	jwt.RegisteredClaims
+}
+
+// TokenPair is the access/refresh token response Login and Refresh return.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // access token TTL, in seconds
+}
+
+// Service authenticates users against storage.DatabaseManager's User table
+// and issues/validates HMAC-signed JWTs.
+type Service struct {
+	db        *gorm.DB
+	jwtSecret []byte
+}
+
+// NewService creates a Service. jwtSecret signs every issued token; losing
+// or rotating it invalidates all outstanding tokens.
+func NewService(db *gorm.DB, jwtSecret string) *Service {
+	return &Service{db: db, jwtSecret: []byte(jwtSecret)}
+}
+
+// Login verifies username/password against the stored bcrypt hash and
+// issues a new TokenPair on success.
+func (s *Service) Login(username, password string) (TokenPair, error) {
+	var user core.User
+	if err := s.db.Where("username = ?", username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return TokenPair{}, ErrInvalidCredentials
+		}
+		return TokenPair{}, fmt.Errorf("auth: failed to look up user: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return TokenPair{}, ErrInvalidCredentials
+	}
+	if !user.IsActive {
+		return TokenPair{}, ErrInvalidCredentials
+	}
+
+	return s.issueTokenPair(user.ID)
+}
+
+// Refresh validates a refresh token and issues a new TokenPair, rejecting
+// access tokens used in its place.
+func (s *Service) Refresh(refreshToken string) (TokenPair, error) {
+	claims, err := s.parse(refreshToken)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if claims.TokenType != "refresh" {
+		return TokenPair{}, errors.New("auth: token is not a refresh token")
+	}
+	return s.issueTokenPair(claims.UserID)
+}
+
+// VerifyAccessToken validates an access token and returns the user ID it
+// was issued for, rejecting refresh tokens used in its place.
+func (s *Service) VerifyAccessToken(token string) (uint, error) {
+	claims, err := s.parse(token)
+	if err != nil {
+		return 0, err
+	}
+	if claims.TokenType != "access" {
+		return 0, errors.New("auth: token is not an access token")
+	}
+	return claims.UserID, nil
+}
+
+// CreateUser hashes password and inserts a new User row, for the one-time
+// admin setup flow.
+func (s *Service) CreateUser(username, email, password string) (*core.User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("auth: failed to hash password: %w", err)
+	}
+
+	user := &core.User{
+		Username:        username,
+		Email:           email,
+		PasswordHash:    string(hash),
+		AccountBalance:  100000.0,
+		MarginAvailable: 100000.0,
+		IsActive:        true,
+	}
+	if err := s.db.Create(user).Error; err != nil {
+		return nil, fmt.Errorf("auth: failed to create user: %w", err)
+	}
+	return user, nil
+}
+
+func (s *Service) issueTokenPair(userID uint) (TokenPair, error) {
+	access, err := s.sign(userID, "access", accessTokenTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refresh, err := s.sign(userID, "refresh", refreshTokenTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    int(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (s *Service) sign(userID uint, tokenType string, ttl time.Duration) (string, error) {
+	claims := Claims{
+		UserID:    userID,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(s.jwtSecret)
+	if err != nil {
+		return "", fmt.Errorf("auth: failed to sign %s token: %w", tokenType, err)
+	}
+	return signed, nil
+}
+
+func (s *Service) parse(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("auth: unexpected signing method %v", t.Header["alg"])
+		}
+		return s.jwtSecret, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("auth: invalid token: %w", err)
+	}
+	if !token.Valid {
+		return nil, errors.New("auth: invalid token")
+	}
+	return claims, nil
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrSetupAlreadyComplete is returned by CompleteSetup once the one-time
+// setup token has already been consumed.
+var ErrSetupAlreadyComplete = errors.New("auth: setup already complete")
+
+// ErrInvalidSetupToken is returned by CompleteSetup when the supplied
+// token doesn't match the one printed to stdout at startup.
+var ErrInvalidSetupToken = errors.New("auth: invalid setup token")
+
+// SetupGate gates first-run admin user creation behind a one-time random
+// token printed to stdout, so the setup endpoint can't be driven by anyone
+// who merely reaches the HTTP port before an operator reads the console.
+type SetupGate struct {
+	mutex     sync.Mutex
+	token     string
+	completed bool
+}
+
+// NewSetupGate generates a random setup token and returns the gate along
+// with the token to print to stdout.
+func NewSetupGate() (*SetupGate, string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", fmt.Errorf("auth: failed to generate setup token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	return &SetupGate{token: token}, token, nil
+}
+
+// CompleteSetup consumes the setup token if it matches and setup hasn't
+// already run, otherwise returns an error.
+func (g *SetupGate) CompleteSetup(token string) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.completed {
+		return ErrSetupAlreadyComplete
+	}
+	if token != g.token {
+		return ErrInvalidSetupToken
+	}
+	g.completed = true
+	return nil
+}
@@ -0,0 +1,181 @@
+// Package backtest replays historical core.MarketData and core.Trade
+// records through an aiassistant.TradingBot, one simulated account at a
+// time, and reports the resulting PnL. It plays the AI-assistant
+// counterpart to pkg/backtest, which drives a pkg/strategy.Strategy against
+// replayed ticks instead.
+package backtest
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/aiassistant"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+)
+
+// MarketDataSource supplies historical core.MarketData ticks for symbol
+// between start and end, ordered by Timestamp.
+type MarketDataSource interface {
+	Query(symbol string, start, end time.Time) ([]core.MarketData, error)
+}
+
+// TradeSource supplies historical core.Trade fills for symbol between start
+// and end, ordered by TradeTime.
+type TradeSource interface {
+	Query(symbol string, start, end time.Time) ([]core.Trade, error)
+}
+
+// AccountConfig is one simulated account the harness replays trades and
+// risk checks against.
+type AccountConfig struct {
+	UserID          uint
+	StartingBalance float64
+}
+
+// Config describes one backtest.Run: the symbols and window of history to
+// replay, the accounts to simulate, and the fee charged on every replayed
+// fill.
+type Config struct {
+	Symbols      []string
+	StartTime    time.Time
+	EndTime      time.Time
+	Accounts     []AccountConfig
+	MakerFeeRate float64
+	TakerFeeRate float64
+}
+
+// AccountReport summarizes one account's replay.
+type AccountReport struct {
+	UserID        uint
+	RealizedPnL   float64
+	UnrealizedPnL float64
+	MaxDrawdown   float64
+	Sharpe        float64
+	WinRate       float64
+	EquityCurve   core.ChartDataResponse
+}
+
+// Report summarizes a full backtest.Run: one AccountReport per configured
+// account.
+type Report struct {
+	Accounts []AccountReport
+}
+
+// event is one replayed tick or fill, merged and sorted across every
+// configured symbol so each account's bot sees history in the order it
+// actually happened.
+type event struct {
+	timestamp  time.Time
+	marketData *core.MarketData
+	trade      *core.Trade
+}
+
+// Run replays marketSource/tradeSource's history for cfg.Symbols between
+// StartTime and EndTime through one in-memory aiassistant.TradingBot per
+// configured account, feeding ticks via IngestMarketData and fills via
+// RecordTrade/ObserveTradeResult, then reports each account's resulting
+// PnL, drawdown, Sharpe ratio, and win rate.
+func Run(cfg Config, marketSource MarketDataSource, tradeSource TradeSource) (Report, error) {
+	if len(cfg.Accounts) == 0 {
+		return Report{}, fmt.Errorf("backtest: config must declare at least one account")
+	}
+
+	events, err := mergedEvents(cfg, marketSource, tradeSource)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{Accounts: make([]AccountReport, 0, len(cfg.Accounts))}
+	for _, account := range cfg.Accounts {
+		report.Accounts = append(report.Accounts, runAccount(cfg, account, events))
+	}
+	return report, nil
+}
+
+// mergedEvents queries every configured symbol from both sources and merges
+// the result into a single chronological stream.
+func mergedEvents(cfg Config, marketSource MarketDataSource, tradeSource TradeSource) ([]event, error) {
+	var events []event
+	for _, symbol := range cfg.Symbols {
+		ticks, err := marketSource.Query(symbol, cfg.StartTime, cfg.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("backtest: failed to query market data for %s: %w", symbol, err)
+		}
+		for i := range ticks {
+			events = append(events, event{timestamp: ticks[i].Timestamp, marketData: &ticks[i]})
+		}
+
+		trades, err := tradeSource.Query(symbol, cfg.StartTime, cfg.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("backtest: failed to query trades for %s: %w", symbol, err)
+		}
+		for i := range trades {
+			events = append(events, event{timestamp: trades[i].TradeTime, trade: &trades[i]})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].timestamp.Before(events[j].timestamp) })
+	return events, nil
+}
+
+// runAccount replays events through a fresh in-memory TradingBot for
+// account, tracking PnL with accountLedger.
+func runAccount(cfg Config, account AccountConfig, events []event) AccountReport {
+	bot := aiassistant.NewTradingBot("")
+	ledger := &accountLedger{feeRate: cfg.TakerFeeRate}
+
+	var curve []core.ChartDataPoint
+	var wins, losses int
+
+	for _, evt := range events {
+		switch {
+		case evt.marketData != nil:
+			bot.IngestMarketData(toMarketDataResponse(*evt.marketData))
+			ledger.mark(evt.marketData.Price)
+
+		case evt.trade != nil:
+			bot.RecordTrade(*evt.trade, evt.trade.Price, evt.trade.Price)
+			realized := ledger.applyFill(*evt.trade)
+			bot.ObserveTradeResult(account.UserID, *evt.trade, account.StartingBalance+ledger.equity())
+			switch {
+			case realized > 0:
+				wins++
+			case realized < 0:
+				losses++
+			}
+		}
+
+		curve = append(curve, core.ChartDataPoint{Timestamp: evt.timestamp, Price: account.StartingBalance + ledger.equity()})
+	}
+
+	maxDrawdown, sharpe := equityStats(curve)
+	var winRate float64
+	if total := wins + losses; total > 0 {
+		winRate = float64(wins) / float64(total)
+	}
+
+	return AccountReport{
+		UserID:        account.UserID,
+		RealizedPnL:   ledger.realizedPnL,
+		UnrealizedPnL: ledger.unrealizedPnL,
+		MaxDrawdown:   maxDrawdown,
+		Sharpe:        sharpe,
+		WinRate:       winRate,
+		EquityCurve:   core.ChartDataResponse{Data: curve, Type: "backtest_equity"},
+	}
+}
+
+// toMarketDataResponse adapts a stored core.MarketData row to the
+// core.MarketDataResponse shape TradingBot.IngestMarketData expects.
+func toMarketDataResponse(md core.MarketData) core.MarketDataResponse {
+	return core.MarketDataResponse{
+		Timestamp:     md.Timestamp,
+		Price:         md.Price,
+		Bid:           md.Bid,
+		Ask:           md.Ask,
+		Volume:        md.Volume,
+		Change24h:     md.Change24h,
+		ChangePercent: md.ChangePercent,
+	}
+}
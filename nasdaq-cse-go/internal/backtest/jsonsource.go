@@ -0,0 +1,90 @@
+package backtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+)
+
+// JSONMarketDataSource reads historical core.MarketData records from one
+// JSON array file per symbol under dir, named "<symbol>_marketdata.json".
+type JSONMarketDataSource struct {
+	dir string
+}
+
+// NewJSONMarketDataSource creates a JSONMarketDataSource rooted at dir.
+func NewJSONMarketDataSource(dir string) *JSONMarketDataSource {
+	return &JSONMarketDataSource{dir: dir}
+}
+
+// Query reads symbol's market data file and returns the records whose
+// Timestamp falls within [start, end].
+func (s *JSONMarketDataSource) Query(symbol string, start, end time.Time) ([]core.MarketData, error) {
+	var records []core.MarketData
+	if err := readJSONFile(filepath.Join(s.dir, symbol+"_marketdata.json"), &records); err != nil {
+		return nil, err
+	}
+	return filterMarketData(records, start, end), nil
+}
+
+func filterMarketData(records []core.MarketData, start, end time.Time) []core.MarketData {
+	filtered := make([]core.MarketData, 0, len(records))
+	for _, record := range records {
+		if !record.Timestamp.Before(start) && !record.Timestamp.After(end) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// JSONTradeSource reads historical core.Trade records from one JSON array
+// file per symbol under dir, named "<symbol>_trades.json".
+type JSONTradeSource struct {
+	dir string
+}
+
+// NewJSONTradeSource creates a JSONTradeSource rooted at dir.
+func NewJSONTradeSource(dir string) *JSONTradeSource {
+	return &JSONTradeSource{dir: dir}
+}
+
+// Query reads symbol's trade file and returns the records whose TradeTime
+// falls within [start, end].
+func (s *JSONTradeSource) Query(symbol string, start, end time.Time) ([]core.Trade, error) {
+	var records []core.Trade
+	if err := readJSONFile(filepath.Join(s.dir, symbol+"_trades.json"), &records); err != nil {
+		return nil, err
+	}
+	return filterTrades(records, start, end), nil
+}
+
+func filterTrades(records []core.Trade, start, end time.Time) []core.Trade {
+	filtered := make([]core.Trade, 0, len(records))
+	for _, record := range records {
+		if !record.TradeTime.Before(start) && !record.TradeTime.After(end) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// readJSONFile decodes path's JSON array into dest, treating a missing file
+// as an empty result rather than an error - a symbol with no recorded
+// trades yet is a normal, not exceptional, backtest input.
+func readJSONFile(path string, dest interface{}) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("backtest: failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("backtest: failed to parse %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package backtest
+
+import (
+	"math"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+)
+
+// accountLedger accumulates one account's realized/unrealized PnL across a
+// replay. Realized PnL comes straight from each trade's own core.Trade.PnL
+// (already computed by the OMS when the fill closed a position), less a fee
+// charged at feeRate; this harness has no per-trade maker/taker flag to
+// replay, so every fill is charged at the taker rate. Unrealized PnL is
+// marked against the last seen market price for whatever quantity the
+// replayed trades have left open, using an average-cost model.
+type accountLedger struct {
+	feeRate float64
+
+	realizedPnL   float64
+	unrealizedPnL float64
+
+	openQuantity float64
+	avgCost      float64
+	lastPrice    float64
+}
+
+// applyFill folds trade into the ledger and returns the realized PnL (if
+// any) it contributed, for win-rate accounting.
+func (l *accountLedger) applyFill(trade core.Trade) float64 {
+	var realized float64
+	if trade.PnL != nil {
+		realized = *trade.PnL
+	}
+	fee := trade.Price * trade.Quantity * l.feeRate
+	l.realizedPnL += realized - fee
+
+	if realized != 0 {
+		// A closing fill: shrink the open quantity it closed rather than
+		// extending the average-cost position.
+		l.openQuantity = math.Max(l.openQuantity-trade.Quantity, 0)
+	} else {
+		newQuantity := l.openQuantity + trade.Quantity
+		if newQuantity > 0 {
+			l.avgCost = (l.avgCost*l.openQuantity + trade.Price*trade.Quantity) / newQuantity
+		}
+		l.openQuantity = newQuantity
+	}
+
+	l.mark(trade.Price)
+	return realized
+}
+
+// mark updates unrealizedPnL against price for whatever quantity is still
+// open.
+func (l *accountLedger) mark(price float64) {
+	l.lastPrice = price
+	if l.openQuantity == 0 {
+		l.unrealizedPnL = 0
+		return
+	}
+	l.unrealizedPnL = l.openQuantity * (price - l.avgCost)
+}
+
+// equity returns realized + unrealized PnL.
+func (l *accountLedger) equity() float64 {
+	return l.realizedPnL + l.unrealizedPnL
+}
+
+// equityStats derives maxDrawdown (largest peak-to-trough drop in curve)
+// and an annualization-free Sharpe ratio (mean / stddev of successive
+// equity changes), mirroring pkg/backtest.computeDrawdownAndSharpe.
+func equityStats(curve []core.ChartDataPoint) (maxDrawdown, sharpe float64) {
+	if len(curve) == 0 {
+		return 0, 0
+	}
+
+	peak := curve[0].Price
+	for _, point := range curve {
+		if point.Price > peak {
+			peak = point.Price
+		}
+		if drawdown := peak - point.Price; drawdown > maxDrawdown {
+			maxDrawdown = drawdown
+		}
+	}
+
+	if len(curve) < 2 {
+		return maxDrawdown, 0
+	}
+
+	returns := make([]float64, 0, len(curve)-1)
+	for i := 1; i < len(curve); i++ {
+		returns = append(returns, curve[i].Price-curve[i-1].Price)
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns))
+	stddev := math.Sqrt(variance)
+	if stddev == 0 {
+		return maxDrawdown, 0
+	}
+
+	return maxDrawdown, mean / stddev
+}
@@ -0,0 +1,224 @@
+package communication
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// FIXSessionInfo is a read-only snapshot of one connected FIX counterparty,
+// exposed over GET /api/fix/sessions.
+type FIXSessionInfo struct {
+	SessionID          string    `json:"session_id"`
+	SenderCompID       string    `json:"sender_comp_id"`
+	TargetCompID       string    `json:"target_comp_id"`
+	RemoteAddr         string    `json:"remote_addr"`
+	LoggedInAt         time.Time `json:"logged_in_at"`
+	NextInboundSeqNum  int       `json:"next_inbound_seq_num"`
+	NextOutboundSeqNum int       `json:"next_outbound_seq_num"`
+}
+
+// FIXAcceptor listens for inbound FIX 4.4 TCP connections and runs the
+// acceptor side of the Logon(A) handshake: it waits for the counterparty's
+// Logon, restores sequence numbers for that CompID pair from SessionStore,
+// and replies with its own Logon before handing the connection to the same
+// FIXEngine session machinery FIXEngine.Logon (initiator mode) drives.
+type FIXAcceptor struct {
+	senderCompID string
+	heartBtInt   int
+	store        SessionStore
+	dictionary   *DataDictionary
+	gateway      *FIXOrderGateway
+
+	listener net.Listener
+
+	mu         sync.Mutex
+	sessions   map[string]*FIXEngine
+	loggedInAt map[string]time.Time
+}
+
+// NewFIXAcceptor creates a FIXAcceptor identified by senderCompID.
+func NewFIXAcceptor(senderCompID string) *FIXAcceptor {
+	// Without a file store, sequence numbers are kept in memory only and
+	// reset to 1 on restart rather than failing construction.
+	var store SessionStore
+	if fileStore, err := NewFileSessionStore("./data/fix_sessions"); err == nil {
+		store = fileStore
+	}
+
+	return &FIXAcceptor{
+		senderCompID: senderCompID,
+		heartBtInt:   30,
+		store:        store,
+		dictionary:   DefaultDataDictionary(),
+		sessions:     make(map[string]*FIXEngine),
+		loggedInAt:   make(map[string]time.Time),
+	}
+}
+
+// SetSessionStore overrides the default file-backed SessionStore (e.g. with
+// a DatabaseSessionStore or RedisSessionStore).
+func (fa *FIXAcceptor) SetSessionStore(store SessionStore) {
+	fa.store = store
+}
+
+// SetOrderGateway wires gateway's NewOrderSingle/OrderCancelRequest handling
+// onto every session this acceptor accepts from here on.
+func (fa *FIXAcceptor) SetOrderGateway(gateway *FIXOrderGateway) {
+	fa.gateway = gateway
+}
+
+// ListenAndServe starts accepting FIX connections on addr and returns once
+// the listener is up; connections are handled on background goroutines
+// until Close is called.
+func (fa *FIXAcceptor) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for FIX connections on %s: %w", addr, err)
+	}
+	fa.listener = listener
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go fa.acceptConn(conn)
+		}
+	}()
+	return nil
+}
+
+// acceptConn waits for the counterparty's Logon(A), restores sequence
+// numbers for that CompID pair, replies with its own Logon, and then runs
+// the session the same way an initiator-side FIXEngine would.
+func (fa *FIXAcceptor) acceptConn(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	raw, err := readFIXMessage(reader)
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	msg, err := FromFIXString(raw)
+	if err != nil || msg.MsgType != MessageTypeLogon {
+		conn.Close()
+		return
+	}
+
+	remoteCompID := msg.Fields["49"]
+	heartBtInt := fa.heartBtInt
+	if hb, err := strconv.Atoi(msg.Fields["108"]); err == nil && hb > 0 {
+		heartBtInt = hb
+	}
+
+	fe := &FIXEngine{
+		senderCompID:    fa.senderCompID,
+		targetCompID:    remoteCompID,
+		heartBtInt:      heartBtInt,
+		store:           fa.store,
+		dictionary:      fa.dictionary,
+		conn:            conn,
+		messageHandlers: make(map[string]MessageHandler),
+		isLoggedIn:      true,
+		stopHeartbeat:   make(chan struct{}),
+	}
+
+	seqNums := SessionSeqNums{NextInboundSeqNum: 1, NextOutboundSeqNum: 1}
+	if fe.store != nil {
+		if loaded, err := fe.store.Load(fe.sessionID()); err == nil {
+			seqNums = loaded
+		}
+	}
+	fe.nextOutSeqNum = seqNums.NextOutboundSeqNum
+	fe.nextInSeqNum = seqNums.NextInboundSeqNum
+
+	if fa.gateway != nil {
+		fa.gateway.wire(fe)
+	}
+
+	if err := fe.send(NewFIXMessage(MessageTypeLogon, map[string]string{
+		"49":  fe.senderCompID,
+		"56":  fe.targetCompID,
+		"98":  "0", // EncryptMethod (None)
+		"108": strconv.Itoa(heartBtInt),
+	})); err != nil {
+		conn.Close()
+		return
+	}
+
+	fa.register(fe)
+	defer fa.unregister(fe.sessionID())
+
+	go fe.heartbeatLoop()
+	fe.readLoopFrom(reader)
+}
+
+func (fa *FIXAcceptor) register(fe *FIXEngine) {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+	fa.sessions[fe.sessionID()] = fe
+	fa.loggedInAt[fe.sessionID()] = time.Now()
+}
+
+func (fa *FIXAcceptor) unregister(sessionID string) {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+	delete(fa.sessions, sessionID)
+	delete(fa.loggedInAt, sessionID)
+}
+
+// Sessions returns a snapshot of every FIX counterparty currently connected
+// to this acceptor, for GET /api/fix/sessions.
+func (fa *FIXAcceptor) Sessions() []FIXSessionInfo {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	infos := make([]FIXSessionInfo, 0, len(fa.sessions))
+	for sessionID, fe := range fa.sessions {
+		fe.seqMu.Lock()
+		nextIn, nextOut := fe.nextInSeqNum, fe.nextOutSeqNum
+		fe.seqMu.Unlock()
+
+		remoteAddr := ""
+		fe.connMu.Lock()
+		if fe.conn != nil {
+			remoteAddr = fe.conn.RemoteAddr().String()
+		}
+		fe.connMu.Unlock()
+
+		infos = append(infos, FIXSessionInfo{
+			SessionID:          sessionID,
+			SenderCompID:       fe.senderCompID,
+			TargetCompID:       fe.targetCompID,
+			RemoteAddr:         remoteAddr,
+			LoggedInAt:         fa.loggedInAt[sessionID],
+			NextInboundSeqNum:  nextIn,
+			NextOutboundSeqNum: nextOut,
+		})
+	}
+	return infos
+}
+
+// Close stops accepting new connections and logs out every active session.
+func (fa *FIXAcceptor) Close() error {
+	if fa.listener != nil {
+		_ = fa.listener.Close()
+	}
+
+	fa.mu.Lock()
+	sessions := make([]*FIXEngine, 0, len(fa.sessions))
+	for _, fe := range fa.sessions {
+		sessions = append(sessions, fe)
+	}
+	fa.mu.Unlock()
+
+	for _, fe := range sessions {
+		_ = fe.Logout()
+	}
+	return nil
+}
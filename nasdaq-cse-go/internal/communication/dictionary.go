@@ -0,0 +1,167 @@
+package communication
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+)
+
+// FieldDef describes a single FIX tag as declared in a QuickFIX-style data
+// dictionary.
+type FieldDef struct {
+	Number int    `xml:"number,attr"`
+	Name   string `xml:"name,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// GroupDef describes a repeating group: a counter tag (e.g. NoMDEntries)
+// followed by a fixed sequence of member tags per entry.
+type GroupDef struct {
+	Name       string `xml:"name,attr"`
+	CountField int    `xml:"number,attr"`
+	Members    []int  `xml:"field>number"`
+}
+
+// MessageDef describes which fields a given MsgType requires and which
+// repeating groups it may carry.
+type MessageDef struct {
+	MsgType  string     `xml:"msgtype,attr"`
+	Name     string     `xml:"name,attr"`
+	Required []int      `xml:"field>number"`
+	Groups   []GroupDef `xml:"group"`
+}
+
+// dictionaryXML mirrors the QuickFIX FIX44.xml layout closely enough to
+// load the subset of tags this module cares about.
+type dictionaryXML struct {
+	XMLName  xml.Name     `xml:"fix"`
+	Fields   []FieldDef   `xml:"fields>field"`
+	Messages []MessageDef `xml:"messages>message"`
+}
+
+// DataDictionary validates that encoded/decoded FIX messages carry the
+// fields a given MsgType requires, the way QuickFIX's DataDictionary does.
+type DataDictionary struct {
+	fieldsByTag    map[int]FieldDef
+	messagesByType map[string]MessageDef
+}
+
+// LoadDataDictionary parses a QuickFIX-style XML data dictionary file.
+func LoadDataDictionary(path string) (*DataDictionary, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data dictionary %s: %w", path, err)
+	}
+
+	var parsed dictionaryXML
+	if err := xml.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse data dictionary %s: %w", path, err)
+	}
+
+	dd := &DataDictionary{
+		fieldsByTag:    make(map[int]FieldDef, len(parsed.Fields)),
+		messagesByType: make(map[string]MessageDef, len(parsed.Messages)),
+	}
+	for _, f := range parsed.Fields {
+		dd.fieldsByTag[f.Number] = f
+	}
+	for _, m := range parsed.Messages {
+		dd.messagesByType[m.MsgType] = m
+	}
+	return dd, nil
+}
+
+// DefaultDataDictionary returns a minimal in-memory dictionary covering the
+// admin and order/execution messages this gateway speaks, for deployments
+// that don't ship a FIX44.xml on disk.
+func DefaultDataDictionary() *DataDictionary {
+	dd := &DataDictionary{
+		fieldsByTag:    make(map[int]FieldDef),
+		messagesByType: make(map[string]MessageDef),
+	}
+
+	fields := []FieldDef{
+		{Number: 1, Name: "Account", Type: "STRING"},
+		{Number: 11, Name: "ClOrdID", Type: "STRING"},
+		{Number: 34, Name: "MsgSeqNum", Type: "SEQNUM"},
+		{Number: 35, Name: "MsgType", Type: "STRING"},
+		{Number: 38, Name: "OrderQty", Type: "QTY"},
+		{Number: 40, Name: "OrdType", Type: "CHAR"},
+		{Number: 41, Name: "OrigClOrdID", Type: "STRING"},
+		{Number: 44, Name: "Price", Type: "PRICE"},
+		{Number: 49, Name: "SenderCompID", Type: "STRING"},
+		{Number: 52, Name: "SendingTime", Type: "UTCTIMESTAMP"},
+		{Number: 54, Name: "Side", Type: "CHAR"},
+		{Number: 55, Name: "Symbol", Type: "STRING"},
+		{Number: 56, Name: "TargetCompID", Type: "STRING"},
+		{Number: 59, Name: "TimeInForce", Type: "CHAR"},
+		{Number: 43, Name: "PossDupFlag", Type: "BOOLEAN"},
+		{Number: 108, Name: "HeartBtInt", Type: "INT"},
+		{Number: 112, Name: "TestReqID", Type: "STRING"},
+		{Number: 123, Name: "GapFillFlag", Type: "BOOLEAN"},
+		{Number: 146, Name: "NoRelatedSym", Type: "NUMINGROUP"},
+		{Number: 262, Name: "MDReqID", Type: "STRING"},
+		{Number: 263, Name: "SubscriptionRequestType", Type: "CHAR"},
+		{Number: 264, Name: "MarketDepth", Type: "INT"},
+		{Number: 267, Name: "NoMDEntryTypes", Type: "NUMINGROUP"},
+		{Number: 268, Name: "NoMDEntries", Type: "NUMINGROUP"},
+		{Number: 269, Name: "MDEntryType", Type: "CHAR"},
+		{Number: 270, Name: "MDEntryPx", Type: "PRICE"},
+		{Number: 271, Name: "MDEntrySize", Type: "QTY"},
+	}
+	for _, f := range fields {
+		dd.fieldsByTag[f.Number] = f
+	}
+
+	dd.messagesByType["A"] = MessageDef{MsgType: "A", Name: "Logon", Required: []int{49, 56, 34, 108, 98}}
+	dd.messagesByType["0"] = MessageDef{MsgType: "0", Name: "Heartbeat", Required: []int{49, 56, 34}}
+	dd.messagesByType["1"] = MessageDef{MsgType: "1", Name: "TestRequest", Required: []int{49, 56, 34, 112}}
+	dd.messagesByType["2"] = MessageDef{MsgType: "2", Name: "ResendRequest", Required: []int{49, 56, 34, 7, 16}}
+	dd.messagesByType["4"] = MessageDef{MsgType: "4", Name: "SequenceReset", Required: []int{49, 56, 34, 36}}
+	dd.messagesByType["5"] = MessageDef{MsgType: "5", Name: "Logout", Required: []int{49, 56, 34}}
+	dd.messagesByType["D"] = MessageDef{MsgType: "D", Name: "NewOrderSingle", Required: []int{49, 56, 34, 11, 55, 54, 38, 40}}
+	dd.messagesByType["F"] = MessageDef{MsgType: "F", Name: "OrderCancelRequest", Required: []int{49, 56, 34, 11, 41, 55, 54}}
+	dd.messagesByType["8"] = MessageDef{MsgType: "8", Name: "ExecutionReport", Required: []int{49, 56, 34, 11, 17, 150, 39, 55, 54}}
+	dd.messagesByType["W"] = MessageDef{
+		MsgType:  "W",
+		Name:     "MarketDataSnapshotFullRefresh",
+		Required: []int{49, 56, 34, 55},
+		Groups:   []GroupDef{{Name: "NoMDEntries", CountField: 268, Members: []int{269, 270, 271}}},
+	}
+
+	return dd
+}
+
+// Validate checks that every field required for msgType is present in
+// fields, returning the first missing tag as an error.
+func (dd *DataDictionary) Validate(msgType string, fields map[int]string) error {
+	def, ok := dd.messagesByType[msgType]
+	if !ok {
+		return fmt.Errorf("unknown MsgType %q in data dictionary", msgType)
+	}
+	for _, tag := range def.Required {
+		if _, present := fields[tag]; !present {
+			name := fmt.Sprintf("tag %d", tag)
+			if fd, ok := dd.fieldsByTag[tag]; ok {
+				name = fd.Name
+			}
+			return fmt.Errorf("message %s missing required field %s", def.Name, name)
+		}
+	}
+	return nil
+}
+
+// Group returns the repeating group definition named groupName for msgType,
+// if the dictionary declares one (e.g. NoMDEntries on MarketDataSnapshot).
+func (dd *DataDictionary) Group(msgType, groupName string) (GroupDef, bool) {
+	def, ok := dd.messagesByType[msgType]
+	if !ok {
+		return GroupDef{}, false
+	}
+	for _, g := range def.Groups {
+		if g.Name == groupName {
+			return g, true
+		}
+	}
+	return GroupDef{}, false
+}
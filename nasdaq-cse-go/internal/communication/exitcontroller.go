@@ -0,0 +1,314 @@
+package communication
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/marketdata"
+)
+
+// ExitControllerConfig configures ExitController's trailing-stop tiers,
+// static stop-loss, and pending-order timeout.
+type ExitControllerConfig struct {
+	// TrailingActivationRatio[i]/TrailingCallbackRate[i] form tier i: once
+	// a position's favorable move from entry reaches
+	// TrailingActivationRatio[i], tier i arms; once armed, a retracement
+	// of TrailingCallbackRate[i] from the high-water mark closes the
+	// position. Both slices must be the same length, and
+	// TrailingActivationRatio must be strictly increasing.
+	TrailingActivationRatio []float64
+	TrailingCallbackRate    []float64
+
+	// StopLossPercent closes the position immediately once the adverse
+	// move from entry reaches this fraction, regardless of which
+	// trailing tier (if any) is armed. Zero disables it.
+	StopLossPercent float64
+
+	// PendingMinutes cancels a LIMIT order tracked via TrackPendingOrder
+	// once it's been outstanding this long. Zero disables the sweep.
+	PendingMinutes int
+}
+
+// validate checks that the trailing-stop tiers are well-formed: the two
+// rate slices the same length, and activation ratios strictly increasing
+// so the highest-armed tier is always the most conservative one still
+// above the current favorable move.
+func (cfg ExitControllerConfig) validate() error {
+	if len(cfg.TrailingActivationRatio) != len(cfg.TrailingCallbackRate) {
+		return fmt.Errorf("exitcontroller: trailingActivationRatio and trailingCallbackRate must be the same length, got %d and %d",
+			len(cfg.TrailingActivationRatio), len(cfg.TrailingCallbackRate))
+	}
+	for i := 1; i < len(cfg.TrailingActivationRatio); i++ {
+		if cfg.TrailingActivationRatio[i] <= cfg.TrailingActivationRatio[i-1] {
+			return fmt.Errorf("exitcontroller: trailingActivationRatio must be strictly increasing, got %v", cfg.TrailingActivationRatio)
+		}
+	}
+	return nil
+}
+
+// position is ExitController's in-memory view of one open position,
+// mirrored to SessionStore as a PositionTierState after every update.
+type position struct {
+	side         string // "LONG" or "SHORT"
+	entryPrice   float64
+	quantity     float64
+	extremePrice float64 // best (most favorable) price seen since entry
+	activeTier   int     // highest armed tier index, -1 if none
+}
+
+// pendingOrder is one outstanding LIMIT order tracked for pendingMinutes
+// timeout cancellation.
+type pendingOrder struct {
+	symbol      string
+	side        string
+	submittedAt time.Time
+}
+
+// ExitController wraps CommunicationManager.SendOrder with a trailing-stop
+// and static-stop-loss exit strategy per open position, driven by tick
+// updates from MarketDataService: as a position's favorable move from
+// entry crosses each configured activation ratio, the corresponding tier
+// arms, and a retracement past that tier's callback rate from the
+// high-water mark emits a market close order via FIX. A pendingMinutes
+// timeout separately cancels LIMIT orders that never filled. Tier state is
+// persisted through the same SessionStore the FIX engine uses for
+// sequence numbers, so a restart doesn't lose a position's high-water
+// mark.
+type ExitController struct {
+	comm  *CommunicationManager
+	store SessionStore
+	cfg   ExitControllerConfig
+
+	mutex     sync.Mutex
+	positions map[string]*position    // keyed by symbol
+	pending   map[string]pendingOrder // keyed by ClOrdID
+
+	stop chan struct{}
+}
+
+// NewExitController creates an ExitController that closes positions
+// through comm and persists tier state through store. cfg's trailing-stop
+// tiers are validated up front; see ExitControllerConfig.
+func NewExitController(comm *CommunicationManager, store SessionStore, cfg ExitControllerConfig) (*ExitController, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &ExitController{
+		comm:      comm,
+		store:     store,
+		cfg:       cfg,
+		positions: make(map[string]*position),
+		pending:   make(map[string]pendingOrder),
+	}, nil
+}
+
+// OpenPosition registers symbol as open with side ("LONG" or "SHORT"),
+// entryPrice, and quantity, restoring prior tier state from SessionStore
+// if a restart left one behind for the same symbol and side.
+func (ec *ExitController) OpenPosition(symbol, side string, entryPrice, quantity float64) error {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+
+	state, found, err := ec.store.LoadPositionState(symbol)
+	if err != nil {
+		return fmt.Errorf("exitcontroller: failed to load position state for %s: %w", symbol, err)
+	}
+
+	pos := &position{side: side, entryPrice: entryPrice, quantity: quantity, extremePrice: entryPrice, activeTier: -1}
+	if found && state.Side == side {
+		pos.entryPrice = state.EntryPrice
+		pos.extremePrice = state.ExtremePrice
+		pos.activeTier = state.ActiveTier
+	}
+	ec.positions[symbol] = pos
+	return ec.savePositionStateLocked(symbol, pos)
+}
+
+// ClosePosition drops symbol's tracked tier state once a position is flat,
+// so a later OpenPosition starts fresh instead of resuming a stale
+// high-water mark.
+func (ec *ExitController) ClosePosition(symbol string) {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+	delete(ec.positions, symbol)
+	// Overwrite the stored state with a side-less record, which
+	// OpenPosition never restores from.
+	_ = ec.store.SavePositionState(symbol, PositionTierState{Symbol: symbol, ActiveTier: -1, UpdatedAt: time.Now()})
+}
+
+// OnTick evaluates tick against its symbol's open position (if any),
+// advancing the high-water mark, arming the next trailing tier once
+// crossed, and emitting a market close order through comm if the armed
+// tier's callback (or the static stop loss) has been breached.
+func (ec *ExitController) OnTick(tick marketdata.Tick) error {
+	ec.mutex.Lock()
+	pos, ok := ec.positions[tick.Symbol]
+	if !ok {
+		ec.mutex.Unlock()
+		return nil
+	}
+
+	price := tick.Last
+	if (pos.side == "LONG" && price > pos.extremePrice) || (pos.side == "SHORT" && price < pos.extremePrice) {
+		pos.extremePrice = price
+	}
+
+	moveRatio := favorableMoveRatio(pos)
+	for i := len(ec.cfg.TrailingActivationRatio) - 1; i > pos.activeTier; i-- {
+		if moveRatio >= ec.cfg.TrailingActivationRatio[i] {
+			pos.activeTier = i
+			break
+		}
+	}
+
+	shouldClose, reason := ec.shouldClose(pos, price)
+	if err := ec.savePositionStateLocked(tick.Symbol, pos); err != nil {
+		ec.mutex.Unlock()
+		return err
+	}
+	side, quantity := pos.side, pos.quantity
+	ec.mutex.Unlock()
+
+	if !shouldClose {
+		return nil
+	}
+
+	closeSide := "SELL"
+	if side == "SHORT" {
+		closeSide = "BUY"
+	}
+	if _, err := ec.comm.SendOrder(map[string]interface{}{
+		"symbol":     tick.Symbol,
+		"side":       closeSide,
+		"order_type": "MARKET",
+		"quantity":   quantity,
+	}); err != nil {
+		return fmt.Errorf("exitcontroller: failed to send %s close for %s (%s): %w", closeSide, tick.Symbol, reason, err)
+	}
+
+	ec.ClosePosition(tick.Symbol)
+	return nil
+}
+
+// favorableMoveRatio returns (extremePrice-entryPrice)/entryPrice for a
+// LONG position, or the mirrored ratio for a SHORT, the basis each tier's
+// TrailingActivationRatio is compared against.
+func favorableMoveRatio(pos *position) float64 {
+	if pos.side == "SHORT" {
+		return (pos.entryPrice - pos.extremePrice) / pos.entryPrice
+	}
+	return (pos.extremePrice - pos.entryPrice) / pos.entryPrice
+}
+
+// shouldClose reports whether price has breached the static stop loss
+// from entry, or retraced past the armed tier's callback rate from the
+// high-water mark.
+func (ec *ExitController) shouldClose(pos *position, price float64) (bool, string) {
+	if pos.side == "SHORT" {
+		if ec.cfg.StopLossPercent > 0 && price >= pos.entryPrice*(1+ec.cfg.StopLossPercent) {
+			return true, "stop loss"
+		}
+		if pos.activeTier >= 0 && price > pos.extremePrice*(1+ec.cfg.TrailingCallbackRate[pos.activeTier]) {
+			return true, "trailing stop"
+		}
+		return false, ""
+	}
+
+	if ec.cfg.StopLossPercent > 0 && price <= pos.entryPrice*(1-ec.cfg.StopLossPercent) {
+		return true, "stop loss"
+	}
+	if pos.activeTier >= 0 && price < pos.extremePrice*(1-ec.cfg.TrailingCallbackRate[pos.activeTier]) {
+		return true, "trailing stop"
+	}
+	return false, ""
+}
+
+// savePositionStateLocked persists pos under symbol. Callers must hold
+// ec.mutex.
+func (ec *ExitController) savePositionStateLocked(symbol string, pos *position) error {
+	return ec.store.SavePositionState(symbol, PositionTierState{
+		Symbol:       symbol,
+		Side:         pos.side,
+		EntryPrice:   pos.entryPrice,
+		Quantity:     pos.quantity,
+		ExtremePrice: pos.extremePrice,
+		ActiveTier:   pos.activeTier,
+		UpdatedAt:    time.Now(),
+	})
+}
+
+// TrackPendingOrder registers clOrdID as an outstanding LIMIT order on
+// symbol/side so the background sweep started by StartPendingOrderSweep
+// cancels it once it's been outstanding longer than cfg.PendingMinutes.
+func (ec *ExitController) TrackPendingOrder(clOrdID, symbol, side string) {
+	if ec.cfg.PendingMinutes <= 0 {
+		return
+	}
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+	ec.pending[clOrdID] = pendingOrder{symbol: symbol, side: side, submittedAt: time.Now()}
+}
+
+// UntrackPendingOrder removes clOrdID once it fills (or is cancelled some
+// other way), so the sweep doesn't try to cancel an order that no longer
+// needs it.
+func (ec *ExitController) UntrackPendingOrder(clOrdID string) {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+	delete(ec.pending, clOrdID)
+}
+
+// StartPendingOrderSweep runs a background loop, checking every interval
+// for LIMIT orders outstanding longer than cfg.PendingMinutes and
+// cancelling them through comm. Call Stop to halt it.
+func (ec *ExitController) StartPendingOrderSweep(interval time.Duration) {
+	ec.stop = make(chan struct{})
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ec.sweepPendingOrders()
+			case <-ec.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background pending-order sweep started by
+// StartPendingOrderSweep.
+func (ec *ExitController) Stop() {
+	if ec.stop != nil {
+		close(ec.stop)
+	}
+}
+
+func (ec *ExitController) sweepPendingOrders() {
+	if ec.cfg.PendingMinutes <= 0 {
+		return
+	}
+	deadline := time.Duration(ec.cfg.PendingMinutes) * time.Minute
+
+	ec.mutex.Lock()
+	var expired []pendingOrder
+	var expiredIDs []string
+	for clOrdID, order := range ec.pending {
+		if time.Since(order.submittedAt) >= deadline {
+			expired = append(expired, order)
+			expiredIDs = append(expiredIDs, clOrdID)
+		}
+	}
+	for _, clOrdID := range expiredIDs {
+		delete(ec.pending, clOrdID)
+	}
+	ec.mutex.Unlock()
+
+	for i, order := range expired {
+		if _, err := ec.comm.CancelOrder(expiredIDs[i], order.symbol, order.side); err != nil {
+			fmt.Printf("exitcontroller: failed to cancel stale order %s: %v\n", expiredIDs[i], err)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package communication
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+)
+
+// FASTOperator names a FAST field operator: how a field's value on the wire
+// relates to the previous value seen for the same (template, field) pair.
+type FASTOperator string
+
+const (
+	FASTOpNone      FASTOperator = "none"
+	FASTOpConstant  FASTOperator = "constant"
+	FASTOpDefault   FASTOperator = "default"
+	FASTOpCopy      FASTOperator = "copy"
+	FASTOpIncrement FASTOperator = "increment"
+	FASTOpDelta     FASTOperator = "delta"
+	FASTOpTail      FASTOperator = "tail"
+)
+
+// FASTFieldType is the wire type of a FAST field, named after the XML
+// element QuickFAST-style template files use to declare it.
+type FASTFieldType string
+
+const (
+	FASTTypeUInt32  FASTFieldType = "uInt32"
+	FASTTypeInt32   FASTFieldType = "int32"
+	FASTTypeUInt64  FASTFieldType = "uInt64"
+	FASTTypeInt64   FASTFieldType = "int64"
+	FASTTypeString  FASTFieldType = "string"
+	FASTTypeDecimal FASTFieldType = "decimal"
+	FASTTypeBytes   FASTFieldType = "byteVector"
+)
+
+// FASTField describes one field of a FAST template: its wire type, id,
+// operator, and (for optional fields) whether a value is required at all.
+type FASTField struct {
+	ID        uint32
+	Name      string
+	Type      FASTFieldType
+	Operator  FASTOperator
+	Mandatory bool
+	Default   string
+}
+
+// FASTTemplate is a FAST message template: an ordered list of fields that
+// together describe how one message type is encoded on the wire.
+type FASTTemplate struct {
+	ID     uint32
+	Name   string
+	Fields []FASTField
+}
+
+// fastPrevValue is one entry in a template's previous-value dictionary,
+// keyed by field id, that the copy/increment/delta/tail operators read and
+// update as messages are encoded/decoded.
+type fastPrevValue struct {
+	assigned bool
+	isNull   bool
+	num      int64
+	str      string
+}
+
+// FASTDecoder implements the stop-bit-encoded FAST (FIX Adapted for
+// STreaming) wire format: PMAP-gated presence, constant/default/copy/
+// increment/delta/tail operators against a per-template dictionary of
+// previous values, and the uInt32/int32/uInt64/int64/string/decimal/
+// byteVector field encodings.
+type FASTDecoder struct {
+	templatesByID   map[uint32]*FASTTemplate
+	templatesByName map[string]*FASTTemplate
+	prevValues      map[uint32]map[uint32]*fastPrevValue // templateID -> fieldID -> previous value
+}
+
+// NewFASTDecoder creates a FAST decoder preloaded with the two templates
+// this gateway publishes by default (MarketData, Trade). Use
+// LoadFASTTemplates to replace these with templates read from an XML file.
+func NewFASTDecoder() *FASTDecoder {
+	fd := &FASTDecoder{
+		templatesByID:   make(map[uint32]*FASTTemplate),
+		templatesByName: make(map[string]*FASTTemplate),
+		prevValues:      make(map[uint32]map[uint32]*fastPrevValue),
+	}
+
+	fd.addTemplate(&FASTTemplate{
+		ID:   1,
+		Name: "MarketData",
+		Fields: []FASTField{
+			{ID: 1, Name: "Symbol", Type: FASTTypeString, Operator: FASTOpCopy, Mandatory: true},
+			{ID: 2, Name: "BidPrice", Type: FASTTypeDecimal, Operator: FASTOpDelta, Mandatory: true},
+			{ID: 3, Name: "AskPrice", Type: FASTTypeDecimal, Operator: FASTOpDelta, Mandatory: true},
+			{ID: 4, Name: "LastPrice", Type: FASTTypeDecimal, Operator: FASTOpDelta, Mandatory: false},
+			{ID: 5, Name: "Volume", Type: FASTTypeUInt64, Operator: FASTOpIncrement, Mandatory: true},
+		},
+	})
+	fd.addTemplate(&FASTTemplate{
+		ID:   2,
+		Name: "Trade",
+		Fields: []FASTField{
+			{ID: 1, Name: "Symbol", Type: FASTTypeString, Operator: FASTOpCopy, Mandatory: true},
+			{ID: 2, Name: "Price", Type: FASTTypeDecimal, Operator: FASTOpDelta, Mandatory: true},
+			{ID: 3, Name: "Quantity", Type: FASTTypeUInt64, Operator: FASTOpNone, Mandatory: true},
+			{ID: 4, Name: "Timestamp", Type: FASTTypeUInt64, Operator: FASTOpIncrement, Mandatory: true},
+		},
+	})
+
+	return fd
+}
+
+func (fd *FASTDecoder) addTemplate(t *FASTTemplate) {
+	fd.templatesByID[t.ID] = t
+	fd.templatesByName[t.Name] = t
+	fd.prevValues[t.ID] = make(map[uint32]*fastPrevValue)
+}
+
+// fastTemplatesXML mirrors a QuickFAST-style template file:
+//
+//	<templates>
+//	  <template id="1" name="MDIncRefresh">
+//	    <string id="1" name="Symbol"><copy/></string>
+//	    <decimal id="2" name="BidPrice"><delta/></decimal>
+//	  </template>
+//	</templates>
+type fastTemplatesXML struct {
+	XMLName   xml.Name          `xml:"templates"`
+	Templates []fastTemplateXML `xml:"template"`
+}
+
+type fastTemplateXML struct {
+	ID     uint32         `xml:"id,attr"`
+	Name   string         `xml:"name,attr"`
+	Fields []fastFieldXML `xml:",any"`
+}
+
+type fastFieldXML struct {
+	XMLName  xml.Name
+	ID       uint32 `xml:"id,attr"`
+	Name     string `xml:"name,attr"`
+	Presence string `xml:"presence,attr"`
+	Ops      []struct {
+		XMLName xml.Name
+		Value   string `xml:"value,attr"`
+	} `xml:",any"`
+}
+
+// LoadFASTTemplates parses a FAST XML template file and returns a decoder
+// configured with the templates it declares, replacing the built-in
+// MarketData/Trade defaults.
+func LoadFASTTemplates(path string) (*FASTDecoder, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FAST template file %s: %w", path, err)
+	}
+
+	var parsed fastTemplatesXML
+	if err := xml.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse FAST template file %s: %w", path, err)
+	}
+
+	fd := &FASTDecoder{
+		templatesByID:   make(map[uint32]*FASTTemplate),
+		templatesByName: make(map[string]*FASTTemplate),
+		prevValues:      make(map[uint32]map[uint32]*fastPrevValue),
+	}
+
+	for _, tpl := range parsed.Templates {
+		t := &FASTTemplate{ID: tpl.ID, Name: tpl.Name}
+		for _, f := range tpl.Fields {
+			field := FASTField{
+				ID:        f.ID,
+				Name:      f.Name,
+				Type:      FASTFieldType(f.XMLName.Local),
+				Operator:  FASTOpNone,
+				Mandatory: f.Presence != "optional",
+			}
+			if len(f.Ops) > 0 {
+				field.Operator = FASTOperator(f.Ops[0].XMLName.Local)
+				field.Default = f.Ops[0].Value
+			}
+			t.Fields = append(t.Fields, field)
+		}
+		fd.addTemplate(t)
+	}
+
+	return fd, nil
+}
+
+// needsPresenceBit reports whether a field's operator consumes a bit of the
+// PMAP, per the FAST specification: default/copy/increment/tail always do,
+// constant does only when the field is optional, and none/delta never do
+// (a delta is always transmitted, even when it encodes zero change).
+func needsPresenceBit(f FASTField) bool {
+	switch f.Operator {
+	case FASTOpDefault, FASTOpCopy, FASTOpIncrement, FASTOpTail:
+		return true
+	case FASTOpConstant:
+		return !f.Mandatory
+	default:
+		return false
+	}
+}
+
+// Encode renders fields as a FAST message for the named template: a PMAP
+// byte sequence followed by the transmitted field bytes, updating the
+// template's previous-value dictionary as it goes.
+func (fd *FASTDecoder) Encode(templateID uint32, fields map[string]interface{}) ([]byte, error) {
+	t, ok := fd.templatesByID[templateID]
+	if !ok {
+		return nil, fmt.Errorf("unknown FAST template id %d", templateID)
+	}
+	prev := fd.prevValues[t.ID]
+
+	var pmapBits []bool
+	var body bytes.Buffer
+
+	for _, f := range t.Fields {
+		pv, ok := prev[f.ID]
+		if !ok {
+			pv = &fastPrevValue{}
+			prev[f.ID] = pv
+		}
+
+		value, present := fields[f.Name]
+		transmit, encoded, err := encodeFASTField(f, value, present, pv)
+		if err != nil {
+			return nil, fmt.Errorf("encoding field %s: %w", f.Name, err)
+		}
+
+		if needsPresenceBit(f) {
+			pmapBits = append(pmapBits, transmit)
+		}
+		if transmit {
+			body.Write(encoded)
+		}
+	}
+
+	var out bytes.Buffer
+	out.Write(encodePMAP(pmapBits))
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
+
+// Decode reads one FAST message for templateID off r: its PMAP, then each
+// field per the template's operators, returning the reconstructed values
+// and updating the previous-value dictionary.
+func (fd *FASTDecoder) Decode(templateID uint32, r io.Reader) (map[string]interface{}, error) {
+	t, ok := fd.templatesByID[templateID]
+	if !ok {
+		return nil, fmt.Errorf("unknown FAST template id %d", templateID)
+	}
+	prev := fd.prevValues[t.ID]
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = &byteReaderWrapper{r}
+	}
+
+	pmapFieldCount := 0
+	for _, f := range t.Fields {
+		if needsPresenceBit(f) {
+			pmapFieldCount++
+		}
+	}
+	pmapBits, err := decodePMAP(br, pmapFieldCount)
+	if err != nil {
+		return nil, fmt.Errorf("decoding PMAP: %w", err)
+	}
+
+	result := make(map[string]interface{}, len(t.Fields))
+	bitIdx := 0
+	for _, f := range t.Fields {
+		pv, ok := prev[f.ID]
+		if !ok {
+			pv = &fastPrevValue{}
+			prev[f.ID] = pv
+		}
+
+		transmit := true
+		if needsPresenceBit(f) {
+			transmit = pmapBits[bitIdx]
+			bitIdx++
+		}
+
+		value, err := decodeFASTField(f, transmit, br, pv)
+		if err != nil {
+			return nil, fmt.Errorf("decoding field %s: %w", f.Name, err)
+		}
+		if value != nil {
+			result[f.Name] = value
+		}
+	}
+
+	return result, nil
+}
+
+// TemplateByName looks up a loaded template by name (e.g. for callers that
+// still address templates the way the old map-based decoder did).
+func (fd *FASTDecoder) TemplateByName(name string) (*FASTTemplate, bool) {
+	t, ok := fd.templatesByName[name]
+	return t, ok
+}
+
+type byteReaderWrapper struct {
+	io.Reader
+}
+
+func (b *byteReaderWrapper) ReadByte() (byte, error) {
+	var buf [1]byte
+	if _, err := io.ReadFull(b.Reader, buf[:]); err != nil {
+		return 0, err
+	}
+	return buf[0], nil
+}
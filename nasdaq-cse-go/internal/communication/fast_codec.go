@@ -0,0 +1,529 @@
+package communication
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// encodeStopBitVLQ renders the 7-bit groups of an unsigned value with the
+// stop bit (the high bit) set on the final byte, per FAST's variable-length
+// integer encoding. Most significant group is written first.
+func encodeStopBitVLQ(v uint64) []byte {
+	var groups []byte
+	groups = append(groups, byte(v&0x7F))
+	v >>= 7
+	for v > 0 {
+		groups = append(groups, byte(v&0x7F))
+		v >>= 7
+	}
+	// groups[0] is least significant; reverse so MSB-first with the stop
+	// bit on the last (least significant) byte written.
+	out := make([]byte, len(groups))
+	for i, g := range groups {
+		out[len(groups)-1-i] = g
+	}
+	out[len(out)-1] |= 0x80
+	return out
+}
+
+// decodeStopBitVLQ reads 7-bit groups from r until it hits one with the
+// stop bit set, reconstructing the unsigned value they encode.
+func decodeStopBitVLQ(r io.ByteReader) (uint64, error) {
+	var v uint64
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		v = (v << 7) | uint64(b&0x7F)
+		if b&0x80 != 0 {
+			return v, nil
+		}
+	}
+}
+
+// encodePMAP renders a presence map: one bit per field that needs one,
+// packed 7 bits per byte into bits 6..0 in transmission order (the first
+// field of each byte takes bit 6), with the stop bit (the high bit)
+// terminating the map the same way integers do.
+func encodePMAP(bits []bool) []byte {
+	if len(bits) == 0 {
+		return []byte{0x80}
+	}
+	var bytesOut []byte
+	for i := 0; i < len(bits); i += 7 {
+		var b byte
+		for j := 0; j < 7 && i+j < len(bits); j++ {
+			if bits[i+j] {
+				b |= 1 << (6 - j)
+			}
+		}
+		bytesOut = append(bytesOut, b)
+	}
+	bytesOut[len(bytesOut)-1] |= 0x80
+	return bytesOut
+}
+
+// decodePMAP reads a presence map off r and returns the first n presence
+// bits in transmission order.
+func decodePMAP(r io.ByteReader, n int) ([]bool, error) {
+	var bits []bool
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		for j := 0; j < 7; j++ {
+			bits = append(bits, b&(1<<(6-j)) != 0)
+		}
+		if b&0x80 != 0 {
+			break
+		}
+	}
+	if len(bits) < n {
+		bits = append(bits, make([]bool, n-len(bits))...)
+	}
+	return bits[:n], nil
+}
+
+// encodeFASTField renders one field's value per its operator, reporting
+// whether a value was actually written to the stream (transmit) — false
+// means the receiver reconstructs it from the previous-value dictionary
+// (copy/tail), the template default (default), or the prior value plus one
+// (increment).
+func encodeFASTField(f FASTField, value interface{}, present bool, pv *fastPrevValue) (transmit bool, encoded []byte, err error) {
+	switch f.Operator {
+	case FASTOpConstant:
+		if f.Mandatory {
+			return false, nil, nil
+		}
+		return present, nil, nil
+
+	case FASTOpDefault:
+		if !present {
+			return false, nil, nil
+		}
+		enc, err := encodeFASTValue(f, value)
+		if err != nil {
+			return false, nil, err
+		}
+		return true, enc, nil
+
+	case FASTOpCopy, FASTOpTail:
+		if !present {
+			return false, nil, nil
+		}
+		if pv.assigned && fastValueEquals(f, value, pv) {
+			return false, nil, nil
+		}
+		enc, err := encodeFASTValue(f, value)
+		if err != nil {
+			return false, nil, err
+		}
+		setFastPrevValue(f, value, pv)
+		return true, enc, nil
+
+	case FASTOpIncrement:
+		if pv.assigned {
+			expected := pv.num + 1
+			if n, ok := toInt64(value); ok && n == expected {
+				setFastPrevValue(f, value, pv)
+				return false, nil, nil
+			}
+		}
+		enc, err := encodeFASTValue(f, value)
+		if err != nil {
+			return false, nil, err
+		}
+		setFastPrevValue(f, value, pv)
+		return true, enc, nil
+
+	case FASTOpDelta:
+		if f.Type == FASTTypeDecimal {
+			_, mant, err := toDecimal(value)
+			if err != nil {
+				return false, nil, err
+			}
+			base := int64(0)
+			if pv.assigned {
+				base = pv.num
+			}
+			enc := encodeSignedVLQ(mant - base)
+			pv.assigned = true
+			pv.num = mant
+			return true, enc, nil
+		}
+
+		base := int64(0)
+		if pv.assigned {
+			base = pv.num
+		}
+		n, ok := toInt64(value)
+		if !ok {
+			return false, nil, fmt.Errorf("delta operator requires a numeric value, got %T", value)
+		}
+		enc := encodeSignedVLQ(n - base)
+		setFastPrevValue(f, value, pv)
+		return true, enc, nil
+
+	default: // none
+		enc, err := encodeFASTValue(f, value)
+		if err != nil {
+			return false, nil, err
+		}
+		return true, enc, nil
+	}
+}
+
+// decodeFASTField reverses encodeFASTField: when transmit is false it
+// reconstructs the value from the operator's rule instead of reading bytes.
+func decodeFASTField(f FASTField, transmit bool, r io.ByteReader, pv *fastPrevValue) (interface{}, error) {
+	switch f.Operator {
+	case FASTOpConstant:
+		if f.Mandatory || transmit {
+			return parseFASTDefault(f), nil
+		}
+		return nil, nil
+
+	case FASTOpDefault:
+		if !transmit {
+			if f.Default == "" {
+				return nil, nil
+			}
+			return parseFASTDefault(f), nil
+		}
+		v, err := decodeFASTValue(f, r)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+
+	case FASTOpCopy, FASTOpTail:
+		if !transmit {
+			if !pv.assigned {
+				return nil, nil
+			}
+			return fastPrevAsValue(f, pv), nil
+		}
+		v, err := decodeFASTValue(f, r)
+		if err != nil {
+			return nil, err
+		}
+		setFastPrevValue(f, v, pv)
+		return v, nil
+
+	case FASTOpIncrement:
+		if !transmit {
+			if !pv.assigned {
+				return nil, nil
+			}
+			pv.num++
+			return pv.num, nil
+		}
+		v, err := decodeFASTValue(f, r)
+		if err != nil {
+			return nil, err
+		}
+		setFastPrevValue(f, v, pv)
+		return v, nil
+
+	case FASTOpDelta:
+		delta, err := decodeSignedVLQ(r)
+		if err != nil {
+			return nil, err
+		}
+		base := int64(0)
+		if pv.assigned {
+			base = pv.num
+		}
+		v := base + delta
+		pv.assigned = true
+		pv.num = v
+		if f.Type == FASTTypeDecimal {
+			return decimalToFloat(-fastDecimalScale, v), nil
+		}
+		return v, nil
+
+	default: // none
+		if !transmit {
+			return nil, nil
+		}
+		return decodeFASTValue(f, r)
+	}
+}
+
+// encodeFASTValue renders value on the wire per f.Type: stop-bit VLQ for
+// integers, stop-bit-terminated ASCII for strings, (exponent, mantissa)
+// stop-bit VLQ pairs for decimals, and a length-prefixed byte run for
+// byteVector.
+func encodeFASTValue(f FASTField, value interface{}) ([]byte, error) {
+	switch f.Type {
+	case FASTTypeUInt32, FASTTypeUInt64:
+		n, ok := toUint64(value)
+		if !ok {
+			return nil, fmt.Errorf("expected unsigned integer for %s, got %T", f.Name, value)
+		}
+		if !f.Mandatory {
+			n++
+		}
+		return encodeStopBitVLQ(n), nil
+
+	case FASTTypeInt32, FASTTypeInt64:
+		n, ok := toInt64(value)
+		if !ok {
+			return nil, fmt.Errorf("expected integer for %s, got %T", f.Name, value)
+		}
+		if !f.Mandatory {
+			if n >= 0 {
+				n++
+			}
+		}
+		return encodeSignedVLQ(n), nil
+
+	case FASTTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string for %s, got %T", f.Name, value)
+		}
+		return encodeFASTString(s), nil
+
+	case FASTTypeDecimal:
+		exp, mant, err := toDecimal(value)
+		if err != nil {
+			return nil, err
+		}
+		out := encodeSignedVLQ(int64(exp))
+		out = append(out, encodeSignedVLQ(mant)...)
+		return out, nil
+
+	case FASTTypeBytes:
+		b, ok := value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("expected []byte for %s, got %T", f.Name, value)
+		}
+		out := encodeStopBitVLQ(uint64(len(b)))
+		return append(out, b...), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported FAST field type %q", f.Type)
+	}
+}
+
+func decodeFASTValue(f FASTField, r io.ByteReader) (interface{}, error) {
+	switch f.Type {
+	case FASTTypeUInt32, FASTTypeUInt64:
+		n, err := decodeStopBitVLQ(r)
+		if err != nil {
+			return nil, err
+		}
+		if !f.Mandatory {
+			n--
+		}
+		return n, nil
+
+	case FASTTypeInt32, FASTTypeInt64:
+		n, err := decodeSignedVLQ(r)
+		if err != nil {
+			return nil, err
+		}
+		if !f.Mandatory && n > 0 {
+			n--
+		}
+		return n, nil
+
+	case FASTTypeString:
+		return decodeFASTString(r)
+
+	case FASTTypeDecimal:
+		exp, err := decodeSignedVLQ(r)
+		if err != nil {
+			return nil, err
+		}
+		mant, err := decodeSignedVLQ(r)
+		if err != nil {
+			return nil, err
+		}
+		return decimalToFloat(exp, mant), nil
+
+	case FASTTypeBytes:
+		n, err := decodeStopBitVLQ(r)
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, n)
+		for i := range b {
+			v, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			b[i] = v
+		}
+		return b, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported FAST field type %q", f.Type)
+	}
+}
+
+// encodeSignedVLQ renders a signed integer as stop-bit 7-bit groups, sign
+// extending the leading group so the receiver can recover the sign from its
+// top data bit.
+func encodeSignedVLQ(n int64) []byte {
+	var groups []byte
+	for {
+		b := byte(n & 0x7F)
+		n >>= 7
+		groups = append(groups, b)
+		if (n == 0 && b&0x40 == 0) || (n == -1 && b&0x40 != 0) {
+			break
+		}
+	}
+	out := make([]byte, len(groups))
+	for i, g := range groups {
+		out[len(groups)-1-i] = g
+	}
+	out[len(out)-1] |= 0x80
+	return out
+}
+
+func decodeSignedVLQ(r io.ByteReader) (int64, error) {
+	var v int64
+	first := true
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if first {
+			if b&0x40 != 0 {
+				v = -1
+			}
+			first = false
+		}
+		v = (v << 7) | int64(b&0x7F)
+		if b&0x80 != 0 {
+			return v, nil
+		}
+	}
+}
+
+// encodeFASTString renders an ASCII string with the stop bit set on the
+// last byte's top bit, matching FAST's string encoding.
+func encodeFASTString(s string) []byte {
+	if s == "" {
+		return []byte{0x80}
+	}
+	b := []byte(s)
+	out := make([]byte, len(b))
+	copy(out, b)
+	out[len(out)-1] |= 0x80
+	return out
+}
+
+func decodeFASTString(r io.ByteReader) (string, error) {
+	var sb strings.Builder
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(b & 0x7F)
+		if b&0x80 != 0 {
+			return sb.String(), nil
+		}
+	}
+}
+
+func toUint64(value interface{}) (uint64, bool) {
+	switch v := value.(type) {
+	case uint64:
+		return v, true
+	case int64:
+		return uint64(v), true
+	case int:
+		return uint64(v), true
+	case float64:
+		return uint64(v), true
+	}
+	return 0, false
+}
+
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// fastDecimalScale is the number of decimal digits of precision toDecimal
+// keeps, which is enough for the prices this gateway quotes. The delta
+// operator encodes/decodes a decimal field's mantissa at this same fixed
+// scale, so its previous-value dictionary entry (pv.num) is always a plain
+// mantissa rather than a (exponent, mantissa) pair.
+const fastDecimalScale = 4
+
+// toDecimal splits a float into FAST's (exponent, mantissa) representation
+// using up to fastDecimalScale decimal digits of precision.
+func toDecimal(value interface{}) (exp int8, mantissa int64, err error) {
+	f, ok := value.(float64)
+	if !ok {
+		return 0, 0, fmt.Errorf("expected float64 for decimal field, got %T", value)
+	}
+	mantissa = int64(f * 10000)
+	return -fastDecimalScale, mantissa, nil
+}
+
+func decimalToFloat(exp, mantissa int64) float64 {
+	f, _ := strconv.ParseFloat(fmt.Sprintf("%de%d", mantissa, exp), 64)
+	return f
+}
+
+func fastValueEquals(f FASTField, value interface{}, pv *fastPrevValue) bool {
+	switch f.Type {
+	case FASTTypeString:
+		s, _ := value.(string)
+		return s == pv.str
+	default:
+		n, ok := toInt64(value)
+		return ok && n == pv.num
+	}
+}
+
+func setFastPrevValue(f FASTField, value interface{}, pv *fastPrevValue) {
+	pv.assigned = true
+	switch f.Type {
+	case FASTTypeString:
+		pv.str, _ = value.(string)
+	default:
+		pv.num, _ = toInt64(value)
+	}
+}
+
+func fastPrevAsValue(f FASTField, pv *fastPrevValue) interface{} {
+	if f.Type == FASTTypeString {
+		return pv.str
+	}
+	return pv.num
+}
+
+func parseFASTDefault(f FASTField) interface{} {
+	switch f.Type {
+	case FASTTypeString:
+		return f.Default
+	case FASTTypeDecimal:
+		v, _ := strconv.ParseFloat(f.Default, 64)
+		return v
+	default:
+		v, _ := strconv.ParseInt(f.Default, 10, 64)
+		return v
+	}
+}
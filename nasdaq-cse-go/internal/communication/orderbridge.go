@@ -0,0 +1,115 @@
+package communication
+
+import (
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/oms"
+)
+
+// FIXOrderGateway maps inbound NewOrderSingle(D)/OrderCancelRequest(F)
+// messages onto oms.OrderManager.SubmitOrder/CancelOrder, and reports the
+// result back to the counterparty as an ExecutionReport(8) - so a FIX
+// counterparty gets the same order lifecycle a REST client gets from
+// POST /api/orders.
+type FIXOrderGateway struct {
+	orderManager  *oms.OrderManager
+	defaultUserID uint
+}
+
+// NewFIXOrderGateway creates a gateway that submits/cancels orders on
+// orderManager under defaultUserID, since a FIX Account(1) tag identifies a
+// broker account rather than one of this simulator's internal user IDs.
+func NewFIXOrderGateway(orderManager *oms.OrderManager, defaultUserID uint) *FIXOrderGateway {
+	return &FIXOrderGateway{orderManager: orderManager, defaultUserID: defaultUserID}
+}
+
+// wire registers this gateway's NewOrderSingle/OrderCancelRequest handlers
+// on fe. Called by both FIXEngine.Logon (initiator) and FIXAcceptor
+// (acceptor) once a session is established.
+func (g *FIXOrderGateway) wire(fe *FIXEngine) {
+	fe.RegisterHandler("new_order_single", func(msg *FIXMessage) error {
+		return g.handleNewOrderSingle(fe, msg)
+	})
+	fe.RegisterHandler("order_cancel_request", func(msg *FIXMessage) error {
+		return g.handleOrderCancelRequest(fe, msg)
+	})
+}
+
+// handleNewOrderSingle submits msg as an order via OrderManager.SubmitOrder
+// and replies with a New (39=0) or Rejected (39=8) ExecutionReport.
+func (g *FIXOrderGateway) handleNewOrderSingle(fe *FIXEngine, msg *FIXMessage) error {
+	side := core.OrderSideBuy
+	if msg.Fields["54"] == "2" {
+		side = core.OrderSideSell
+	}
+	orderType := core.OrderTypeMarket
+	if msg.Fields["40"] == "2" {
+		orderType = core.OrderTypeLimit
+	}
+	quantity, _ := strconv.ParseFloat(msg.Fields["38"], 64)
+
+	req := core.OrderCreateRequest{
+		ContractSymbol: msg.Fields["55"],
+		Side:           side,
+		OrderType:      orderType,
+		Quantity:       quantity,
+	}
+	if priceStr, ok := msg.Fields["44"]; ok {
+		if price, err := strconv.ParseFloat(priceStr, 64); err == nil {
+			req.Price = &price
+		}
+	}
+
+	result := g.orderManager.SubmitOrder(g.defaultUserID, req)
+
+	execReport := NewFIXMessage(MessageTypeExecutionReport, map[string]string{
+		"49": fe.targetCompID,
+		"56": fe.senderCompID,
+		"11": msg.Fields["11"],
+		"17": result.OrderID,
+		"55": msg.Fields["55"],
+		"54": msg.Fields["54"],
+		"38": msg.Fields["38"],
+	})
+	if result.Success {
+		execReport.Fields["150"] = "0" // ExecType (New)
+		execReport.Fields["39"] = "0"  // OrdStatus (New)
+	} else {
+		execReport.Fields["150"] = "8" // ExecType (Rejected)
+		execReport.Fields["39"] = "8"  // OrdStatus (Rejected)
+		execReport.Fields["58"] = result.Error
+	}
+
+	return fe.send(execReport)
+}
+
+// handleOrderCancelRequest cancels msg's OrigClOrdID(41) via
+// OrderManager.CancelOrder and replies with a Canceled (39=4) or Rejected
+// (39=8) ExecutionReport.
+func (g *FIXOrderGateway) handleOrderCancelRequest(fe *FIXEngine, msg *FIXMessage) error {
+	result := g.orderManager.CancelOrder(msg.Fields["41"], g.defaultUserID)
+
+	execReport := NewFIXMessage(MessageTypeExecutionReport, map[string]string{
+		"49": fe.targetCompID,
+		"56": fe.senderCompID,
+		"11": msg.Fields["11"],
+		"17": uuid.New().String()[:8], // ExecID, required on every ExecutionReport
+		"41": msg.Fields["41"],
+		"55": msg.Fields["55"],
+		"54": msg.Fields["54"],
+	})
+	if success, _ := result["success"].(bool); success {
+		execReport.Fields["150"] = "4" // ExecType (Canceled)
+		execReport.Fields["39"] = "4"  // OrdStatus (Canceled)
+	} else {
+		execReport.Fields["150"] = "8"
+		execReport.Fields["39"] = "8"
+		if errMsg, ok := result["error"].(string); ok {
+			execReport.Fields["58"] = errMsg
+		}
+	}
+
+	return fe.send(execReport)
+}
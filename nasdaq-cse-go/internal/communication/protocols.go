@@ -1,17 +1,72 @@
-// Package communication provides FIX/FAST protocol simulation for market connectivity
+// Package communication provides FIX/FAST protocol connectivity: a real
+// SOH-framed FIX 4.4 session engine and a FAST codec driven by XML
+// templates.
 package communication
 
 import (
-	"encoding/json"
+	"bufio"
 	"fmt"
+	"io"
+	"net"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
-
-	"github.com/google/uuid"
 )
 
+// soh is the ASCII Start-Of-Header byte (0x01) FIX uses as its field
+// delimiter on the wire. Session-layer code (session.go) reads/writes it
+// directly; FIXMessage.ToFIXString/FromFIXString below also speak it so
+// encode/decode round-trips match what a real counterparty sends.
+const soh = "\x01"
+
+// readFIXMessage reads one complete SOH-delimited FIX message off reader,
+// framing it by BodyLength(9) rather than assuming one ReadString call
+// happens to land on a message boundary: a real FIX message has one SOH per
+// field, so a naive single ReadString(soh) only ever returns the first
+// field. It reads BeginString(8) and BodyLength(9), then exactly
+// BodyLength's worth of bytes for the body, then the trailing CheckSum(10)
+// field, and returns the concatenation ready for FromFIXString.
+func readFIXMessage(reader *bufio.Reader) (string, error) {
+	var message strings.Builder
+
+	beginString, err := reader.ReadString(soh[0])
+	if err != nil {
+		return "", err
+	}
+	message.WriteString(beginString)
+
+	bodyLengthField, err := reader.ReadString(soh[0])
+	if err != nil {
+		return "", err
+	}
+	message.WriteString(bodyLengthField)
+
+	tagValue := strings.SplitN(strings.TrimSuffix(bodyLengthField, soh), "=", 2)
+	if len(tagValue) != 2 || tagValue[0] != "9" {
+		return "", fmt.Errorf("expected BodyLength(9) field, got %q", bodyLengthField)
+	}
+	bodyLength, err := strconv.Atoi(tagValue[1])
+	if err != nil {
+		return "", fmt.Errorf("invalid BodyLength value %q: %w", tagValue[1], err)
+	}
+
+	body := make([]byte, bodyLength)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return "", err
+	}
+	message.Write(body)
+
+	checksumField, err := reader.ReadString(soh[0])
+	if err != nil {
+		return "", err
+	}
+	message.WriteString(checksumField)
+
+	return message.String(), nil
+}
+
 // MessageType represents FIX message types
 type MessageType string
 
@@ -23,6 +78,7 @@ const (
 	MessageTypeHeartbeat             MessageType = "0"
 	MessageTypeLogon                 MessageType = "A"
 	MessageTypeLogout                MessageType = "5"
+	MessageTypeOrderCancelRequest    MessageType = "F"
 )
 
 // FIXMessage represents a FIX protocol message
@@ -36,9 +92,9 @@ func NewFIXMessage(msgType MessageType, fields map[string]string) *FIXMessage {
 	if fields == nil {
 		fields = make(map[string]string)
 	}
-	
-	fields["35"] = string(msgType)                                          // MsgType
-	fields["52"] = time.Now().UTC().Format("20060102-15:04:05")           // SendingTime
+
+	fields["35"] = string(msgType)                              // MsgType
+	fields["52"] = time.Now().UTC().Format("20060102-15:04:05") // SendingTime
 
 	return &FIXMessage{
 		MsgType: msgType,
@@ -46,50 +102,58 @@ func NewFIXMessage(msgType MessageType, fields map[string]string) *FIXMessage {
 	}
 }
 
-// ToFIXString converts message to FIX format string
+// ToFIXString renders the message as a real SOH-delimited FIX 4.4 string:
+// BodyLength(9) is the byte count between the SOH following it and the SOH
+// preceding CheckSum(10), and CheckSum is the sum of all preceding bytes
+// (including their trailing SOH) mod 256, formatted as three digits.
 func (fm *FIXMessage) ToFIXString() string {
-	fixParts := []string{"8=FIX.4.4"} // BeginString
-
-	// Sort field tags for consistent ordering
+	// Sort field tags numerically so BeginString/BodyLength/MsgType lead
+	// and the remainder is in a stable, comparable order.
 	var tags []string
 	for tag := range fm.Fields {
+		if tag == "8" || tag == "9" || tag == "35" || tag == "10" {
+			continue
+		}
 		tags = append(tags, tag)
 	}
-	sort.Strings(tags)
+	sort.Slice(tags, func(i, j int) bool {
+		ti, _ := strconv.Atoi(tags[i])
+		tj, _ := strconv.Atoi(tags[j])
+		return ti < tj
+	})
 
-	// Add all fields
+	var body strings.Builder
+	body.WriteString(fmt.Sprintf("35=%s%s", fm.Fields["35"], soh))
 	for _, tag := range tags {
-		fixParts = append(fixParts, fmt.Sprintf("%s=%s", tag, fm.Fields[tag]))
+		body.WriteString(fmt.Sprintf("%s=%s%s", tag, fm.Fields[tag], soh))
 	}
 
-	// Calculate body length (everything after the BodyLength field)
-	body := strings.Join(fixParts[1:], "|")
-	bodyLength := fmt.Sprintf("9=%d", len(body))
-	fixParts = append([]string{fixParts[0], bodyLength}, fixParts[1:]...)
+	bodyLength := body.Len()
+	head := fmt.Sprintf("8=FIX.4.4%s9=%d%s", soh, bodyLength, soh)
 
-	// Calculate checksum (simplified)
-	fullMessage := strings.Join(fixParts, "|")
 	checksum := 0
-	for _, char := range fullMessage {
-		checksum += int(char)
+	for _, b := range []byte(head + body.String()) {
+		checksum += int(b)
 	}
-	checksum = checksum % 256
-	fixParts = append(fixParts, fmt.Sprintf("10=%03d", checksum))
+	checksum %= 256
 
-	return strings.Join(fixParts, "|")
+	return fmt.Sprintf("%s%s10=%03d%s", head, body.String(), checksum, soh)
 }
 
-// FromFIXString parses FIX message from string
+// FromFIXString parses a real SOH-delimited FIX message, verifying the
+// CheckSum(10) the way a receiving session would before trusting the
+// fields it carries.
 func FromFIXString(fixString string) (*FIXMessage, error) {
-	parts := strings.Split(fixString, "|")
+	parts := strings.Split(fixString, soh)
 	fields := make(map[string]string)
 
 	for _, part := range parts {
-		if strings.Contains(part, "=") {
-			tagValue := strings.SplitN(part, "=", 2)
-			if len(tagValue) == 2 {
-				fields[tagValue[0]] = tagValue[1]
-			}
+		if part == "" {
+			continue
+		}
+		tagValue := strings.SplitN(part, "=", 2)
+		if len(tagValue) == 2 {
+			fields[tagValue[0]] = tagValue[1]
 		}
 	}
 
@@ -98,6 +162,19 @@ func FromFIXString(fixString string) (*FIXMessage, error) {
 		return nil, fmt.Errorf("missing MsgType field")
 	}
 
+	if wantChecksum, ok := fields["10"]; ok {
+		idx := strings.LastIndex(fixString, "10=")
+		if idx > 0 {
+			checksum := 0
+			for _, b := range []byte(fixString[:idx]) {
+				checksum += int(b)
+			}
+			if got := fmt.Sprintf("%03d", checksum%256); got != wantChecksum {
+				return nil, fmt.Errorf("checksum mismatch: got %s want %s", got, wantChecksum)
+			}
+		}
+	}
+
 	return &FIXMessage{
 		MsgType: MessageType(msgTypeStr),
 		Fields:  fields,
@@ -107,294 +184,95 @@ func FromFIXString(fixString string) (*FIXMessage, error) {
 // MessageHandler defines the interface for handling FIX messages
 type MessageHandler func(*FIXMessage) error
 
-// FIXEngine handles FIX protocol communication
+// FIXEngine runs a FIX 4.4 session over a real TCP connection: it opens the
+// socket, drives the Logon(A)/Heartbeat(0)/TestRequest(1)/ResendRequest(2)/
+// SequenceReset(4)/Logout(5) admin flows, validates encoded/decoded fields
+// against a DataDictionary, and persists sequence numbers through a
+// SessionStore so a reconnect can gap-fill instead of restarting at 1.
+// See session.go for the socket/admin-flow implementation.
 type FIXEngine struct {
-	senderCompID     string
-	targetCompID     string
-	seqNum          int
-	sessions        map[string]interface{}
+	senderCompID string
+	targetCompID string
+	host         string
+	port         int
+	heartBtInt   int
+
+	store      SessionStore
+	dictionary *DataDictionary
+
+	conn   net.Conn
+	connMu sync.Mutex
+
+	nextOutSeqNum int
+	nextInSeqNum  int
+	seqMu         sync.Mutex
+
 	messageHandlers map[string]MessageHandler
+	handlersMu      sync.RWMutex
 	isLoggedIn      bool
+
+	stopHeartbeat chan struct{}
 }
 
-// NewFIXEngine creates a new FIX engine
+// NewFIXEngine creates a FIX engine identified by senderCompID, using store
+// for sequence-number persistence and dictionary to validate messages. Call
+// Configure to point it at a real counterparty before Logon.
 func NewFIXEngine(senderCompID string) *FIXEngine {
+	// Without a file store, sequence numbers are kept in memory only and
+	// reset to 1 on restart rather than failing engine construction.
+	var store SessionStore
+	if fileStore, err := NewFileSessionStore("./data/fix_sessions"); err == nil {
+		store = fileStore
+	}
+
 	return &FIXEngine{
 		senderCompID:    senderCompID,
 		targetCompID:    "EXCHANGE",
-		seqNum:         1,
-		sessions:       make(map[string]interface{}),
+		heartBtInt:      30,
+		store:           store,
+		dictionary:      DefaultDataDictionary(),
 		messageHandlers: make(map[string]MessageHandler),
-		isLoggedIn:     false,
+		isLoggedIn:      false,
 	}
 }
 
-// Logon simulates FIX logon process
-func (fe *FIXEngine) Logon(username, password string) error {
-	logonMsg := NewFIXMessage(MessageTypeLogon, map[string]string{
-		"49":  fe.senderCompID,           // SenderCompID
-		"56":  fe.targetCompID,           // TargetCompID
-		"34":  strconv.Itoa(fe.seqNum),   // MsgSeqNum
-		"553": username,                  // Username
-		"554": password,                  // Password
-		"98":  "0",                       // EncryptMethod (None)
-		"108": "30",                      // HeartBtInt
-	})
-
-	fe.seqNum++
-
-	// Simulate successful logon
-	time.Sleep(100 * time.Millisecond)
-	fe.isLoggedIn = true
-
-	fmt.Printf("FIX Logon: %s\n", logonMsg.ToFIXString())
-	return nil
+// Configure points the engine at a real counterparty. A non-default
+// SessionStore/DataDictionary (e.g. RedisSessionStore or a QuickFIX-style
+// XML dictionary loaded via LoadDataDictionary) is swapped in separately
+// through SetSessionStore/SetDataDictionary.
+func (fe *FIXEngine) Configure(host string, port int, targetCompID string) {
+	fe.host = host
+	fe.port = port
+	fe.targetCompID = targetCompID
 }
 
-// SendNewOrder sends new order via FIX protocol
-func (fe *FIXEngine) SendNewOrder(orderData map[string]interface{}) (string, error) {
-	if !fe.isLoggedIn {
-		return "", fmt.Errorf("not logged in to FIX session")
-	}
-
-	clOrdID := uuid.New().String()[:8]
-
-	// Convert order side
-	side := "1" // Buy
-	if orderData["side"].(string) == "SELL" {
-		side = "2"
-	}
-
-	// Convert order type
-	ordType := "1" // Market
-	if orderData["order_type"].(string) == "LIMIT" {
-		ordType = "2"
-	}
-
-	fields := map[string]string{
-		"49": fe.senderCompID,
-		"56": fe.targetCompID,
-		"34": strconv.Itoa(fe.seqNum),
-		"11": clOrdID,                                    // ClOrdID
-		"55": orderData["symbol"].(string),               // Symbol
-		"54": side,                                       // Side
-		"38": fmt.Sprintf("%.0f", orderData["quantity"]), // OrderQty
-		"40": ordType,                                    // OrdType
-		"59": "0",                                        // TimeInForce (DAY)
-	}
-
-	// Add price for limit orders
-	if price, exists := orderData["price"]; exists && price != nil {
-		fields["44"] = fmt.Sprintf("%.2f", price.(float64))
-	}
-
-	// Add account if provided
-	if account, exists := orderData["account"]; exists {
-		fields["1"] = account.(string)
-	} else {
-		fields["1"] = "DEMO001"
-	}
-
-	orderMsg := NewFIXMessage(MessageTypeNewOrderSingle, fields)
-	fe.seqNum++
-
-	// Simulate sending message
-	fixString := orderMsg.ToFIXString()
-	fmt.Printf("FIX Order: %s\n", fixString)
-
-	// Simulate execution report response
-	go fe.simulateExecutionReport(clOrdID, orderData)
-
-	return clOrdID, nil
+// SetSessionStore overrides the default file-backed SessionStore.
+func (fe *FIXEngine) SetSessionStore(store SessionStore) {
+	fe.store = store
 }
 
-// simulateExecutionReport simulates execution report from exchange
-func (fe *FIXEngine) simulateExecutionReport(clOrdID string, orderData map[string]interface{}) {
-	time.Sleep(100 * time.Millisecond)
-
-	execID := uuid.New().String()[:8]
-
-	// Simulate market execution
-	fillPrice := 2050.0
-	if price, exists := orderData["price"]; exists && price != nil {
-		fillPrice = price.(float64)
-	}
-
-	if orderData["order_type"].(string) == "MARKET" {
-		// Add small slippage for market orders
-		slippage := 0.1
-		if orderData["side"].(string) == "SELL" {
-			slippage = -0.1
-		}
-		fillPrice = 2050.0 + slippage
-	}
-
-	side := "1"
-	if orderData["side"].(string) == "SELL" {
-		side = "2"
-	}
-
-	execReport := NewFIXMessage(MessageTypeExecutionReport, map[string]string{
-		"49":  fe.targetCompID,
-		"56":  fe.senderCompID,
-		"34":  strconv.Itoa(fe.seqNum),
-		"11":  clOrdID,                                      // ClOrdID
-		"17":  execID,                                       // ExecID
-		"150": "F",                                          // ExecType (Trade)
-		"39":  "2",                                          // OrdStatus (Filled)
-		"55":  orderData["symbol"].(string),                 // Symbol
-		"54":  side,                                         // Side
-		"38":  fmt.Sprintf("%.0f", orderData["quantity"]),   // OrderQty
-		"32":  fmt.Sprintf("%.0f", orderData["quantity"]),   // LastQty
-		"31":  fmt.Sprintf("%.2f", fillPrice),               // LastPx
-		"14":  fmt.Sprintf("%.0f", orderData["quantity"]),   // CumQty
-		"6":   fmt.Sprintf("%.2f", fillPrice),               // AvgPx
-	})
-
-	fmt.Printf("FIX Execution: %s\n", execReport.ToFIXString())
-
-	// Call execution handler if registered
-	if handler, exists := fe.messageHandlers["execution"]; exists {
-		handler(execReport)
-	}
+// SetDataDictionary overrides the default in-memory DataDictionary.
+func (fe *FIXEngine) SetDataDictionary(dictionary *DataDictionary) {
+	fe.dictionary = dictionary
 }
 
-// SubscribeMarketData subscribes to market data feeds
-func (fe *FIXEngine) SubscribeMarketData(symbols []string) error {
-	if !fe.isLoggedIn {
-		return fmt.Errorf("not logged in to FIX session")
-	}
-
-	for _, symbol := range symbols {
-		reqID := uuid.New().String()[:8]
-
-		mdRequest := NewFIXMessage(MessageTypeMarketDataSnapshot, map[string]string{
-			"49":  fe.senderCompID,
-			"56":  fe.targetCompID,
-			"34":  strconv.Itoa(fe.seqNum),
-			"262": reqID,     // MDReqID
-			"263": "1",       // SubscriptionRequestType (Snapshot + Updates)
-			"264": "1",       // MarketDepth
-			"267": "2",       // NoMDEntryTypes
-			"269": "0|1",     // MDEntryType (Bid|Offer)
-			"146": "1",       // NoRelatedSym
-			"55":  symbol,    // Symbol
-		})
-
-		fe.seqNum++
-		fmt.Printf("FIX Market Data Request: %s\n", mdRequest.ToFIXString())
-	}
-
-	// Start market data simulation
-	go fe.simulateMarketData(symbols)
-	return nil
-}
-
-// simulateMarketData simulates market data updates
-func (fe *FIXEngine) simulateMarketData(symbols []string) {
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		if !fe.isLoggedIn {
-			break
-		}
-
-		for _, symbol := range symbols {
-			// Generate random price movements
-			basePrice := 2050.0
-			priceChange := float64((time.Now().UnixNano()%1000 - 500)) / 250.0 // Random price change
-			bidPrice := basePrice + priceChange - 0.5
-			askPrice := basePrice + priceChange + 0.5
-
-			mdUpdate := NewFIXMessage(MessageTypeMarketDataIncremental, map[string]string{
-				"49":  fe.targetCompID,
-				"56":  fe.senderCompID,
-				"34":  strconv.Itoa(fe.seqNum),
-				"55":  symbol,                           // Symbol
-				"268": "2",                             // NoMDEntries
-				"269": "0",                             // MDEntryType (Bid)
-				"270": fmt.Sprintf("%.2f", bidPrice),   // MDEntryPx
-				"271": "100",                           // MDEntrySize
-				"272": fmt.Sprintf("%.2f", askPrice),   // MDEntryPx for Ask
-			})
-
-			// Call market data handler if registered
-			if handler, exists := fe.messageHandlers["market_data"]; exists {
-				handler(mdUpdate)
-			}
-		}
-	}
+// sessionID identifies this CompID pair for SessionStore lookups.
+func (fe *FIXEngine) sessionID() string {
+	return fe.senderCompID + "-" + fe.targetCompID
 }
 
 // RegisterHandler registers a message handler
 func (fe *FIXEngine) RegisterHandler(handlerType string, handler MessageHandler) {
+	fe.handlersMu.Lock()
+	defer fe.handlersMu.Unlock()
 	fe.messageHandlers[handlerType] = handler
 }
 
-// Logout performs FIX logout
-func (fe *FIXEngine) Logout() error {
-	if fe.isLoggedIn {
-		logoutMsg := NewFIXMessage(MessageTypeLogout, map[string]string{
-			"49": fe.senderCompID,
-			"56": fe.targetCompID,
-			"34": strconv.Itoa(fe.seqNum),
-		})
-
-		fmt.Printf("FIX Logout: %s\n", logoutMsg.ToFIXString())
-		fe.isLoggedIn = false
-	}
-	return nil
-}
-
-// FASTDecoder provides simplified FAST (FIX Adapted for STreaming) decoder
-type FASTDecoder struct {
-	templates map[string]map[string]interface{}
-}
-
-// NewFASTDecoder creates a new FAST decoder
-func NewFASTDecoder() *FASTDecoder {
-	return &FASTDecoder{
-		templates: map[string]map[string]interface{}{
-			"MarketData": {
-				"id":     1,
-				"fields": []string{"Symbol", "BidPrice", "AskPrice", "LastPrice", "Volume"},
-			},
-			"Trade": {
-				"id":     2,
-				"fields": []string{"Symbol", "Price", "Quantity", "Timestamp"},
-			},
-		},
-	}
-}
-
-// DecodeMessage decodes FAST message (simplified implementation)
-func (fd *FASTDecoder) DecodeMessage(fastData []byte) (map[string]interface{}, error) {
-	// This is a simplified decoder - real FAST is much more complex
-	var data map[string]interface{}
-	if err := json.Unmarshal(fastData, &data); err != nil {
-		return map[string]interface{}{
-			"error": "Failed to decode FAST message",
-		}, err
-	}
-	return data, nil
-}
-
-// EncodeMessage encodes message to FAST format (simplified)
-func (fd *FASTDecoder) EncodeMessage(templateName string, data map[string]interface{}) ([]byte, error) {
-	template, exists := fd.templates[templateName]
-	if !exists {
-		return nil, fmt.Errorf("template %s not found", templateName)
-	}
-
-	message := map[string]interface{}{
-		"template":    templateName,
-		"template_id": template["id"],
-		"data":        data,
-		"timestamp":   time.Now().Format(time.RFC3339),
-	}
-
-	return json.Marshal(message)
+func (fe *FIXEngine) handler(handlerType string) (MessageHandler, bool) {
+	fe.handlersMu.RLock()
+	defer fe.handlersMu.RUnlock()
+	h, ok := fe.messageHandlers[handlerType]
+	return h, ok
 }
 
 // CommunicationManager manages FIX/FAST communication protocols
@@ -402,6 +280,8 @@ type CommunicationManager struct {
 	fixEngine   *FIXEngine
 	fastDecoder *FASTDecoder
 	isConnected bool
+
+	acceptor *FIXAcceptor
 }
 
 // NewCommunicationManager creates a new communication manager
@@ -440,8 +320,41 @@ func (cm *CommunicationManager) SendOrder(orderData map[string]interface{}) (str
 	return cm.fixEngine.SendNewOrder(orderData)
 }
 
-// Disconnect disconnects from exchange
+// SendNewOrderSingle sends a typed NewOrderSingle(D) via FIX protocol and
+// returns its ClOrdID.
+func (cm *CommunicationManager) SendNewOrderSingle(order NewOrderSingle) (string, error) {
+	if !cm.isConnected {
+		return "", fmt.Errorf("not connected to exchange")
+	}
+
+	return cm.fixEngine.SendNewOrderSingle(order)
+}
+
+// CancelOrder sends an OrderCancelRequest(F) for origClOrdID via FIX
+// protocol and returns the new ClOrdID assigned to the cancel request.
+func (cm *CommunicationManager) CancelOrder(origClOrdID, symbol, side string) (string, error) {
+	if !cm.isConnected {
+		return "", fmt.Errorf("not connected to exchange")
+	}
+
+	return cm.fixEngine.SendOrderCancelRequest(origClOrdID, symbol, side)
+}
+
+// OnExecutionReport registers a typed callback invoked whenever an
+// ExecutionReport(8) is received.
+func (cm *CommunicationManager) OnExecutionReport(callback func(ExecutionReport)) {
+	cm.fixEngine.OnExecutionReport(callback)
+}
+
+// Disconnect disconnects from exchange and stops the FIX acceptor, if one
+// was started.
 func (cm *CommunicationManager) Disconnect() error {
+	if cm.acceptor != nil {
+		if err := cm.acceptor.Close(); err != nil {
+			return err
+		}
+	}
+
 	if cm.isConnected {
 		if err := cm.fixEngine.Logout(); err != nil {
 			return err
@@ -465,4 +378,64 @@ func (cm *CommunicationManager) RegisterMarketDataHandler(handler MessageHandler
 // IsConnected returns connection status
 func (cm *CommunicationManager) IsConnected() bool {
 	return cm.isConnected
-}
\ No newline at end of file
+}
+
+// LoadFASTTemplates loads the FAST templates a market data feed encodes
+// against (e.g. a UDP multicast depth/kline feed) into this manager's
+// fastDecoder, so DecodeFASTMarketData can decode messages for them.
+func (cm *CommunicationManager) LoadFASTTemplates(path string) error {
+	fd, err := LoadFASTTemplates(path)
+	if err != nil {
+		return fmt.Errorf("failed to load FAST templates from %s: %w", path, err)
+	}
+	cm.fastDecoder = fd
+	return nil
+}
+
+// DecodeFASTMarketData decodes one FAST-encoded market data message for
+// templateID off r, using the templates loaded via LoadFASTTemplates.
+func (cm *CommunicationManager) DecodeFASTMarketData(templateID uint32, r io.Reader) (map[string]interface{}, error) {
+	return cm.fastDecoder.Decode(templateID, r)
+}
+
+// EncodeFASTMarketData renders fields as a FAST-encoded market data message
+// for templateID, using the templates loaded via LoadFASTTemplates.
+func (cm *CommunicationManager) EncodeFASTMarketData(templateID uint32, fields map[string]interface{}) ([]byte, error) {
+	return cm.fastDecoder.Encode(templateID, fields)
+}
+
+// SetSessionStore overrides the default file-backed SessionStore the
+// initiator-side fixEngine uses to persist sequence numbers (e.g. with a
+// DatabaseSessionStore backed by storage.DatabaseManager).
+func (cm *CommunicationManager) SetSessionStore(store SessionStore) {
+	cm.fixEngine.SetSessionStore(store)
+}
+
+// StartFIXAcceptor listens on addr for counterparties initiating FIX 4.4
+// sessions against this server, using store to persist sequence numbers and
+// gateway (if non-nil) to map inbound NewOrderSingle/OrderCancelRequest onto
+// oms.OrderManager.
+func (cm *CommunicationManager) StartFIXAcceptor(addr, senderCompID string, store SessionStore, gateway *FIXOrderGateway) error {
+	acceptor := NewFIXAcceptor(senderCompID)
+	if store != nil {
+		acceptor.SetSessionStore(store)
+	}
+	if gateway != nil {
+		acceptor.SetOrderGateway(gateway)
+	}
+	if err := acceptor.ListenAndServe(addr); err != nil {
+		return err
+	}
+	cm.acceptor = acceptor
+	return nil
+}
+
+// FIXSessions returns a snapshot of every FIX counterparty currently
+// connected to this manager's acceptor, for GET /api/fix/sessions. Returns
+// an empty slice if StartFIXAcceptor hasn't been called.
+func (cm *CommunicationManager) FIXSessions() []FIXSessionInfo {
+	if cm.acceptor == nil {
+		return []FIXSessionInfo{}
+	}
+	return cm.acceptor.Sessions()
+}
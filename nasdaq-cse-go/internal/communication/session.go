@@ -0,0 +1,530 @@
+package communication
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewOrderSingle is the typed request for a FIX NewOrderSingle(D).
+type NewOrderSingle struct {
+	Symbol    string
+	Side      string // "BUY" or "SELL"
+	OrderType string // "MARKET" or "LIMIT"
+	Quantity  float64
+	Price     *float64
+	Account   string
+}
+
+// ExecutionReport is the typed view of a FIX ExecutionReport(8).
+type ExecutionReport struct {
+	ClOrdID   string
+	ExecID    string
+	Symbol    string
+	Side      string
+	OrderQty  float64
+	LastQty   float64
+	LastPx    float64
+	CumQty    float64
+	AvgPx     float64
+	OrdStatus string
+}
+
+// Logon opens the TCP session (if Configure set a host/port) and runs the
+// FIX 4.4 Logon(A) handshake, restoring sequence numbers from the
+// SessionStore so a reconnect resumes rather than resetting to 1.
+func (fe *FIXEngine) Logon(username, password string) error {
+	seqNums := SessionSeqNums{NextInboundSeqNum: 1, NextOutboundSeqNum: 1}
+	if fe.store != nil {
+		if loaded, err := fe.store.Load(fe.sessionID()); err == nil {
+			seqNums = loaded
+		}
+	}
+	fe.seqMu.Lock()
+	fe.nextOutSeqNum = seqNums.NextOutboundSeqNum
+	fe.nextInSeqNum = seqNums.NextInboundSeqNum
+	fe.seqMu.Unlock()
+
+	if fe.host != "" {
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", fe.host, fe.port), 5*time.Second)
+		if err != nil {
+			return fmt.Errorf("failed to dial FIX counterparty %s:%d: %w", fe.host, fe.port, err)
+		}
+		fe.connMu.Lock()
+		fe.conn = conn
+		fe.connMu.Unlock()
+		go fe.readLoop(conn)
+	}
+
+	logonMsg := NewFIXMessage(MessageTypeLogon, map[string]string{
+		"49":  fe.senderCompID,
+		"56":  fe.targetCompID,
+		"553": username,
+		"554": password,
+		"98":  "0", // EncryptMethod (None)
+		"108": strconv.Itoa(fe.heartBtInt),
+	})
+	if err := fe.send(logonMsg); err != nil {
+		return fmt.Errorf("failed to send Logon: %w", err)
+	}
+
+	fe.isLoggedIn = true
+	fe.stopHeartbeat = make(chan struct{})
+	go fe.heartbeatLoop()
+
+	return nil
+}
+
+// send validates msg against the data dictionary, stamps MsgSeqNum(34),
+// assigns it the next outbound sequence number, writes it (over the TCP
+// socket when connected, or just to the session log in loopback/demo mode),
+// and persists the advanced sequence number.
+func (fe *FIXEngine) send(msg *FIXMessage) error {
+	fe.seqMu.Lock()
+	seqNum := fe.nextOutSeqNum
+	fe.nextOutSeqNum++
+	fe.seqMu.Unlock()
+	msg.Fields["34"] = strconv.Itoa(seqNum)
+
+	if fe.dictionary != nil {
+		intFields := make(map[int]string, len(msg.Fields))
+		for tag, v := range msg.Fields {
+			if n, err := strconv.Atoi(tag); err == nil {
+				intFields[n] = v
+			}
+		}
+		if err := fe.dictionary.Validate(string(msg.MsgType), intFields); err != nil {
+			return err
+		}
+	}
+
+	fixString := msg.ToFIXString()
+
+	fe.connMu.Lock()
+	conn := fe.conn
+	fe.connMu.Unlock()
+	if conn != nil {
+		if _, err := conn.Write([]byte(fixString)); err != nil {
+			return fmt.Errorf("failed to write FIX message to socket: %w", err)
+		}
+	} else {
+		fmt.Printf("FIX >> %s\n", fixString)
+	}
+
+	fe.persistSeqNums()
+	return nil
+}
+
+func (fe *FIXEngine) persistSeqNums() {
+	if fe.store == nil {
+		return
+	}
+	fe.seqMu.Lock()
+	seqNums := SessionSeqNums{NextInboundSeqNum: fe.nextInSeqNum, NextOutboundSeqNum: fe.nextOutSeqNum}
+	fe.seqMu.Unlock()
+	_ = fe.store.Save(fe.sessionID(), seqNums)
+}
+
+// readLoop reads SOH-delimited messages off conn and dispatches the admin
+// flows (Heartbeat/TestRequest/ResendRequest/SequenceReset) or forwards
+// application messages to registered handlers.
+func (fe *FIXEngine) readLoop(conn net.Conn) {
+	fe.readLoopFrom(bufio.NewReader(conn))
+}
+
+// readLoopFrom is readLoop's body, taking an already-buffered reader so
+// FIXAcceptor can hand off a connection after consuming its Logon message
+// without losing any bytes buffered past it.
+func (fe *FIXEngine) readLoopFrom(reader *bufio.Reader) {
+	for {
+		raw, err := readFIXMessage(reader)
+		if err != nil {
+			return
+		}
+		msg, err := FromFIXString(raw)
+		if err != nil {
+			continue
+		}
+		fe.onInbound(msg)
+	}
+}
+
+// onInbound advances the expected inbound sequence number, issuing a
+// ResendRequest(2) on a gap, and routes the message to the right admin
+// handler or application callback.
+func (fe *FIXEngine) onInbound(msg *FIXMessage) {
+	seqNum, _ := strconv.Atoi(msg.Fields["34"])
+
+	fe.seqMu.Lock()
+	expected := fe.nextInSeqNum
+	fe.seqMu.Unlock()
+
+	if seqNum > expected {
+		fe.sendResendRequest(expected, seqNum-1)
+	}
+
+	fe.seqMu.Lock()
+	if seqNum >= expected {
+		fe.nextInSeqNum = seqNum + 1
+	}
+	fe.seqMu.Unlock()
+	fe.persistSeqNums()
+
+	switch msg.MsgType {
+	case "1": // TestRequest -> answer with Heartbeat echoing TestReqID
+		fe.sendHeartbeat(msg.Fields["112"])
+	case "2": // ResendRequest -> reply with a GapFill SequenceReset
+		fe.sendSequenceReset(seqNum + 1)
+	case MessageTypeExecutionReport:
+		if handler, ok := fe.handler("execution"); ok {
+			_ = handler(msg)
+		}
+	case MessageTypeMarketDataSnapshot, MessageTypeMarketDataIncremental:
+		if handler, ok := fe.handler("market_data"); ok {
+			_ = handler(msg)
+		}
+	case MessageTypeNewOrderSingle:
+		if handler, ok := fe.handler("new_order_single"); ok {
+			_ = handler(msg)
+		}
+	case MessageTypeOrderCancelRequest:
+		if handler, ok := fe.handler("order_cancel_request"); ok {
+			_ = handler(msg)
+		}
+	}
+}
+
+func (fe *FIXEngine) sendHeartbeat(testReqID string) {
+	fields := map[string]string{"49": fe.senderCompID, "56": fe.targetCompID}
+	if testReqID != "" {
+		fields["112"] = testReqID
+	}
+	_ = fe.send(NewFIXMessage(MessageTypeHeartbeat, fields))
+}
+
+func (fe *FIXEngine) sendTestRequest(testReqID string) {
+	_ = fe.send(NewFIXMessage("1", map[string]string{
+		"49": fe.senderCompID, "56": fe.targetCompID, "112": testReqID,
+	}))
+}
+
+func (fe *FIXEngine) sendResendRequest(beginSeqNo, endSeqNo int) {
+	_ = fe.send(NewFIXMessage("2", map[string]string{
+		"49": fe.senderCompID, "56": fe.targetCompID,
+		"7": strconv.Itoa(beginSeqNo), "16": strconv.Itoa(endSeqNo),
+	}))
+}
+
+func (fe *FIXEngine) sendSequenceReset(newSeqNo int) {
+	_ = fe.send(NewFIXMessage("4", map[string]string{
+		"49": fe.senderCompID, "56": fe.targetCompID,
+		"36": strconv.Itoa(newSeqNo), "123": "Y", // GapFillFlag
+	}))
+}
+
+// heartbeatLoop sends a Heartbeat(0) every HeartBtInt seconds until Logout.
+func (fe *FIXEngine) heartbeatLoop() {
+	ticker := time.NewTicker(time.Duration(fe.heartBtInt) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			fe.sendHeartbeat("")
+		case <-fe.stopHeartbeat:
+			return
+		}
+	}
+}
+
+// SendNewOrderSingle sends a typed NewOrderSingle(D) and returns its ClOrdID.
+func (fe *FIXEngine) SendNewOrderSingle(order NewOrderSingle) (string, error) {
+	if !fe.isLoggedIn {
+		return "", fmt.Errorf("not logged in to FIX session")
+	}
+
+	clOrdID := uuid.New().String()[:8]
+
+	side := "1" // Buy
+	if order.Side == "SELL" {
+		side = "2"
+	}
+	ordType := "1" // Market
+	if order.OrderType == "LIMIT" {
+		ordType = "2"
+	}
+
+	account := order.Account
+	if account == "" {
+		account = "DEMO001"
+	}
+
+	fields := map[string]string{
+		"49": fe.senderCompID,
+		"56": fe.targetCompID,
+		"11": clOrdID,
+		"55": order.Symbol,
+		"54": side,
+		"38": fmt.Sprintf("%.0f", order.Quantity),
+		"40": ordType,
+		"59": "0", // TimeInForce (DAY)
+		"1":  account,
+	}
+	if order.Price != nil {
+		fields["44"] = fmt.Sprintf("%.2f", *order.Price)
+	}
+
+	if err := fe.send(NewFIXMessage(MessageTypeNewOrderSingle, fields)); err != nil {
+		return "", err
+	}
+
+	if fe.conn == nil {
+		// Demo/loopback mode: no real counterparty will send back an
+		// ExecutionReport, so synthesize one the way the old simulator did.
+		go fe.simulateExecutionReport(clOrdID, order)
+	}
+
+	return clOrdID, nil
+}
+
+// OnExecutionReport registers a typed callback invoked whenever an
+// ExecutionReport(8) is received (real or simulated).
+func (fe *FIXEngine) OnExecutionReport(callback func(ExecutionReport)) {
+	fe.RegisterHandler("execution", func(msg *FIXMessage) error {
+		orderQty, _ := strconv.ParseFloat(msg.Fields["38"], 64)
+		lastQty, _ := strconv.ParseFloat(msg.Fields["32"], 64)
+		lastPx, _ := strconv.ParseFloat(msg.Fields["31"], 64)
+		cumQty, _ := strconv.ParseFloat(msg.Fields["14"], 64)
+		avgPx, _ := strconv.ParseFloat(msg.Fields["6"], 64)
+		side := "BUY"
+		if msg.Fields["54"] == "2" {
+			side = "SELL"
+		}
+		callback(ExecutionReport{
+			ClOrdID:   msg.Fields["11"],
+			ExecID:    msg.Fields["17"],
+			Symbol:    msg.Fields["55"],
+			Side:      side,
+			OrderQty:  orderQty,
+			LastQty:   lastQty,
+			LastPx:    lastPx,
+			CumQty:    cumQty,
+			AvgPx:     avgPx,
+			OrdStatus: msg.Fields["39"],
+		})
+		return nil
+	})
+}
+
+// SendNewOrder is the legacy map-based entry point kept for callers (and
+// CommunicationManager.SendOrder) that haven't moved to SendNewOrderSingle.
+func (fe *FIXEngine) SendNewOrder(orderData map[string]interface{}) (string, error) {
+	order := NewOrderSingle{
+		Symbol:    orderData["symbol"].(string),
+		Side:      orderData["side"].(string),
+		OrderType: orderData["order_type"].(string),
+		Quantity:  orderData["quantity"].(float64),
+	}
+	if price, ok := orderData["price"]; ok && price != nil {
+		p := price.(float64)
+		order.Price = &p
+	}
+	if account, ok := orderData["account"]; ok {
+		order.Account = account.(string)
+	}
+	return fe.SendNewOrderSingle(order)
+}
+
+// SendOrderCancelRequest sends an OrderCancelRequest(F) referencing
+// origClOrdID and returns the new ClOrdID assigned to the cancel request.
+func (fe *FIXEngine) SendOrderCancelRequest(origClOrdID, symbol, side string) (string, error) {
+	if !fe.isLoggedIn {
+		return "", fmt.Errorf("not logged in to FIX session")
+	}
+
+	clOrdID := uuid.New().String()[:8]
+	sideTag := "1" // Buy
+	if side == "SELL" {
+		sideTag = "2"
+	}
+
+	fields := map[string]string{
+		"49": fe.senderCompID,
+		"56": fe.targetCompID,
+		"11": clOrdID,
+		"41": origClOrdID,
+		"55": symbol,
+		"54": sideTag,
+	}
+	if err := fe.send(NewFIXMessage(MessageTypeOrderCancelRequest, fields)); err != nil {
+		return "", err
+	}
+
+	if fe.conn == nil {
+		// Demo/loopback mode: no real counterparty will send back a
+		// Canceled ExecutionReport, so synthesize one.
+		go fe.simulateCancelExecutionReport(clOrdID, origClOrdID, symbol, sideTag)
+	}
+
+	return clOrdID, nil
+}
+
+// simulateCancelExecutionReport mimics a Canceled(39=4) ExecutionReport when
+// no real counterparty is wired up, mirroring simulateExecutionReport.
+func (fe *FIXEngine) simulateCancelExecutionReport(clOrdID, origClOrdID, symbol, sideTag string) {
+	time.Sleep(100 * time.Millisecond)
+
+	execReport := NewFIXMessage(MessageTypeExecutionReport, map[string]string{
+		"49":  fe.targetCompID,
+		"56":  fe.senderCompID,
+		"11":  clOrdID,
+		"41":  origClOrdID,
+		"17":  uuid.New().String()[:8],
+		"150": "4", // ExecType (Canceled)
+		"39":  "4", // OrdStatus (Canceled)
+		"55":  symbol,
+		"54":  sideTag,
+	})
+
+	if handler, ok := fe.handler("execution"); ok {
+		_ = handler(execReport)
+	}
+}
+
+// simulateExecutionReport mimics a fill when no real counterparty is wired
+// up, preserving the demo server's behavior of immediately "filling" orders.
+func (fe *FIXEngine) simulateExecutionReport(clOrdID string, order NewOrderSingle) {
+	time.Sleep(100 * time.Millisecond)
+
+	execID := uuid.New().String()[:8]
+
+	fillPrice := 2050.0
+	if order.Price != nil {
+		fillPrice = *order.Price
+	} else {
+		slippage := 0.1
+		if order.Side == "SELL" {
+			slippage = -0.1
+		}
+		fillPrice = 2050.0 + slippage
+	}
+
+	side := "1"
+	if order.Side == "SELL" {
+		side = "2"
+	}
+
+	execReport := NewFIXMessage(MessageTypeExecutionReport, map[string]string{
+		"49":  fe.targetCompID,
+		"56":  fe.senderCompID,
+		"11":  clOrdID,
+		"17":  execID,
+		"150": "F", // ExecType (Trade)
+		"39":  "2", // OrdStatus (Filled)
+		"55":  order.Symbol,
+		"54":  side,
+		"38":  fmt.Sprintf("%.0f", order.Quantity),
+		"32":  fmt.Sprintf("%.0f", order.Quantity),
+		"31":  fmt.Sprintf("%.2f", fillPrice),
+		"14":  fmt.Sprintf("%.0f", order.Quantity),
+		"6":   fmt.Sprintf("%.2f", fillPrice),
+	})
+
+	if handler, ok := fe.handler("execution"); ok {
+		_ = handler(execReport)
+	}
+}
+
+// SubscribeMarketData subscribes to market data feeds for symbols.
+func (fe *FIXEngine) SubscribeMarketData(symbols []string) error {
+	if !fe.isLoggedIn {
+		return fmt.Errorf("not logged in to FIX session")
+	}
+
+	for _, symbol := range symbols {
+		reqID := uuid.New().String()[:8]
+		mdRequest := NewFIXMessage(MessageTypeMarketDataSnapshot, map[string]string{
+			"49":  fe.senderCompID,
+			"56":  fe.targetCompID,
+			"262": reqID,
+			"263": "1", // SubscriptionRequestType (Snapshot + Updates)
+			"264": "1", // MarketDepth
+			"267": "2", // NoMDEntryTypes
+			"269": "0", // MDEntryType (Bid) — request structure simplified to one entry type field
+			"146": "1", // NoRelatedSym
+			"55":  symbol,
+		})
+		if err := fe.send(mdRequest); err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", symbol, err)
+		}
+	}
+
+	if fe.conn == nil {
+		go fe.simulateMarketData(symbols)
+	}
+	return nil
+}
+
+// simulateMarketData simulates market data updates when no real market data
+// source is connected (demo mode).
+func (fe *FIXEngine) simulateMarketData(symbols []string) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !fe.isLoggedIn {
+			return
+		}
+
+		for _, symbol := range symbols {
+			basePrice := 2050.0
+			priceChange := float64(time.Now().UnixNano()%1000-500) / 250.0
+			bidPrice := basePrice + priceChange - 0.5
+			askPrice := basePrice + priceChange + 0.5
+
+			mdUpdate := NewFIXMessage(MessageTypeMarketDataIncremental, map[string]string{
+				"49":  fe.targetCompID,
+				"56":  fe.senderCompID,
+				"55":  symbol,
+				"268": "2",
+				"269": "0",
+				"270": fmt.Sprintf("%.2f", bidPrice),
+				"271": "100",
+				"272": fmt.Sprintf("%.2f", askPrice),
+			})
+
+			if handler, ok := fe.handler("market_data"); ok {
+				_ = handler(mdUpdate)
+			}
+		}
+	}
+}
+
+// Logout performs FIX logout, stopping the heartbeat loop and closing the
+// socket (if one was opened).
+func (fe *FIXEngine) Logout() error {
+	if !fe.isLoggedIn {
+		return nil
+	}
+
+	if err := fe.send(NewFIXMessage(MessageTypeLogout, map[string]string{
+		"49": fe.senderCompID, "56": fe.targetCompID,
+	})); err != nil {
+		return err
+	}
+
+	close(fe.stopHeartbeat)
+	fe.isLoggedIn = false
+
+	fe.connMu.Lock()
+	if fe.conn != nil {
+		_ = fe.conn.Close()
+		fe.conn = nil
+	}
+	fe.connMu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,293 @@
+package communication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"gorm.io/gorm"
+)
+
+// SessionSeqNums holds the inbound/outbound sequence numbers for one FIX
+// session, keyed by SenderCompID/TargetCompID pair.
+type SessionSeqNums struct {
+	NextInboundSeqNum  int `json:"next_inbound_seq_num"`
+	NextOutboundSeqNum int `json:"next_outbound_seq_num"`
+}
+
+// SessionStore persists FIX sequence numbers across reconnects so a session
+// can honor gap-fill and PossDupFlag(43) instead of resetting to 1. It also
+// persists ExitController's per-position trailing-stop tier state, so a
+// restart doesn't lose a position's high-water mark.
+type SessionStore interface {
+	Load(sessionID string) (SessionSeqNums, error)
+	Save(sessionID string, seqNums SessionSeqNums) error
+	LoadPositionState(positionID string) (PositionTierState, bool, error)
+	SavePositionState(positionID string, state PositionTierState) error
+}
+
+// PositionTierState is ExitController's per-position trailing-stop state:
+// the entry price, the best (most favorable) price seen since entry, and
+// the highest trailing tier activated so far.
+type PositionTierState struct {
+	Symbol       string    `json:"symbol"`
+	Side         string    `json:"side"` // "LONG" or "SHORT"
+	EntryPrice   float64   `json:"entry_price"`
+	Quantity     float64   `json:"quantity"`
+	ExtremePrice float64   `json:"extreme_price"` // running max (LONG) or min (SHORT) favorable price
+	ActiveTier   int       `json:"active_tier"`   // highest activated tier index, -1 if none
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// FileSessionStore persists sequence numbers as one JSON file per session
+// under a configurable directory.
+type FileSessionStore struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewFileSessionStore creates a FileSessionStore rooted at dir.
+func NewFileSessionStore(dir string) (*FileSessionStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create session store directory: %w", err)
+	}
+	return &FileSessionStore{dir: dir}, nil
+}
+
+// Load returns the stored sequence numbers for sessionID, or fresh ones
+// starting at 1 if the session has never been seen.
+func (fs *FileSessionStore) Load(sessionID string) (SessionSeqNums, error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	data, err := os.ReadFile(fs.path(sessionID))
+	if os.IsNotExist(err) {
+		return SessionSeqNums{NextInboundSeqNum: 1, NextOutboundSeqNum: 1}, nil
+	} else if err != nil {
+		return SessionSeqNums{}, fmt.Errorf("failed to read session state for %s: %w", sessionID, err)
+	}
+
+	var seqNums SessionSeqNums
+	if err := json.Unmarshal(data, &seqNums); err != nil {
+		return SessionSeqNums{}, fmt.Errorf("failed to parse session state for %s: %w", sessionID, err)
+	}
+	return seqNums, nil
+}
+
+// Save persists seqNums for sessionID.
+func (fs *FileSessionStore) Save(sessionID string, seqNums SessionSeqNums) error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	data, err := json.Marshal(seqNums)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session state: %w", err)
+	}
+	return os.WriteFile(fs.path(sessionID), data, 0644)
+}
+
+func (fs *FileSessionStore) path(sessionID string) string {
+	return filepath.Join(fs.dir, sessionID+".json")
+}
+
+// LoadPositionState returns the stored tier state for positionID, or
+// false if none has been saved yet.
+func (fs *FileSessionStore) LoadPositionState(positionID string) (PositionTierState, bool, error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	data, err := os.ReadFile(fs.positionPath(positionID))
+	if os.IsNotExist(err) {
+		return PositionTierState{}, false, nil
+	} else if err != nil {
+		return PositionTierState{}, false, fmt.Errorf("failed to read position state for %s: %w", positionID, err)
+	}
+
+	var state PositionTierState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return PositionTierState{}, false, fmt.Errorf("failed to parse position state for %s: %w", positionID, err)
+	}
+	return state, true, nil
+}
+
+// SavePositionState persists state for positionID.
+func (fs *FileSessionStore) SavePositionState(positionID string, state PositionTierState) error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal position state: %w", err)
+	}
+	return os.WriteFile(fs.positionPath(positionID), data, 0644)
+}
+
+func (fs *FileSessionStore) positionPath(positionID string) string {
+	return filepath.Join(fs.dir, "position_"+positionID+".json")
+}
+
+// RedisSessionStore persists sequence numbers in Redis, keyed under a
+// configurable prefix, so multiple gateway instances can share session
+// state.
+type RedisSessionStore struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisSessionStore creates a RedisSessionStore against addr (host:port).
+func NewRedisSessionStore(addr string, db int, prefix string) *RedisSessionStore {
+	return &RedisSessionStore{
+		client: redis.NewClient(&redis.Options{Addr: addr, DB: db}),
+		prefix: prefix,
+		ttl:    30 * 24 * time.Hour,
+	}
+}
+
+// Load returns the stored sequence numbers for sessionID, or fresh ones
+// starting at 1 if the key doesn't exist.
+func (rs *RedisSessionStore) Load(sessionID string) (SessionSeqNums, error) {
+	ctx := context.Background()
+	raw, err := rs.client.Get(ctx, rs.key(sessionID)).Bytes()
+	if err == redis.Nil {
+		return SessionSeqNums{NextInboundSeqNum: 1, NextOutboundSeqNum: 1}, nil
+	} else if err != nil {
+		return SessionSeqNums{}, fmt.Errorf("failed to load session state from redis: %w", err)
+	}
+
+	var seqNums SessionSeqNums
+	if err := json.Unmarshal(raw, &seqNums); err != nil {
+		return SessionSeqNums{}, fmt.Errorf("failed to parse session state from redis: %w", err)
+	}
+	return seqNums, nil
+}
+
+// Save persists seqNums for sessionID.
+func (rs *RedisSessionStore) Save(sessionID string, seqNums SessionSeqNums) error {
+	data, err := json.Marshal(seqNums)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session state: %w", err)
+	}
+	return rs.client.Set(context.Background(), rs.key(sessionID), data, rs.ttl).Err()
+}
+
+func (rs *RedisSessionStore) key(sessionID string) string {
+	return rs.prefix + ":" + sessionID
+}
+
+// LoadPositionState returns the stored tier state for positionID, or false
+// if none has been saved yet.
+func (rs *RedisSessionStore) LoadPositionState(positionID string) (PositionTierState, bool, error) {
+	ctx := context.Background()
+	raw, err := rs.client.Get(ctx, rs.positionKey(positionID)).Bytes()
+	if err == redis.Nil {
+		return PositionTierState{}, false, nil
+	} else if err != nil {
+		return PositionTierState{}, false, fmt.Errorf("failed to load position state from redis: %w", err)
+	}
+
+	var state PositionTierState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return PositionTierState{}, false, fmt.Errorf("failed to parse position state from redis: %w", err)
+	}
+	return state, true, nil
+}
+
+// SavePositionState persists state for positionID.
+func (rs *RedisSessionStore) SavePositionState(positionID string, state PositionTierState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal position state: %w", err)
+	}
+	return rs.client.Set(context.Background(), rs.positionKey(positionID), data, rs.ttl).Err()
+}
+
+func (rs *RedisSessionStore) positionKey(positionID string) string {
+	return rs.prefix + ":position:" + positionID
+}
+
+// DatabaseSessionStore persists sequence numbers and position tier state in
+// the same SQLite database storage.DatabaseManager manages for every other
+// domain table, so a FIX gateway's session state survives a restart without
+// needing a separate file or Redis instance.
+type DatabaseSessionStore struct {
+	db *gorm.DB
+}
+
+// NewDatabaseSessionStore creates a DatabaseSessionStore against db (typically
+// storage.DatabaseManager.GetDB()).
+func NewDatabaseSessionStore(db *gorm.DB) *DatabaseSessionStore {
+	return &DatabaseSessionStore{db: db}
+}
+
+// Load returns the stored sequence numbers for sessionID, or fresh ones
+// starting at 1 if the session has never been seen.
+func (ds *DatabaseSessionStore) Load(sessionID string) (SessionSeqNums, error) {
+	var record core.FIXSessionRecord
+	err := ds.db.Where("session_id = ?", sessionID).First(&record).Error
+	if err == gorm.ErrRecordNotFound {
+		return SessionSeqNums{NextInboundSeqNum: 1, NextOutboundSeqNum: 1}, nil
+	} else if err != nil {
+		return SessionSeqNums{}, fmt.Errorf("failed to load session state for %s: %w", sessionID, err)
+	}
+	return SessionSeqNums{
+		NextInboundSeqNum:  record.NextInboundSeqNum,
+		NextOutboundSeqNum: record.NextOutboundSeqNum,
+	}, nil
+}
+
+// Save persists seqNums for sessionID, creating the record the first time.
+func (ds *DatabaseSessionStore) Save(sessionID string, seqNums SessionSeqNums) error {
+	record := core.FIXSessionRecord{
+		SessionID:          sessionID,
+		NextInboundSeqNum:  seqNums.NextInboundSeqNum,
+		NextOutboundSeqNum: seqNums.NextOutboundSeqNum,
+	}
+	return ds.db.Where("session_id = ?", sessionID).
+		Assign(record).
+		FirstOrCreate(&core.FIXSessionRecord{}, "session_id = ?", sessionID).Error
+}
+
+// LoadPositionState returns the stored tier state for positionID, or false
+// if none has been saved yet.
+func (ds *DatabaseSessionStore) LoadPositionState(positionID string) (PositionTierState, bool, error) {
+	var record core.FIXPositionTierRecord
+	err := ds.db.Where("position_id = ?", positionID).First(&record).Error
+	if err == gorm.ErrRecordNotFound {
+		return PositionTierState{}, false, nil
+	} else if err != nil {
+		return PositionTierState{}, false, fmt.Errorf("failed to load position state for %s: %w", positionID, err)
+	}
+	return PositionTierState{
+		Symbol:       record.Symbol,
+		Side:         record.Side,
+		EntryPrice:   record.EntryPrice,
+		Quantity:     record.Quantity,
+		ExtremePrice: record.ExtremePrice,
+		ActiveTier:   record.ActiveTier,
+		UpdatedAt:    record.UpdatedAt,
+	}, true, nil
+}
+
+// SavePositionState persists state for positionID, creating the record the
+// first time.
+func (ds *DatabaseSessionStore) SavePositionState(positionID string, state PositionTierState) error {
+	record := core.FIXPositionTierRecord{
+		PositionID:   positionID,
+		Symbol:       state.Symbol,
+		Side:         state.Side,
+		EntryPrice:   state.EntryPrice,
+		Quantity:     state.Quantity,
+		ExtremePrice: state.ExtremePrice,
+		ActiveTier:   state.ActiveTier,
+	}
+	return ds.db.Where("position_id = ?", positionID).
+		Assign(record).
+		FirstOrCreate(&core.FIXPositionTierRecord{}, "position_id = ?", positionID).Error
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// InstrumentContractType classifies a futures contract by its delivery
+// cycle, mirroring the this_week/next_week/quarter naming exchanges use for
+// rolling futures listings.
+type InstrumentContractType string
+
+const (
+	InstrumentContractThisWeek InstrumentContractType = "this_week"
+	InstrumentContractNextWeek InstrumentContractType = "next_week"
+	InstrumentContractQuarter  InstrumentContractType = "quarter"
+)
+
+// InstrumentInfo holds the contract-level trading constraints for one
+// symbol: the price/quantity granularity an order must align to, the
+// notional value of one contract, and when it stops trading.
+type InstrumentInfo struct {
+	Symbol         string                 `json:"symbol"`
+	PriceTickSize  float64                `json:"price_tick_size"`
+	AmountTickSize float64                `json:"amount_tick_size"`
+	ContractVal    float64                `json:"contract_val"`
+	Delivery       time.Time              `json:"delivery"`
+	ContractType   InstrumentContractType `json:"contract_type"`
+}
+
+// IsExpired reports whether asOf is on or after the instrument's delivery
+// date, meaning no new orders should be accepted against it.
+func (i InstrumentInfo) IsExpired(asOf time.Time) bool {
+	return !i.Delivery.IsZero() && !asOf.Before(i.Delivery)
+}
+
+// AlignsToPriceTick reports whether price is an integer multiple of
+// PriceTickSize, within floating-point rounding tolerance.
+func (i InstrumentInfo) AlignsToPriceTick(price float64) bool {
+	return alignsToTick(price, i.PriceTickSize)
+}
+
+// AlignsToAmountTick reports whether quantity is an integer multiple of
+// AmountTickSize, within floating-point rounding tolerance.
+func (i InstrumentInfo) AlignsToAmountTick(quantity float64) bool {
+	return alignsToTick(quantity, i.AmountTickSize)
+}
+
+// alignsToTick reports whether value is within 1e-8 of an integer multiple
+// of tick, treating a non-positive tick as "no constraint".
+func alignsToTick(value, tick float64) bool {
+	if tick <= 0 {
+		return true
+	}
+	remainder := math.Mod(value, tick)
+	return remainder < 1e-8 || tick-remainder < 1e-8
+}
+
+// InstrumentRegistry is a concurrency-safe, symbol-keyed lookup of
+// InstrumentInfo, loaded from JSON at startup and consulted by order
+// validation so limit prices, quantities, and expired contracts are
+// rejected before they ever reach the matching engine.
+type InstrumentRegistry struct {
+	mutex       sync.RWMutex
+	instruments map[string]InstrumentInfo
+}
+
+// NewInstrumentRegistry creates an empty InstrumentRegistry; call Load to
+// populate it.
+func NewInstrumentRegistry() *InstrumentRegistry {
+	return &InstrumentRegistry{instruments: make(map[string]InstrumentInfo)}
+}
+
+// Load replaces the registry's contents with instruments, keyed by Symbol.
+func (r *InstrumentRegistry) Load(instruments []InstrumentInfo) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.instruments = make(map[string]InstrumentInfo, len(instruments))
+	for _, inst := range instruments {
+		r.instruments[inst.Symbol] = inst
+	}
+}
+
+// Get returns the InstrumentInfo for symbol, if registered.
+func (r *InstrumentRegistry) Get(symbol string) (InstrumentInfo, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	inst, ok := r.instruments[symbol]
+	return inst, ok
+}
+
+// All returns every registered instrument.
+func (r *InstrumentRegistry) All() []InstrumentInfo {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	instruments := make([]InstrumentInfo, 0, len(r.instruments))
+	for _, inst := range r.instruments {
+		instruments = append(instruments, inst)
+	}
+	return instruments
+}
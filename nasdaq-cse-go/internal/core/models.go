@@ -17,19 +17,21 @@ const (
 type OrderType string
 
 const (
-	OrderTypeMarket OrderType = "MARKET"
-	OrderTypeLimit  OrderType = "LIMIT"
-	OrderTypeStop   OrderType = "STOP"
+	OrderTypeMarket    OrderType = "MARKET"
+	OrderTypeLimit     OrderType = "LIMIT"
+	OrderTypeStop      OrderType = "STOP"       // triggers a market order once StopPrice trades
+	OrderTypeStopLimit OrderType = "STOP_LIMIT" // triggers a limit order at Price once StopPrice trades
 )
 
 // OrderStatus represents the status of an order
 type OrderStatus string
 
 const (
-	OrderStatusPending   OrderStatus = "PENDING"
-	OrderStatusFilled    OrderStatus = "FILLED"
-	OrderStatusCancelled OrderStatus = "CANCELLED"
-	OrderStatusRejected  OrderStatus = "REJECTED"
+	OrderStatusPending         OrderStatus = "PENDING"
+	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
+	OrderStatusFilled          OrderStatus = "FILLED"
+	OrderStatusCancelled       OrderStatus = "CANCELLED"
+	OrderStatusRejected        OrderStatus = "REJECTED"
 )
 
 // ContractType represents the type of trading contract
@@ -45,6 +47,7 @@ type User struct {
 	ID              uint      `gorm:"primaryKey" json:"id"`
 	Username        string    `gorm:"unique;not null" json:"username"`
 	Email           string    `gorm:"unique;not null" json:"email"`
+	PasswordHash    string    `gorm:"not null" json:"-"`
 	AccountBalance  float64   `gorm:"default:100000.0" json:"account_balance"`
 	MarginAvailable float64   `gorm:"default:100000.0" json:"margin_available"`
 	CreatedAt       time.Time `json:"created_at"`
@@ -54,35 +57,36 @@ type User struct {
 
 // Contract represents a trading contract
 type Contract struct {
-	ID                 uint         `gorm:"primaryKey" json:"id"`
-	Symbol             string       `gorm:"index;not null" json:"symbol"`
-	ContractType       ContractType `json:"contract_type"`
-	ExpiryDate         time.Time    `json:"expiry_date"`
-	ContractSize       float64      `json:"contract_size"`       // Troy ounces per contract
-	TickSize           float64      `gorm:"default:0.01" json:"tick_size"`
-	InitialMargin      float64      `json:"initial_margin"`
-	MaintenanceMargin  float64      `json:"maintenance_margin"`
-	CreatedAt          time.Time    `json:"created_at"`
-	UpdatedAt          time.Time    `json:"updated_at"`
-	IsActive           bool         `gorm:"default:true" json:"is_active"`
+	ID                uint         `gorm:"primaryKey" json:"id"`
+	Symbol            string       `gorm:"index;not null" json:"symbol"`
+	ContractType      ContractType `json:"contract_type"`
+	ExpiryDate        time.Time    `json:"expiry_date"`
+	ContractSize      float64      `json:"contract_size"` // Troy ounces per contract
+	TickSize          float64      `gorm:"default:0.01" json:"tick_size"`
+	InitialMargin     float64      `json:"initial_margin"`
+	MaintenanceMargin float64      `json:"maintenance_margin"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+	IsActive          bool         `gorm:"default:true" json:"is_active"`
 }
 
 // Order represents a trading order
 type Order struct {
-	ID              uint        `gorm:"primaryKey" json:"id"`
-	OrderID         string      `gorm:"unique;not null" json:"order_id"`
-	UserID          uint        `gorm:"not null" json:"user_id"`
-	ContractID      uint        `gorm:"not null" json:"contract_id"`
-	Side            OrderSide   `json:"side"`
-	OrderType       OrderType   `json:"order_type"`
-	Quantity        float64     `json:"quantity"`
-	Price           *float64    `json:"price,omitempty"`
-	StopPrice       *float64    `json:"stop_price,omitempty"`
-	Status          OrderStatus `json:"status"`
-	FilledQuantity  float64     `gorm:"default:0" json:"filled_quantity"`
-	AvgFillPrice    *float64    `json:"avg_fill_price,omitempty"`
-	CreatedAt       time.Time   `json:"created_at"`
-	UpdatedAt       time.Time   `json:"updated_at"`
+	ID             uint        `gorm:"primaryKey" json:"id"`
+	OrderID        string      `gorm:"unique;not null" json:"order_id"`
+	UserID         uint        `gorm:"not null" json:"user_id"`
+	ContractID     uint        `gorm:"not null" json:"contract_id"`
+	Side           OrderSide   `json:"side"`
+	OrderType      OrderType   `json:"order_type"`
+	Quantity       float64     `json:"quantity"`
+	Price          *float64    `json:"price,omitempty"`
+	StopPrice      *float64    `json:"stop_price,omitempty"`
+	Status         OrderStatus `json:"status"`
+	FilledQuantity float64     `gorm:"default:0" json:"filled_quantity"`
+	AvgFillPrice   *float64    `json:"avg_fill_price,omitempty"`
+	Strategy       string      `gorm:"index" json:"strategy,omitempty"` // free-form tag identifying who placed the order, e.g. "manual", "grid"
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
 
 	// Associations
 	User     User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
@@ -91,15 +95,17 @@ type Order struct {
 
 // Trade represents an executed trade
 type Trade struct {
-	ID           uint       `gorm:"primaryKey" json:"id"`
-	TradeID      string     `gorm:"unique;not null" json:"trade_id"`
-	BuyOrderID   *string    `json:"buy_order_id,omitempty"`
-	SellOrderID  *string    `json:"sell_order_id,omitempty"`
-	ContractID   uint       `gorm:"not null" json:"contract_id"`
-	Quantity     float64    `json:"quantity"`
-	Price        float64    `json:"price"`
-	TradeTime    time.Time  `json:"trade_time"`
-	CreatedAt    time.Time  `json:"created_at"`
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TradeID     string    `gorm:"unique;not null" json:"trade_id"`
+	BuyOrderID  *string   `json:"buy_order_id,omitempty"`
+	SellOrderID *string   `json:"sell_order_id,omitempty"`
+	ContractID  uint      `gorm:"not null" json:"contract_id"`
+	Quantity    float64   `json:"quantity"`
+	Price       float64   `json:"price"`
+	PnL         *float64  `json:"pnl,omitempty"`                   // realized P&L this fill contributed to the closing side of a position
+	Strategy    string    `gorm:"index" json:"strategy,omitempty"` // inherited from the aggressing order that produced this fill
+	TradeTime   time.Time `json:"trade_time"`
+	CreatedAt   time.Time `json:"created_at"`
 
 	// Associations
 	Contract Contract `gorm:"foreignKey:ContractID" json:"contract,omitempty"`
@@ -107,17 +113,17 @@ type Trade struct {
 
 // Position represents a user's position in a contract
 type Position struct {
-	ID                 uint      `gorm:"primaryKey" json:"id"`
-	UserID             uint      `gorm:"not null" json:"user_id"`
-	ContractID         uint      `gorm:"not null" json:"contract_id"`
-	Quantity           float64   `json:"quantity"`
-	AvgEntryPrice      float64   `json:"avg_entry_price"`
-	UnrealizedPnL      float64   `gorm:"default:0" json:"unrealized_pnl"`
-	RealizedPnL        float64   `gorm:"default:0" json:"realized_pnl"`
-	MarginRequirement  float64   `json:"margin_requirement"`
-	LastUpdated        time.Time `json:"last_updated"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	UserID            uint      `gorm:"not null" json:"user_id"`
+	ContractID        uint      `gorm:"not null" json:"contract_id"`
+	Quantity          float64   `json:"quantity"`
+	AvgEntryPrice     float64   `json:"avg_entry_price"`
+	UnrealizedPnL     float64   `gorm:"default:0" json:"unrealized_pnl"`
+	RealizedPnL       float64   `gorm:"default:0" json:"realized_pnl"`
+	MarginRequirement float64   `json:"margin_requirement"`
+	LastUpdated       time.Time `json:"last_updated"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 
 	// Associations
 	User     User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
@@ -126,16 +132,16 @@ type Position struct {
 
 // MarketData represents market data for a contract
 type MarketData struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	ContractID   uint      `gorm:"not null" json:"contract_id"`
-	Price        float64   `json:"price"`
-	Bid          float64   `json:"bid"`
-	Ask          float64   `json:"ask"`
-	Volume       int64     `json:"volume"`
-	Change24h    float64   `json:"change_24h"`
-	ChangePercent float64  `json:"change_percent"`
-	Timestamp    time.Time `json:"timestamp"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ContractID    uint      `gorm:"not null" json:"contract_id"`
+	Price         float64   `json:"price"`
+	Bid           float64   `json:"bid"`
+	Ask           float64   `json:"ask"`
+	Volume        int64     `json:"volume"`
+	Change24h     float64   `json:"change_24h"`
+	ChangePercent float64   `json:"change_percent"`
+	Timestamp     time.Time `json:"timestamp"`
+	CreatedAt     time.Time `json:"created_at"`
 
 	// Associations
 	Contract Contract `gorm:"foreignKey:ContractID" json:"contract,omitempty"`
@@ -143,20 +149,139 @@ type MarketData struct {
 
 // AIAnalysis represents AI-generated analysis
 type AIAnalysis struct {
-	ID              uint      `gorm:"primaryKey" json:"id"`
-	UserID          uint      `gorm:"not null" json:"user_id"`
-	AnalysisType    string    `json:"analysis_type"`
-	PredictedDirection string `json:"predicted_direction"`
-	ConfidenceScore float64   `json:"confidence_score"`
-	Suggestion      string    `json:"suggestion"`
-	RiskLevel       string    `json:"risk_level"`
-	Timestamp       time.Time `json:"timestamp"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID                 uint      `gorm:"primaryKey" json:"id"`
+	UserID             uint      `gorm:"not null" json:"user_id"`
+	AnalysisType       string    `json:"analysis_type"`
+	PredictedDirection string    `json:"predicted_direction"`
+	ConfidenceScore    float64   `json:"confidence_score"`
+	Suggestion         string    `json:"suggestion"`
+	RiskLevel          string    `json:"risk_level"`
+	Timestamp          time.Time `json:"timestamp"`
+	CreatedAt          time.Time `json:"created_at"`
 
 	// Associations
 	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
 
+// CircuitBreakerState persists RiskManager's per-user EMA circuit-breaker
+// state (see rms.RiskManager.EvaluateCircuitBreaker) so a restart doesn't
+// forget an open circuit.
+type CircuitBreakerState struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	UserID           uint       `gorm:"uniqueIndex;not null" json:"user_id"`
+	State            string     `gorm:"default:closed" json:"state"` // "closed", "half-open", or "open"
+	EMA              float64    `json:"ema"`
+	ProbeBaselineEMA float64    `json:"probe_baseline_ema"`
+	ProbeConsumed    bool       `json:"probe_consumed"`
+	LastBucketStart  time.Time  `json:"last_bucket_start"`
+	OpenedAt         *time.Time `json:"opened_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// MarginLoanAction distinguishes a MarginLoanRecord borrow from a repay.
+type MarginLoanAction string
+
+const (
+	MarginLoanActionBorrow MarginLoanAction = "BORROW"
+	MarginLoanActionRepay  MarginLoanAction = "REPAY"
+)
+
+// MarginLoanRecord logs a single auto-borrow or auto-repay performed by
+// rms.MarginCreditManager on a user's behalf.
+type MarginLoanRecord struct {
+	ID            uint             `gorm:"primaryKey" json:"id"`
+	UserID        uint             `gorm:"index;not null" json:"user_id"`
+	TransactionID string           `gorm:"unique;not null" json:"transaction_id"`
+	Action        MarginLoanAction `json:"action"`
+	Asset         string           `gorm:"index;not null" json:"asset"`
+	Principle     float64          `json:"principle"`
+	Time          time.Time        `json:"time"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// MarginInterestRecord logs interest accrued against an outstanding
+// MarginLoanRecord. Repayments reuse the same table with Principle set to
+// the amount repaid and Interest left at zero.
+type MarginInterestRecord struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"index;not null" json:"user_id"`
+	TransactionID string    `gorm:"unique;not null" json:"transaction_id"`
+	Asset         string    `gorm:"index;not null" json:"asset"`
+	Principle     float64   `json:"principle"`
+	Interest      float64   `json:"interest"`
+	InterestRate  float64   `json:"interest_rate"`
+	Time          time.Time `json:"time"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// HedgeLeg records a cross-exchange hedge opened by rms.CrossHedger against
+// one of a user's primary-venue positions.
+type HedgeLeg struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"index;not null" json:"user_id"`
+	ContractID uint       `gorm:"index;not null" json:"contract_id"`
+	Venue      string     `json:"venue"`
+	Quantity   float64    `json:"quantity"`
+	EntryPrice float64    `json:"entry_price"`
+	OpenedAt   time.Time  `json:"opened_at"`
+	ClosedAt   *time.Time `json:"closed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// PriceHistory is a daily close used by rms.HistoricalSimulationVaR to build
+// per-position PnL vectors.
+type PriceHistory struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ContractID uint      `gorm:"index;not null" json:"contract_id"`
+	Price      float64   `json:"price"`
+	Date       time.Time `gorm:"index" json:"date"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OrderFlowSnapshot persists one interval's buy/sell aggressor imbalance as
+// computed by aiassistant.OrderFlowAnalyzer: BuyVolume/SellVolume are that
+// interval's totals, CVD is the cumulative signed volume (buy-sell) across
+// every interval up to and including this one, and ImbalanceRatio is
+// (buy-sell)/(buy+sell) for this interval alone.
+type OrderFlowSnapshot struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	ContractID     uint      `gorm:"index;not null" json:"contract_id"`
+	IntervalStart  time.Time `gorm:"index" json:"interval_start"`
+	BuyVolume      float64   `json:"buy_volume"`
+	SellVolume     float64   `json:"sell_volume"`
+	CVD            float64   `json:"cvd"`
+	ImbalanceRatio float64   `json:"imbalance_ratio"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// FIXSessionRecord persists one FIX session's sequence-number state across
+// restarts, keyed by the SenderCompID-TargetCompID pair a
+// communication.FIXEngine uses as its session ID, so a reconnect can
+// gap-fill instead of resetting to 1.
+type FIXSessionRecord struct {
+	ID                 uint      `gorm:"primaryKey" json:"id"`
+	SessionID          string    `gorm:"uniqueIndex;not null" json:"session_id"`
+	NextInboundSeqNum  int       `json:"next_inbound_seq_num"`
+	NextOutboundSeqNum int       `json:"next_outbound_seq_num"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// FIXPositionTierRecord persists one position's trailing-stop tier state
+// (see communication.PositionTierState) across restarts.
+type FIXPositionTierRecord struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	PositionID   string    `gorm:"uniqueIndex;not null" json:"position_id"`
+	Symbol       string    `json:"symbol"`
+	Side         string    `json:"side"`
+	EntryPrice   float64   `json:"entry_price"`
+	Quantity     float64   `json:"quantity"`
+	ExtremePrice float64   `json:"extreme_price"`
+	ActiveTier   int       `json:"active_tier"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
 // API Request/Response Models
 
 // UserCreateRequest represents a request to create a new user
@@ -167,23 +292,26 @@ type UserCreateRequest struct {
 
 // OrderCreateRequest represents a request to create a new order
 type OrderCreateRequest struct {
-	ContractSymbol string     `json:"contract_symbol" validate:"required"`
-	Side           OrderSide  `json:"side" validate:"required"`
-	OrderType      OrderType  `json:"order_type" validate:"required"`
-	Quantity       float64    `json:"quantity" validate:"required,gt=0"`
-	Price          *float64   `json:"price,omitempty" validate:"omitempty,gt=0"`
-	StopPrice      *float64   `json:"stop_price,omitempty" validate:"omitempty,gt=0"`
+	ContractSymbol string    `json:"contract_symbol" validate:"required"`
+	Side           OrderSide `json:"side" validate:"required"`
+	OrderType      OrderType `json:"order_type" validate:"required"`
+	Quantity       float64   `json:"quantity" validate:"required,gt=0"`
+	Price          *float64  `json:"price,omitempty" validate:"omitempty,gt=0"`
+	StopPrice      *float64  `json:"stop_price,omitempty" validate:"omitempty,gt=0"`
+	Strategy       string    `json:"strategy,omitempty"` // e.g. "manual", "grid", "calendar_arb"; defaults to "manual" if left blank
 }
 
-// ChatMessageRequest represents a chat message to the AI assistant
+// ChatMessageRequest represents a chat message to the AI assistant. The
+// acting user comes from the authenticated session, not the request body.
 type ChatMessageRequest struct {
 	Message string `json:"message" validate:"required,max=500"`
-	UserID  uint   `json:"user_id" validate:"required"`
 }
 
-// TradingContextRequest represents trading context for AI analysis
+// TradingContextRequest represents trading context for AI analysis. It
+// currently carries no fields of its own: the acting user comes from the
+// authenticated session, and the market data analyzed is always the
+// server's current tick.
 type TradingContextRequest struct {
-	UserID uint `json:"user_id" validate:"required"`
 }
 
 // MarketDataResponse represents current market data
@@ -208,4 +336,4 @@ type ChartDataPoint struct {
 type ChartDataResponse struct {
 	Data []ChartDataPoint `json:"data"`
 	Type string           `json:"type"`
-}
\ No newline at end of file
+}
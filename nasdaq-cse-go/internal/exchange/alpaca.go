@@ -0,0 +1,347 @@
+package exchange
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// AlpacaConnector speaks Alpaca's REST trading API (key/secret headers, no
+// request signing) and its market-data streaming websocket.
+type AlpacaConnector struct {
+	apiKeyID  string
+	apiSecret string
+	baseURL   string // trading REST, e.g. https://paper-api.alpaca.markets
+	streamURL string // market-data websocket, e.g. wss://stream.data.alpaca.markets/v2/iex
+	dataURL   string // market-data REST, e.g. https://data.alpaca.markets
+	client    *http.Client
+}
+
+// NewAlpacaConnector creates an AlpacaConnector against Alpaca's paper
+// trading endpoints by default; call SetBaseURL to point at live trading.
+func NewAlpacaConnector(apiKeyID, apiSecret string) *AlpacaConnector {
+	return &AlpacaConnector{
+		apiKeyID:  apiKeyID,
+		apiSecret: apiSecret,
+		baseURL:   "https://paper-api.alpaca.markets",
+		streamURL: "wss://stream.data.alpaca.markets/v2/iex",
+		dataURL:   "https://data.alpaca.markets",
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SetBaseURL overrides the trading REST endpoint (e.g. for live trading at
+// https://api.alpaca.markets).
+func (a *AlpacaConnector) SetBaseURL(baseURL string) {
+	a.baseURL = baseURL
+}
+
+// Name returns the connector's identifier.
+func (a *AlpacaConnector) Name() string {
+	return "alpaca"
+}
+
+func (a *AlpacaConnector) authHeaders(req *http.Request) {
+	req.Header.Set("APCA-API-KEY-ID", a.apiKeyID)
+	req.Header.Set("APCA-API-SECRET-KEY", a.apiSecret)
+}
+
+// Authenticate verifies apiKeyID/apiSecret against Alpaca's account
+// endpoint.
+func (a *AlpacaConnector) Authenticate(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.baseURL+"/v2/account", nil)
+	if err != nil {
+		return fmt.Errorf("alpaca: failed to build account request: %w", err)
+	}
+	a.authHeaders(req)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("alpaca: authentication request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("alpaca: authentication failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+type alpacaTradeMessage struct {
+	Type   string  `json:"T"`
+	Symbol string  `json:"S"`
+	Price  float64 `json:"p"`
+	Size   float64 `json:"s"`
+}
+
+// SubscribeTrades streams symbol's trade updates over Alpaca's market-data
+// websocket, authenticating and subscribing on connect and reconnecting
+// with exponential backoff whenever the connection drops.
+func (a *AlpacaConnector) SubscribeTrades(ctx context.Context, symbol string) (<-chan Trade, error) {
+	ch := make(chan Trade, 32)
+
+	go func() {
+		defer close(ch)
+		backoffReconnect(ctx, "alpaca", func(ctx context.Context) error {
+			return a.readStream(ctx, symbol, "trades", func(msg alpacaTradeMessage) {
+				if msg.Type != "t" {
+					return
+				}
+				select {
+				case ch <- Trade{Symbol: msg.Symbol, Price: msg.Price, Quantity: msg.Size, Timestamp: time.Now()}:
+				case <-ctx.Done():
+				}
+			})
+		})
+	}()
+
+	return ch, nil
+}
+
+type alpacaQuoteMessage struct {
+	Type     string  `json:"T"`
+	Symbol   string  `json:"S"`
+	BidPrice float64 `json:"bp"`
+	BidSize  float64 `json:"bs"`
+	AskPrice float64 `json:"ap"`
+	AskSize  float64 `json:"as"`
+}
+
+// SubscribeOrderBook streams symbol's top-of-book quote updates over
+// Alpaca's market-data websocket, reconnecting with exponential backoff
+// whenever the connection drops.
+func (a *AlpacaConnector) SubscribeOrderBook(ctx context.Context, symbol string) (<-chan OrderBookUpdate, error) {
+	ch := make(chan OrderBookUpdate, 32)
+
+	go func() {
+		defer close(ch)
+		backoffReconnect(ctx, "alpaca", func(ctx context.Context) error {
+			return a.readQuoteStream(ctx, symbol, ch)
+		})
+	}()
+
+	return ch, nil
+}
+
+// readStream authenticates, subscribes to channel (e.g. "trades") for
+// symbol, and decodes incoming trade messages until the connection drops.
+func (a *AlpacaConnector) readStream(ctx context.Context, symbol, channel string, onTrade func(alpacaTradeMessage)) error {
+	conn, err := a.dialAndSubscribe(ctx, symbol, channel)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var events []alpacaTradeMessage
+		if err := json.Unmarshal(message, &events); err != nil {
+			continue
+		}
+		for _, e := range events {
+			onTrade(e)
+		}
+	}
+}
+
+func (a *AlpacaConnector) readQuoteStream(ctx context.Context, symbol string, ch chan<- OrderBookUpdate) error {
+	conn, err := a.dialAndSubscribe(ctx, symbol, "quotes")
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var events []alpacaQuoteMessage
+		if err := json.Unmarshal(message, &events); err != nil {
+			continue
+		}
+		for _, e := range events {
+			if e.Type != "q" {
+				continue
+			}
+			update := OrderBookUpdate{
+				Symbol:    e.Symbol,
+				Bids:      []OrderBookLevel{{Price: e.BidPrice, Quantity: e.BidSize}},
+				Asks:      []OrderBookLevel{{Price: e.AskPrice, Quantity: e.AskSize}},
+				Timestamp: time.Now(),
+			}
+			select {
+			case ch <- update:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// dialAndSubscribe opens Alpaca's market-data stream, authenticates with
+// apiKeyID/apiSecret, and subscribes channel for symbol.
+func (a *AlpacaConnector) dialAndSubscribe(ctx context.Context, symbol, channel string) (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, a.streamURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("alpaca: failed to dial stream: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	authMsg := map[string]string{"action": "auth", "key": a.apiKeyID, "secret": a.apiSecret}
+	if err := conn.WriteJSON(authMsg); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("alpaca: failed to authenticate stream: %w", err)
+	}
+
+	subMsg := map[string]interface{}{"action": "subscribe", channel: []string{symbol}}
+	if err := conn.WriteJSON(subMsg); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("alpaca: failed to subscribe to %s/%s: %w", channel, symbol, err)
+	}
+
+	return conn, nil
+}
+
+// FetchKlines fetches up to limit historical bars for symbol/interval via
+// Alpaca's market-data REST API.
+func (a *AlpacaConnector) FetchKlines(ctx context.Context, symbol, interval string, limit int) ([]Kline, error) {
+	reqURL := fmt.Sprintf("%s/v2/stocks/%s/bars?timeframe=%s&limit=%d", a.dataURL, symbol, interval, limit)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("alpaca: failed to build bars request: %w", err)
+	}
+	a.authHeaders(req)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("alpaca: failed to fetch bars for %s: %w", symbol, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("alpaca: bars for %s returned status %d", symbol, resp.StatusCode)
+	}
+
+	var body struct {
+		Bars []struct {
+			Timestamp string  `json:"t"`
+			Open      float64 `json:"o"`
+			High      float64 `json:"h"`
+			Low       float64 `json:"l"`
+			Close     float64 `json:"c"`
+			Volume    float64 `json:"v"`
+		} `json:"bars"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("alpaca: failed to decode bars for %s: %w", symbol, err)
+	}
+
+	klines := make([]Kline, 0, len(body.Bars))
+	for _, bar := range body.Bars {
+		openTime, _ := time.Parse(time.RFC3339, bar.Timestamp)
+		klines = append(klines, Kline{
+			Symbol:   symbol,
+			Interval: interval,
+			Open:     bar.Open,
+			High:     bar.High,
+			Low:      bar.Low,
+			Close:    bar.Close,
+			Volume:   bar.Volume,
+			OpenTime: openTime,
+			Closed:   true,
+		})
+	}
+	return klines, nil
+}
+
+// PlaceOrder submits order via Alpaca's POST /v2/orders.
+func (a *AlpacaConnector) PlaceOrder(ctx context.Context, order OrderRequest) (OrderResult, error) {
+	side := "buy"
+	if order.Side == "SELL" {
+		side = "sell"
+	}
+	orderType := "market"
+	if order.OrderType == "LIMIT" {
+		orderType = "limit"
+	}
+
+	payload := map[string]interface{}{
+		"symbol":        order.Symbol,
+		"side":          side,
+		"type":          orderType,
+		"qty":           strconv.FormatFloat(order.Quantity, 'f', -1, 64),
+		"time_in_force": "day",
+	}
+	if order.Price != nil {
+		payload["limit_price"] = strconv.FormatFloat(*order.Price, 'f', -1, 64)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return OrderResult{Success: false, Error: err.Error()}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/v2/orders", bytes.NewReader(body))
+	if err != nil {
+		return OrderResult{Success: false, Error: err.Error()}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	a.authHeaders(req)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return OrderResult{Success: false, Error: err.Error()}, err
+	}
+	defer resp.Body.Close()
+
+	var respBody struct {
+		ID      string `json:"id"`
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&respBody)
+
+	if resp.StatusCode >= 300 {
+		return OrderResult{Success: false, Error: respBody.Message}, fmt.Errorf("alpaca: order rejected: %s", respBody.Message)
+	}
+	return OrderResult{Success: true, ExchangeOrder: respBody.ID, Status: respBody.Status}, nil
+}
+
+// CancelOrder cancels exchangeOrderID via Alpaca's
+// DELETE /v2/orders/{order_id}.
+func (a *AlpacaConnector) CancelOrder(ctx context.Context, symbol, exchangeOrderID string) (OrderResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete,
+		a.baseURL+"/v2/orders/"+strings.TrimSpace(exchangeOrderID), nil)
+	if err != nil {
+		return OrderResult{Success: false, Error: err.Error()}, err
+	}
+	a.authHeaders(req)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return OrderResult{Success: false, Error: err.Error()}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return OrderResult{Success: false, Error: fmt.Sprintf("status %d", resp.StatusCode)}, fmt.Errorf("alpaca: cancel rejected with status %d", resp.StatusCode)
+	}
+	return OrderResult{Success: true, ExchangeOrder: exchangeOrderID, Status: "canceled"}, nil
+}
@@ -0,0 +1,356 @@
+package exchange
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// BinanceConnector streams public market data off Binance's single-stream
+// websocket (@miniTicker for trades, @bookTicker for best bid/ask), fetches
+// candles from the public REST klines endpoint, and places/cancels orders
+// through Binance's signed REST API.
+type BinanceConnector struct {
+	apiKey    string
+	apiSecret string
+	client    *http.Client
+}
+
+// NewBinanceConnector creates a BinanceConnector. apiKey/apiSecret may be
+// empty for market-data-only use; PlaceOrder/CancelOrder require both.
+func NewBinanceConnector(apiKey, apiSecret string) *BinanceConnector {
+	return &BinanceConnector{
+		apiKey:    apiKey,
+		apiSecret: apiSecret,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the connector's identifier.
+func (b *BinanceConnector) Name() string {
+	return "binance"
+}
+
+// Authenticate verifies apiKey/apiSecret against Binance's signed account
+// endpoint. A market-data-only connector (no credentials) is a no-op.
+func (b *BinanceConnector) Authenticate(ctx context.Context) error {
+	if b.apiKey == "" || b.apiSecret == "" {
+		return nil
+	}
+
+	params := url.Values{}
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	signed := b.sign(params)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		"https://api.binance.com/api/v3/account?"+signed.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("binance: failed to build account request: %w", err)
+	}
+	req.Header.Set("X-MBX-APIKEY", b.apiKey)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("binance: authentication request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("binance: authentication failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sign appends Binance's HMAC-SHA256 signature (over the query string) as
+// the "signature" param, matching every Binance private REST endpoint.
+func (b *BinanceConnector) sign(params url.Values) url.Values {
+	mac := hmac.New(sha256.New, []byte(b.apiSecret))
+	mac.Write([]byte(params.Encode()))
+	params.Set("signature", hex.EncodeToString(mac.Sum(nil)))
+	return params
+}
+
+type binanceMiniTicker struct {
+	EventType string `json:"e"`
+	Symbol    string `json:"s"`
+	Close     string `json:"c"`
+	Volume    string `json:"v"`
+}
+
+// SubscribeTrades streams symbol's @miniTicker updates, reconnecting with
+// exponential backoff whenever the connection drops.
+func (b *BinanceConnector) SubscribeTrades(ctx context.Context, symbol string) (<-chan Trade, error) {
+	ch := make(chan Trade, 32)
+	streamName := strings.ToLower(symbol) + "@miniTicker"
+	streamURL := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s", streamName)
+
+	go func() {
+		defer close(ch)
+		backoffReconnect(ctx, "binance", func(ctx context.Context) error {
+			return b.readMiniTickerStream(ctx, streamURL, ch)
+		})
+	}()
+
+	return ch, nil
+}
+
+func (b *BinanceConnector) readMiniTickerStream(ctx context.Context, streamURL string, ch chan<- Trade) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, streamURL, nil)
+	if err != nil {
+		return fmt.Errorf("binance: failed to dial %s: %w", streamURL, err)
+	}
+	defer conn.Close()
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var mt binanceMiniTicker
+		if err := json.Unmarshal(message, &mt); err != nil {
+			continue
+		}
+		price, err := strconv.ParseFloat(mt.Close, 64)
+		if err != nil {
+			continue
+		}
+		volume, _ := strconv.ParseFloat(mt.Volume, 64)
+
+		select {
+		case ch <- Trade{Symbol: mt.Symbol, Price: price, Quantity: volume, Timestamp: time.Now()}:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// SubscribeOrderBook streams symbol's bookTicker (best bid/ask) updates,
+// reconnecting with exponential backoff whenever the connection drops.
+func (b *BinanceConnector) SubscribeOrderBook(ctx context.Context, symbol string) (<-chan OrderBookUpdate, error) {
+	ch := make(chan OrderBookUpdate, 32)
+	streamName := strings.ToLower(symbol) + "@bookTicker"
+	streamURL := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s", streamName)
+
+	go func() {
+		defer close(ch)
+		backoffReconnect(ctx, "binance", func(ctx context.Context) error {
+			return b.readBookTickerStream(ctx, streamURL, ch)
+		})
+	}()
+
+	return ch, nil
+}
+
+type binanceBookTickerEvent struct {
+	Symbol   string `json:"s"`
+	BidPrice string `json:"b"`
+	BidQty   string `json:"B"`
+	AskPrice string `json:"a"`
+	AskQty   string `json:"A"`
+}
+
+func (b *BinanceConnector) readBookTickerStream(ctx context.Context, streamURL string, ch chan<- OrderBookUpdate) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, streamURL, nil)
+	if err != nil {
+		return fmt.Errorf("binance: failed to dial %s: %w", streamURL, err)
+	}
+	defer conn.Close()
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var bt binanceBookTickerEvent
+		if err := json.Unmarshal(message, &bt); err != nil {
+			continue
+		}
+		bidPrice, _ := strconv.ParseFloat(bt.BidPrice, 64)
+		bidQty, _ := strconv.ParseFloat(bt.BidQty, 64)
+		askPrice, _ := strconv.ParseFloat(bt.AskPrice, 64)
+		askQty, _ := strconv.ParseFloat(bt.AskQty, 64)
+
+		update := OrderBookUpdate{
+			Symbol:    bt.Symbol,
+			Bids:      []OrderBookLevel{{Price: bidPrice, Quantity: bidQty}},
+			Asks:      []OrderBookLevel{{Price: askPrice, Quantity: askQty}},
+			Timestamp: time.Now(),
+		}
+
+		select {
+		case ch <- update:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// FetchKlines fetches up to limit historical candles for symbol/interval
+// via Binance's public REST klines endpoint.
+func (b *BinanceConnector) FetchKlines(ctx context.Context, symbol, interval string, limit int) ([]Kline, error) {
+	reqURL := fmt.Sprintf("https://api.binance.com/api/v3/klines?symbol=%s&interval=%s&limit=%d",
+		strings.ToUpper(symbol), interval, limit)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("binance: failed to build klines request: %w", err)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("binance: failed to fetch klines for %s: %w", symbol, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("binance: klines for %s returned status %d", symbol, resp.StatusCode)
+	}
+
+	// Binance returns each kline as a heterogeneous JSON array, not an
+	// object, so decode into [][]interface{} rather than a struct.
+	var raw [][]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("binance: failed to decode klines for %s: %w", symbol, err)
+	}
+
+	klines := make([]Kline, 0, len(raw))
+	for _, row := range raw {
+		if len(row) < 7 {
+			continue
+		}
+		open, _ := strconv.ParseFloat(row[1].(string), 64)
+		high, _ := strconv.ParseFloat(row[2].(string), 64)
+		low, _ := strconv.ParseFloat(row[3].(string), 64)
+		closePrice, _ := strconv.ParseFloat(row[4].(string), 64)
+		volume, _ := strconv.ParseFloat(row[5].(string), 64)
+		openTimeMs, _ := row[0].(float64)
+		closeTimeMs, _ := row[6].(float64)
+
+		klines = append(klines, Kline{
+			Symbol:    symbol,
+			Interval:  interval,
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     closePrice,
+			Volume:    volume,
+			OpenTime:  time.UnixMilli(int64(openTimeMs)),
+			CloseTime: time.UnixMilli(int64(closeTimeMs)),
+			Closed:    true,
+		})
+	}
+	return klines, nil
+}
+
+// PlaceOrder submits order via Binance's signed POST /api/v3/order.
+func (b *BinanceConnector) PlaceOrder(ctx context.Context, order OrderRequest) (OrderResult, error) {
+	if b.apiKey == "" || b.apiSecret == "" {
+		return OrderResult{Success: false, Error: "binance: missing API credentials"}, fmt.Errorf("binance: missing API credentials")
+	}
+
+	side := "BUY"
+	if order.Side == "SELL" {
+		side = "SELL"
+	}
+	orderType := "MARKET"
+	if order.OrderType == "LIMIT" {
+		orderType = "LIMIT"
+	}
+
+	params := url.Values{}
+	params.Set("symbol", strings.ToUpper(order.Symbol))
+	params.Set("side", side)
+	params.Set("type", orderType)
+	params.Set("quantity", strconv.FormatFloat(order.Quantity, 'f', -1, 64))
+	if order.Price != nil {
+		params.Set("price", strconv.FormatFloat(*order.Price, 'f', -1, 64))
+		params.Set("timeInForce", "GTC")
+	}
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	signed := b.sign(params)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		"https://api.binance.com/api/v3/order?"+signed.Encode(), nil)
+	if err != nil {
+		return OrderResult{Success: false, Error: err.Error()}, err
+	}
+	req.Header.Set("X-MBX-APIKEY", b.apiKey)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return OrderResult{Success: false, Error: err.Error()}, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		OrderID int64  `json:"orderId"`
+		Status  string `json:"status"`
+		Msg     string `json:"msg"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+
+	if resp.StatusCode != http.StatusOK {
+		return OrderResult{Success: false, Error: body.Msg}, fmt.Errorf("binance: order rejected: %s", body.Msg)
+	}
+	return OrderResult{Success: true, ExchangeOrder: strconv.FormatInt(body.OrderID, 10), Status: body.Status}, nil
+}
+
+// CancelOrder cancels exchangeOrderID via Binance's signed
+// DELETE /api/v3/order.
+func (b *BinanceConnector) CancelOrder(ctx context.Context, symbol, exchangeOrderID string) (OrderResult, error) {
+	if b.apiKey == "" || b.apiSecret == "" {
+		return OrderResult{Success: false, Error: "binance: missing API credentials"}, fmt.Errorf("binance: missing API credentials")
+	}
+
+	params := url.Values{}
+	params.Set("symbol", strings.ToUpper(symbol))
+	params.Set("orderId", exchangeOrderID)
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	signed := b.sign(params)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete,
+		"https://api.binance.com/api/v3/order?"+signed.Encode(), nil)
+	if err != nil {
+		return OrderResult{Success: false, Error: err.Error()}, err
+	}
+	req.Header.Set("X-MBX-APIKEY", b.apiKey)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return OrderResult{Success: false, Error: err.Error()}, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Status string `json:"status"`
+		Msg    string `json:"msg"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+
+	if resp.StatusCode != http.StatusOK {
+		return OrderResult{Success: false, Error: body.Msg}, fmt.Errorf("binance: cancel rejected: %s", body.Msg)
+	}
+	return OrderResult{Success: true, ExchangeOrder: exchangeOrderID, Status: body.Status}, nil
+}
@@ -0,0 +1,375 @@
+package exchange
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// BybitConnector speaks Bybit's v5 unified API: signed HMAC-SHA256 REST for
+// account/order endpoints, and public websocket topics for trades/orderbook.
+type BybitConnector struct {
+	apiKey    string
+	apiSecret string
+	client    *http.Client
+}
+
+const bybitRecvWindow = "5000"
+
+// NewBybitConnector creates a BybitConnector. apiKey/apiSecret may be empty
+// for market-data-only use; PlaceOrder/CancelOrder require both.
+func NewBybitConnector(apiKey, apiSecret string) *BybitConnector {
+	return &BybitConnector{
+		apiKey:    apiKey,
+		apiSecret: apiSecret,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the connector's identifier.
+func (b *BybitConnector) Name() string {
+	return "bybit"
+}
+
+// sign computes Bybit v5's signature: HMAC-SHA256 over
+// timestamp+apiKey+recvWindow+payload (the query string for GET, the raw
+// JSON body for POST), hex-encoded.
+func (b *BybitConnector) sign(timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(b.apiSecret))
+	mac.Write([]byte(timestamp + b.apiKey + bybitRecvWindow + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (b *BybitConnector) authHeaders(req *http.Request, payload string) {
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	req.Header.Set("X-BAPI-API-KEY", b.apiKey)
+	req.Header.Set("X-BAPI-TIMESTAMP", timestamp)
+	req.Header.Set("X-BAPI-RECV-WINDOW", bybitRecvWindow)
+	req.Header.Set("X-BAPI-SIGN", b.sign(timestamp, payload))
+}
+
+// Authenticate verifies apiKey/apiSecret against Bybit's signed wallet
+// balance endpoint. A market-data-only connector (no credentials) is a
+// no-op.
+func (b *BybitConnector) Authenticate(ctx context.Context) error {
+	if b.apiKey == "" || b.apiSecret == "" {
+		return nil
+	}
+
+	query := "accountType=UNIFIED"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		"https://api.bybit.com/v5/account/wallet-balance?"+query, nil)
+	if err != nil {
+		return fmt.Errorf("bybit: failed to build wallet-balance request: %w", err)
+	}
+	b.authHeaders(req, query)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("bybit: authentication request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("bybit: failed to decode authentication response: %w", err)
+	}
+	if body.RetCode != 0 {
+		return fmt.Errorf("bybit: authentication failed: %s", body.RetMsg)
+	}
+	return nil
+}
+
+type bybitWSMessage struct {
+	Topic string          `json:"topic"`
+	Data  json.RawMessage `json:"data"`
+}
+
+type bybitPublicTrade struct {
+	Symbol string `json:"s"`
+	Price  string `json:"p"`
+	Size   string `json:"v"`
+	Side   string `json:"S"`
+}
+
+// SubscribeTrades streams symbol's publicTrade topic over Bybit's public
+// spot websocket, reconnecting with exponential backoff whenever the
+// connection drops.
+func (b *BybitConnector) SubscribeTrades(ctx context.Context, symbol string) (<-chan Trade, error) {
+	ch := make(chan Trade, 32)
+	topic := "publicTrade." + strings.ToUpper(symbol)
+
+	go func() {
+		defer close(ch)
+		backoffReconnect(ctx, "bybit", func(ctx context.Context) error {
+			return b.readTopic(ctx, topic, func(raw json.RawMessage) {
+				var trades []bybitPublicTrade
+				if err := json.Unmarshal(raw, &trades); err != nil {
+					return
+				}
+				for _, t := range trades {
+					price, _ := strconv.ParseFloat(t.Price, 64)
+					qty, _ := strconv.ParseFloat(t.Size, 64)
+					side := "BUY"
+					if t.Side == "Sell" {
+						side = "SELL"
+					}
+					select {
+					case ch <- Trade{Symbol: t.Symbol, Price: price, Quantity: qty, Side: side, Timestamp: time.Now()}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			})
+		})
+	}()
+
+	return ch, nil
+}
+
+type bybitOrderBookData struct {
+	Symbol string     `json:"s"`
+	Bids   [][]string `json:"b"`
+	Asks   [][]string `json:"a"`
+}
+
+// SubscribeOrderBook streams symbol's orderbook.1 topic (best bid/ask) over
+// Bybit's public spot websocket, reconnecting with exponential backoff
+// whenever the connection drops.
+func (b *BybitConnector) SubscribeOrderBook(ctx context.Context, symbol string) (<-chan OrderBookUpdate, error) {
+	ch := make(chan OrderBookUpdate, 32)
+	topic := "orderbook.1." + strings.ToUpper(symbol)
+
+	go func() {
+		defer close(ch)
+		backoffReconnect(ctx, "bybit", func(ctx context.Context) error {
+			return b.readTopic(ctx, topic, func(raw json.RawMessage) {
+				var data bybitOrderBookData
+				if err := json.Unmarshal(raw, &data); err != nil {
+					return
+				}
+				update := OrderBookUpdate{Symbol: data.Symbol, Timestamp: time.Now()}
+				for _, level := range data.Bids {
+					if len(level) < 2 {
+						continue
+					}
+					price, _ := strconv.ParseFloat(level[0], 64)
+					qty, _ := strconv.ParseFloat(level[1], 64)
+					update.Bids = append(update.Bids, OrderBookLevel{Price: price, Quantity: qty})
+				}
+				for _, level := range data.Asks {
+					if len(level) < 2 {
+						continue
+					}
+					price, _ := strconv.ParseFloat(level[0], 64)
+					qty, _ := strconv.ParseFloat(level[1], 64)
+					update.Asks = append(update.Asks, OrderBookLevel{Price: price, Quantity: qty})
+				}
+
+				select {
+				case ch <- update:
+				case <-ctx.Done():
+				}
+			})
+		})
+	}()
+
+	return ch, nil
+}
+
+// readTopic dials Bybit's public spot websocket, subscribes to topic, and
+// invokes onData for every message on that topic until the connection
+// drops or errors.
+func (b *BybitConnector) readTopic(ctx context.Context, topic string, onData func(json.RawMessage)) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, "wss://stream.bybit.com/v5/public/spot", nil)
+	if err != nil {
+		return fmt.Errorf("bybit: failed to dial public stream: %w", err)
+	}
+	defer conn.Close()
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	sub := map[string]interface{}{"op": "subscribe", "args": []string{topic}}
+	if err := conn.WriteJSON(sub); err != nil {
+		return fmt.Errorf("bybit: failed to subscribe to %s: %w", topic, err)
+	}
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var msg bybitWSMessage
+		if err := json.Unmarshal(message, &msg); err != nil {
+			continue
+		}
+		if msg.Topic != topic {
+			continue
+		}
+		onData(msg.Data)
+	}
+}
+
+// FetchKlines fetches up to limit historical candles for symbol/interval
+// via Bybit's public REST kline endpoint.
+func (b *BybitConnector) FetchKlines(ctx context.Context, symbol, interval string, limit int) ([]Kline, error) {
+	reqURL := fmt.Sprintf("https://api.bybit.com/v5/market/kline?category=spot&symbol=%s&interval=%s&limit=%d",
+		strings.ToUpper(symbol), interval, limit)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("bybit: failed to build klines request: %w", err)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("bybit: failed to fetch klines for %s: %w", symbol, err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			List [][]string `json:"list"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("bybit: failed to decode klines for %s: %w", symbol, err)
+	}
+	if body.RetCode != 0 {
+		return nil, fmt.Errorf("bybit: klines for %s failed: %s", symbol, body.RetMsg)
+	}
+
+	klines := make([]Kline, 0, len(body.Result.List))
+	for _, row := range body.Result.List {
+		if len(row) < 6 {
+			continue
+		}
+		startMs, _ := strconv.ParseInt(row[0], 10, 64)
+		open, _ := strconv.ParseFloat(row[1], 64)
+		high, _ := strconv.ParseFloat(row[2], 64)
+		low, _ := strconv.ParseFloat(row[3], 64)
+		closePrice, _ := strconv.ParseFloat(row[4], 64)
+		volume, _ := strconv.ParseFloat(row[5], 64)
+
+		klines = append(klines, Kline{
+			Symbol:   symbol,
+			Interval: interval,
+			Open:     open,
+			High:     high,
+			Low:      low,
+			Close:    closePrice,
+			Volume:   volume,
+			OpenTime: time.UnixMilli(startMs),
+			Closed:   true,
+		})
+	}
+	return klines, nil
+}
+
+// PlaceOrder submits order via Bybit's signed POST /v5/order/create.
+func (b *BybitConnector) PlaceOrder(ctx context.Context, order OrderRequest) (OrderResult, error) {
+	if b.apiKey == "" || b.apiSecret == "" {
+		return OrderResult{Success: false, Error: "bybit: missing API credentials"}, fmt.Errorf("bybit: missing API credentials")
+	}
+
+	side := "Buy"
+	if order.Side == "SELL" {
+		side = "Sell"
+	}
+	orderType := "Market"
+	if order.OrderType == "LIMIT" {
+		orderType = "Limit"
+	}
+
+	payload := map[string]interface{}{
+		"category":  "spot",
+		"symbol":    strings.ToUpper(order.Symbol),
+		"side":      side,
+		"orderType": orderType,
+		"qty":       strconv.FormatFloat(order.Quantity, 'f', -1, 64),
+	}
+	if order.Price != nil {
+		payload["price"] = strconv.FormatFloat(*order.Price, 'f', -1, 64)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return OrderResult{Success: false, Error: err.Error()}, err
+	}
+
+	result, err := b.post(ctx, "/v5/order/create", body)
+	if err != nil {
+		return OrderResult{Success: false, Error: err.Error()}, err
+	}
+	return result, nil
+}
+
+// CancelOrder cancels exchangeOrderID via Bybit's signed
+// POST /v5/order/cancel.
+func (b *BybitConnector) CancelOrder(ctx context.Context, symbol, exchangeOrderID string) (OrderResult, error) {
+	if b.apiKey == "" || b.apiSecret == "" {
+		return OrderResult{Success: false, Error: "bybit: missing API credentials"}, fmt.Errorf("bybit: missing API credentials")
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"category": "spot",
+		"symbol":   strings.ToUpper(symbol),
+		"orderId":  exchangeOrderID,
+	})
+	if err != nil {
+		return OrderResult{Success: false, Error: err.Error()}, err
+	}
+
+	result, err := b.post(ctx, "/v5/order/cancel", payload)
+	if err != nil {
+		return OrderResult{Success: false, Error: err.Error()}, err
+	}
+	return result, nil
+}
+
+func (b *BybitConnector) post(ctx context.Context, path string, payload []byte) (OrderResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.bybit.com"+path, strings.NewReader(string(payload)))
+	if err != nil {
+		return OrderResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	b.authHeaders(req, string(payload))
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return OrderResult{}, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			OrderID string `json:"orderId"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return OrderResult{}, fmt.Errorf("bybit: failed to decode response for %s: %w", path, err)
+	}
+	if body.RetCode != 0 {
+		return OrderResult{Success: false, Error: body.RetMsg}, fmt.Errorf("bybit: %s failed: %s", path, body.RetMsg)
+	}
+	return OrderResult{Success: true, ExchangeOrder: body.Result.OrderID, Status: "accepted"}, nil
+}
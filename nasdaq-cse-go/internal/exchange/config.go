@@ -0,0 +1,55 @@
+package exchange
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SessionConfig describes one configured exchange connector, following the
+// bbgo convention of a `sessions:` map keyed by a caller-chosen session
+// name (e.g. "binance_spot") rather than by exchange name, so the same
+// exchange can be configured twice under different credentials.
+type SessionConfig struct {
+	Exchange      string `yaml:"exchange"` // "binance", "bybit", or "alpaca"
+	APIKey        string `yaml:"apiKey"`
+	APISecret     string `yaml:"apiSecret"`
+	MarketDataFor string `yaml:"marketDataFor"` // symbol pattern this session feeds into MarketDataService, e.g. "GOLD*"
+	Orders        bool   `yaml:"orders"`        // whether OrderManager may route live orders through this session
+}
+
+// SessionsConfig is the top-level `sessions:` document loaded from YAML,
+// mirroring cmd/backtest's config-file convention.
+type SessionsConfig struct {
+	Sessions map[string]SessionConfig `yaml:"sessions"`
+}
+
+// LoadSessionsConfig reads and parses a sessions YAML file (see
+// configs/exchanges.yaml for the expected layout).
+func LoadSessionsConfig(path string) (*SessionsConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("exchange: failed to read sessions config %s: %w", path, err)
+	}
+
+	var cfg SessionsConfig
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("exchange: failed to parse sessions config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// NewConnector builds the Connector a SessionConfig describes.
+func NewConnector(cfg SessionConfig) (Connector, error) {
+	switch cfg.Exchange {
+	case "binance":
+		return NewBinanceConnector(cfg.APIKey, cfg.APISecret), nil
+	case "bybit":
+		return NewBybitConnector(cfg.APIKey, cfg.APISecret), nil
+	case "alpaca":
+		return NewAlpacaConnector(cfg.APIKey, cfg.APISecret), nil
+	default:
+		return nil, fmt.Errorf("exchange: unknown exchange %q", cfg.Exchange)
+	}
+}
@@ -0,0 +1,132 @@
+// Package exchange defines a pluggable connector interface to real venues
+// (Binance, Bybit, Alpaca) so MarketDataService and OrderManager can be fed
+// by a live exchange instead of only the in-process simulation, the way
+// bbgo lets one strategy run unmodified against several exchange adapters.
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Trade is one normalized public trade print, regardless of venue.
+type Trade struct {
+	Symbol    string
+	Price     float64
+	Quantity  float64
+	Side      string // "BUY" or "SELL"
+	Timestamp time.Time
+}
+
+// OrderBookLevel is one price/quantity level of a normalized order book.
+type OrderBookLevel struct {
+	Price    float64
+	Quantity float64
+}
+
+// OrderBookUpdate is a normalized top-of-book (or depth) snapshot.
+type OrderBookUpdate struct {
+	Symbol    string
+	Bids      []OrderBookLevel
+	Asks      []OrderBookLevel
+	Timestamp time.Time
+}
+
+// Kline is one normalized OHLCV candle.
+type Kline struct {
+	Symbol    string
+	Interval  string
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    float64
+	OpenTime  time.Time
+	CloseTime time.Time
+	Closed    bool
+}
+
+// OrderRequest is a normalized outbound order, regardless of venue.
+type OrderRequest struct {
+	Symbol    string
+	Side      string // "BUY" or "SELL"
+	OrderType string // "MARKET" or "LIMIT"
+	Quantity  float64
+	Price     *float64
+}
+
+// OrderResult is a normalized response to PlaceOrder/CancelOrder.
+type OrderResult struct {
+	Success       bool
+	ExchangeOrder string
+	Status        string
+	Error         string
+}
+
+// Connector is the seam every venue adapter implements: streaming market
+// data (trades, order book, klines) and placing/canceling orders against
+// the venue's private API. Authenticate must succeed before PlaceOrder or
+// CancelOrder are called.
+type Connector interface {
+	// Name identifies the venue for logging, routing, and /api/exchanges.
+	Name() string
+	// Authenticate establishes (or verifies) credentials for the private
+	// REST/WS endpoints PlaceOrder/CancelOrder use.
+	Authenticate(ctx context.Context) error
+	// SubscribeTrades streams public trade prints for symbol until ctx is
+	// canceled or the feed errors out, reconnecting with backoff in between.
+	SubscribeTrades(ctx context.Context, symbol string) (<-chan Trade, error)
+	// SubscribeOrderBook streams top-of-book updates for symbol the same
+	// way SubscribeTrades streams trades.
+	SubscribeOrderBook(ctx context.Context, symbol string) (<-chan OrderBookUpdate, error)
+	// FetchKlines fetches up to limit historical candles for symbol/interval.
+	FetchKlines(ctx context.Context, symbol, interval string, limit int) ([]Kline, error)
+	// PlaceOrder submits order against the venue's private API.
+	PlaceOrder(ctx context.Context, order OrderRequest) (OrderResult, error)
+	// CancelOrder cancels a previously placed order by its venue order ID.
+	CancelOrder(ctx context.Context, symbol, exchangeOrderID string) (OrderResult, error)
+}
+
+// backoffReconnect calls connect in a loop until ctx is canceled, waiting
+// with exponential backoff (starting at 1s, doubling up to a 30s cap, plus
+// jitter) after each connection that ends in an error, following the done/stop channel reconnect
+// pattern used by real exchange SDKs (e.g. Binance's websocket client).
+// connect should block for the lifetime of one connection and return (nil
+// or an error) only once it has dropped.
+func backoffReconnect(ctx context.Context, name string, connect func(ctx context.Context) error) {
+	backoff := time.Second
+	const maxBackoff = 30 * time.Second
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		err := connect(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			// connect returned cleanly (e.g. ctx canceled mid-read); loop
+			// will exit on the next ctx.Err() check.
+			continue
+		}
+
+		jitter := time.Duration(rand.Int63n(int64(backoff) / 2))
+		wait := backoff + jitter
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		fmt.Printf("%s: reconnecting after %v (last error: %v)\n", name, wait.Round(time.Millisecond), err)
+	}
+}
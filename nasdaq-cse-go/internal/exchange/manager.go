@@ -0,0 +1,190 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/marketdata"
+)
+
+// SessionStatus reports one configured session's connectivity, for the
+// /api/exchanges endpoint.
+type SessionStatus struct {
+	Name      string    `json:"name"`
+	Exchange  string    `json:"exchange"`
+	Connected bool      `json:"connected"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type session struct {
+	name      string
+	config    SessionConfig
+	connector Connector
+	status    SessionStatus
+}
+
+// Manager builds Connectors from a SessionsConfig, authenticates each on
+// startup, and exposes which session feeds MarketDataService and which
+// receives OrderManager's live order flow.
+type Manager struct {
+	mutex    sync.RWMutex
+	sessions map[string]*session
+}
+
+// NewManager builds a Connector for every entry in cfg and authenticates
+// it; authentication failures are recorded on the session's status rather
+// than failing the whole manager, since one misconfigured venue shouldn't
+// block the others from coming up.
+func NewManager(ctx context.Context, cfg *SessionsConfig) (*Manager, error) {
+	mgr := &Manager{sessions: make(map[string]*session)}
+
+	for name, sc := range cfg.Sessions {
+		connector, err := NewConnector(sc)
+		if err != nil {
+			return nil, fmt.Errorf("exchange: failed to build session %q: %w", name, err)
+		}
+
+		s := &session{
+			name:      name,
+			config:    sc,
+			connector: connector,
+			status: SessionStatus{
+				Name:      name,
+				Exchange:  sc.Exchange,
+				CheckedAt: time.Now(),
+			},
+		}
+		if err := connector.Authenticate(ctx); err != nil {
+			s.status.Error = err.Error()
+		} else {
+			s.status.Connected = true
+		}
+		mgr.sessions[name] = s
+	}
+
+	return mgr, nil
+}
+
+// Statuses returns every configured session's last-known connectivity.
+func (m *Manager) Statuses() []SessionStatus {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	statuses := make([]SessionStatus, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		statuses = append(statuses, s.status)
+	}
+	return statuses
+}
+
+// MarketDataSources returns PriceSource adapters for every session whose
+// config marks it as a market-data feed, keyed by the symbol pattern it
+// should be routed under (SessionConfig.MarketDataFor).
+func (m *Manager) MarketDataSources() map[string]marketdata.PriceSource {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	sources := make(map[string]marketdata.PriceSource)
+	for _, s := range m.sessions {
+		if s.config.MarketDataFor == "" {
+			continue
+		}
+		sources[s.config.MarketDataFor] = NewPriceSourceAdapter(s.connector)
+	}
+	return sources
+}
+
+// OrderConnector returns the Connector configured to receive live orders
+// from OrderManager, if any session has Orders: true set.
+func (m *Manager) OrderConnector() (Connector, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, s := range m.sessions {
+		if s.config.Orders {
+			return s.connector, true
+		}
+	}
+	return nil, false
+}
+
+// ForwardOrder best-effort mirrors order onto the configured order
+// connector, the same fire-and-forget pattern FIXOrderGateway uses for
+// bridging orders into a different system of record: a forwarding failure
+// is reported back to the caller but never blocks or reverses the OMS fill
+// that already happened.
+func (m *Manager) ForwardOrder(ctx context.Context, order OrderRequest) (OrderResult, error) {
+	connector, ok := m.OrderConnector()
+	if !ok {
+		return OrderResult{}, fmt.Errorf("exchange: no session configured to receive orders")
+	}
+	return connector.PlaceOrder(ctx, order)
+}
+
+// PriceSourceAdapter wraps a Connector so it satisfies
+// marketdata.PriceSource, letting a live exchange connector feed
+// MarketDataService's symbol routing the same way marketdata.BinanceSource
+// does today.
+type PriceSourceAdapter struct {
+	connector Connector
+}
+
+// NewPriceSourceAdapter wraps connector as a marketdata.PriceSource.
+func NewPriceSourceAdapter(connector Connector) *PriceSourceAdapter {
+	return &PriceSourceAdapter{connector: connector}
+}
+
+// Name identifies the underlying connector's venue.
+func (a *PriceSourceAdapter) Name() string {
+	return a.connector.Name()
+}
+
+// Subscribe streams symbol's trades from the underlying connector,
+// normalizing each Trade into a Tick.
+func (a *PriceSourceAdapter) Subscribe(symbol string) (<-chan marketdata.Tick, error) {
+	trades, err := a.connector.SubscribeTrades(context.Background(), symbol)
+	if err != nil {
+		return nil, fmt.Errorf("exchange: failed to subscribe to %s trades: %w", symbol, err)
+	}
+
+	ticks := make(chan marketdata.Tick, 32)
+	go func() {
+		defer close(ticks)
+		for trade := range trades {
+			ticks <- marketdata.Tick{
+				Symbol:    trade.Symbol,
+				Bid:       trade.Price,
+				Ask:       trade.Price,
+				Last:      trade.Price,
+				Volume:    int64(trade.Quantity),
+				Timestamp: trade.Timestamp,
+			}
+		}
+	}()
+	return ticks, nil
+}
+
+// Snapshot fetches a single recent kline for symbol and normalizes its
+// close price into a Tick, since Connector has no standalone snapshot
+// call.
+func (a *PriceSourceAdapter) Snapshot(symbol string) (marketdata.Tick, error) {
+	klines, err := a.connector.FetchKlines(context.Background(), symbol, "1m", 1)
+	if err != nil {
+		return marketdata.Tick{}, fmt.Errorf("exchange: failed to snapshot %s: %w", symbol, err)
+	}
+	if len(klines) == 0 {
+		return marketdata.Tick{}, fmt.Errorf("exchange: no klines returned for %s", symbol)
+	}
+
+	k := klines[len(klines)-1]
+	return marketdata.Tick{
+		Symbol:    symbol,
+		Bid:       k.Close,
+		Ask:       k.Close,
+		Last:      k.Close,
+		Timestamp: k.OpenTime,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package marketdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// BinanceSource streams best-bid/ask quotes from Binance's bookTicker feed
+// over a public websocket, with a REST fallback for one-off snapshots.
+type BinanceSource struct {
+	client *http.Client
+}
+
+// NewBinanceSource creates a BinanceSource.
+func NewBinanceSource() *BinanceSource {
+	return &BinanceSource{client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// Name returns the source's identifier.
+func (b *BinanceSource) Name() string {
+	return "binance"
+}
+
+type binanceBookTicker struct {
+	Symbol   string `json:"s"`
+	BidPrice string `json:"b"`
+	BidQty   string `json:"B"`
+	AskPrice string `json:"a"`
+	AskQty   string `json:"A"`
+}
+
+// Snapshot fetches the current best bid/ask for symbol via Binance's REST
+// bookTicker endpoint.
+func (b *BinanceSource) Snapshot(symbol string) (Tick, error) {
+	url := fmt.Sprintf("https://api.binance.com/api/v3/ticker/bookTicker?symbol=%s", strings.ToUpper(symbol))
+	resp, err := b.client.Get(url)
+	if err != nil {
+		return Tick{}, fmt.Errorf("binance: failed to fetch %s: %w", symbol, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Tick{}, fmt.Errorf("binance: %s returned status %d", symbol, resp.StatusCode)
+	}
+
+	var bt binanceBookTicker
+	if err := json.NewDecoder(resp.Body).Decode(&bt); err != nil {
+		return Tick{}, fmt.Errorf("binance: failed to decode response for %s: %w", symbol, err)
+	}
+
+	return bookTickerToTick(bt)
+}
+
+// Subscribe opens a websocket to Binance's bookTicker stream for symbol and
+// pushes a Tick for every update until the connection drops.
+func (b *BinanceSource) Subscribe(symbol string) (<-chan Tick, error) {
+	streamName := strings.ToLower(symbol) + "@bookTicker"
+	url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s", streamName)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("binance: failed to dial %s stream: %w", symbol, err)
+	}
+
+	ch := make(chan Tick, 16)
+	go func() {
+		defer conn.Close()
+		defer close(ch)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var bt binanceBookTicker
+			if err := json.Unmarshal(message, &bt); err != nil {
+				continue
+			}
+			tick, err := bookTickerToTick(bt)
+			if err != nil {
+				continue
+			}
+			ch <- tick
+		}
+	}()
+
+	return ch, nil
+}
+
+func bookTickerToTick(bt binanceBookTicker) (Tick, error) {
+	bid, err := strconv.ParseFloat(bt.BidPrice, 64)
+	if err != nil {
+		return Tick{}, fmt.Errorf("binance: invalid bid price %q: %w", bt.BidPrice, err)
+	}
+	ask, err := strconv.ParseFloat(bt.AskPrice, 64)
+	if err != nil {
+		return Tick{}, fmt.Errorf("binance: invalid ask price %q: %w", bt.AskPrice, err)
+	}
+
+	return Tick{
+		Symbol:    bt.Symbol,
+		Bid:       bid,
+		Ask:       ask,
+		Last:      (bid + ask) / 2,
+		Timestamp: time.Now(),
+	}, nil
+}
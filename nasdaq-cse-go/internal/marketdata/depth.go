@@ -0,0 +1,140 @@
+package marketdata
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+)
+
+// DepthLevel is one price/quantity level of an order book side.
+type DepthLevel struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// DepthSnapshot is a full top-N view of both sides of a DepthBook, the
+// format clients use to seed their local book before following diff
+// updates.
+type DepthSnapshot struct {
+	Symbol string       `json:"symbol"`
+	Bids   []DepthLevel `json:"bids"`
+	Asks   []DepthLevel `json:"asks"`
+}
+
+// DepthUpdateAction classifies how a DepthUpdate changed a single price
+// level, so a client can maintain a local book without re-fetching a full
+// snapshot after the first message.
+type DepthUpdateAction string
+
+const (
+	DepthUpdateAdd     DepthUpdateAction = "add"
+	DepthUpdateReplace DepthUpdateAction = "replace"
+	DepthUpdateRemove  DepthUpdateAction = "remove"
+)
+
+// DepthUpdate is a single price-level diff produced by ApplyOrder or
+// ApplyTrade.
+type DepthUpdate struct {
+	Symbol   string            `json:"symbol"`
+	Side     core.OrderSide    `json:"side"`
+	Action   DepthUpdateAction `json:"action"`
+	Price    float64           `json:"price"`
+	Quantity float64           `json:"quantity"`
+}
+
+// DepthBook is an in-memory limit order book for one symbol: price-level
+// buckets of resting quantity per side. It's deliberately aggregate-only
+// (no per-order queue) since the simulator only needs to advertise
+// depth-of-market, not reconstruct individual resting orders.
+type DepthBook struct {
+	symbol string
+
+	mutex sync.RWMutex
+	bids  map[float64]float64 // OrderSideBuy resting quantity, by price
+	asks  map[float64]float64 // OrderSideSell resting quantity, by price
+}
+
+// NewDepthBook creates an empty DepthBook for symbol.
+func NewDepthBook(symbol string) *DepthBook {
+	return &DepthBook{
+		symbol: symbol,
+		bids:   make(map[float64]float64),
+		asks:   make(map[float64]float64),
+	}
+}
+
+// ApplyOrder adds a resting order's quantity to side's price level,
+// returning the diff update clients should apply to their local book.
+func (b *DepthBook) ApplyOrder(side core.OrderSide, price, quantity float64) DepthUpdate {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	level := b.levels(side)
+	_, existed := level[price]
+	level[price] += quantity
+
+	action := DepthUpdateReplace
+	if !existed {
+		action = DepthUpdateAdd
+	}
+	return DepthUpdate{Symbol: b.symbol, Side: side, Action: action, Price: price, Quantity: level[price]}
+}
+
+// ApplyTrade removes a fill's quantity from side's resting price level,
+// returning the diff update clients should apply to their local book. A
+// level that's fully consumed is removed rather than left at zero.
+func (b *DepthBook) ApplyTrade(side core.OrderSide, price, quantity float64) DepthUpdate {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	level := b.levels(side)
+	remaining := level[price] - quantity
+
+	if remaining <= 0 {
+		delete(level, price)
+		return DepthUpdate{Symbol: b.symbol, Side: side, Action: DepthUpdateRemove, Price: price}
+	}
+
+	level[price] = remaining
+	return DepthUpdate{Symbol: b.symbol, Side: side, Action: DepthUpdateReplace, Price: price, Quantity: remaining}
+}
+
+// levels returns the resting-quantity map for side. Callers must hold
+// b.mutex.
+func (b *DepthBook) levels(side core.OrderSide) map[float64]float64 {
+	if side == core.OrderSideSell {
+		return b.asks
+	}
+	return b.bids
+}
+
+// Snapshot returns the top limit price levels on each side: bids sorted
+// highest-first, asks sorted lowest-first.
+func (b *DepthBook) Snapshot(limit int) DepthSnapshot {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	bids := sortedLevels(b.bids, func(a, bPrice float64) bool { return a > bPrice }, limit)
+	asks := sortedLevels(b.asks, func(a, bPrice float64) bool { return a < bPrice }, limit)
+
+	return DepthSnapshot{Symbol: b.symbol, Bids: bids, Asks: asks}
+}
+
+func sortedLevels(levels map[float64]float64, less func(a, b float64) bool, limit int) []DepthLevel {
+	prices := make([]float64, 0, len(levels))
+	for price := range levels {
+		prices = append(prices, price)
+	}
+	sort.Slice(prices, func(i, j int) bool { return less(prices[i], prices[j]) })
+
+	if limit > 0 && limit < len(prices) {
+		prices = prices[:limit]
+	}
+
+	result := make([]DepthLevel, 0, len(prices))
+	for _, price := range prices {
+		result = append(result, DepthLevel{Price: price, Quantity: levels[price]})
+	}
+	return result
+}
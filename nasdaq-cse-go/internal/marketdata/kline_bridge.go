@@ -0,0 +1,23 @@
+package marketdata
+
+import (
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/pkg/indicator"
+)
+
+// StreamKLines subscribes to symbol through the service's routed sources
+// and forwards every tick into agg, the way a strategy wires its
+// indicators to live market data.
+func (mds *MarketDataService) StreamKLines(symbol string, agg *indicator.KLineAggregator) error {
+	ticks, err := mds.SubscribeSymbol(symbol)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for tick := range ticks {
+			agg.OnTick(symbol, tick.Last, tick.Volume, tick.Timestamp)
+		}
+	}()
+
+	return nil
+}
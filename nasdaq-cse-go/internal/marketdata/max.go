@@ -0,0 +1,131 @@
+package marketdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// MaxSource streams quotes from MAX (Taiwan's MaiCoin exchange), combining
+// its public REST ticker for snapshots with its websocket feed for
+// subscriptions.
+type MaxSource struct {
+	client *http.Client
+}
+
+// NewMaxSource creates a MaxSource.
+func NewMaxSource() *MaxSource {
+	return &MaxSource{client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// Name returns the source's identifier.
+func (m *MaxSource) Name() string {
+	return "max"
+}
+
+type maxTicker struct {
+	Buy  string `json:"buy"`
+	Sell string `json:"sell"`
+	Last string `json:"last"`
+	Vol  string `json:"vol"`
+}
+
+// Snapshot fetches the current ticker for symbol (a MAX market code, e.g.
+// "btctwd") via MAX's REST API.
+func (m *MaxSource) Snapshot(symbol string) (Tick, error) {
+	url := fmt.Sprintf("https://max-api.maicoin.com/api/v2/tickers/%s", strings.ToLower(symbol))
+	resp, err := m.client.Get(url)
+	if err != nil {
+		return Tick{}, fmt.Errorf("max: failed to fetch %s: %w", symbol, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Tick{}, fmt.Errorf("max: %s returned status %d", symbol, resp.StatusCode)
+	}
+
+	var t maxTicker
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return Tick{}, fmt.Errorf("max: failed to decode response for %s: %w", symbol, err)
+	}
+
+	return maxTickerToTick(symbol, t)
+}
+
+type maxWSEvent struct {
+	Channel string     `json:"c"`
+	Event   string     `json:"e"`
+	Market  string     `json:"m"`
+	Ticker  *maxTicker `json:"t"`
+}
+
+// Subscribe opens a websocket to MAX's public stream, subscribes to the
+// ticker channel for symbol, and pushes a Tick per update.
+func (m *MaxSource) Subscribe(symbol string) (<-chan Tick, error) {
+	conn, _, err := websocket.DefaultDialer.Dial("wss://max-stream.maicoin.com/ws", nil)
+	if err != nil {
+		return nil, fmt.Errorf("max: failed to dial stream: %w", err)
+	}
+
+	subscribe := map[string]interface{}{
+		"action": "sub",
+		"subscriptions": []map[string]string{
+			{"channel": "ticker", "market": strings.ToLower(symbol)},
+		},
+		"id": "nasdaq-cse-go",
+	}
+	if err := conn.WriteJSON(subscribe); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("max: failed to subscribe to %s: %w", symbol, err)
+	}
+
+	ch := make(chan Tick, 16)
+	go func() {
+		defer conn.Close()
+		defer close(ch)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var evt maxWSEvent
+			if err := json.Unmarshal(message, &evt); err != nil || evt.Ticker == nil {
+				continue
+			}
+			tick, err := maxTickerToTick(symbol, *evt.Ticker)
+			if err != nil {
+				continue
+			}
+			ch <- tick
+		}
+	}()
+
+	return ch, nil
+}
+
+func maxTickerToTick(symbol string, t maxTicker) (Tick, error) {
+	bid, err := strconv.ParseFloat(t.Buy, 64)
+	if err != nil {
+		return Tick{}, fmt.Errorf("max: invalid buy price %q: %w", t.Buy, err)
+	}
+	ask, err := strconv.ParseFloat(t.Sell, 64)
+	if err != nil {
+		return Tick{}, fmt.Errorf("max: invalid sell price %q: %w", t.Sell, err)
+	}
+	last, _ := strconv.ParseFloat(t.Last, 64)
+	volFloat, _ := strconv.ParseFloat(t.Vol, 64)
+
+	return Tick{
+		Symbol:    symbol,
+		Bid:       bid,
+		Ask:       ask,
+		Last:      last,
+		Volume:    int64(volFloat),
+		Timestamp: time.Now(),
+	}, nil
+}
@@ -0,0 +1,134 @@
+package marketdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// OkxSource streams quotes from OKX's public ticker channel.
+type OkxSource struct {
+	client *http.Client
+}
+
+// NewOkxSource creates an OkxSource.
+func NewOkxSource() *OkxSource {
+	return &OkxSource{client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// Name returns the source's identifier.
+func (o *OkxSource) Name() string {
+	return "okx"
+}
+
+type okxTickerData struct {
+	InstID string `json:"instId"`
+	BidPx  string `json:"bidPx"`
+	AskPx  string `json:"askPx"`
+	Last   string `json:"last"`
+	Vol24h string `json:"vol24h"`
+}
+
+type okxTickerResponse struct {
+	Data []okxTickerData `json:"data"`
+}
+
+// Snapshot fetches the current ticker for symbol (an OKX instId, e.g.
+// "BTC-USDT") via OKX's REST API.
+func (o *OkxSource) Snapshot(symbol string) (Tick, error) {
+	url := fmt.Sprintf("https://www.okx.com/api/v5/market/ticker?instId=%s", strings.ToUpper(symbol))
+	resp, err := o.client.Get(url)
+	if err != nil {
+		return Tick{}, fmt.Errorf("okx: failed to fetch %s: %w", symbol, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Tick{}, fmt.Errorf("okx: %s returned status %d", symbol, resp.StatusCode)
+	}
+
+	var parsed okxTickerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return Tick{}, fmt.Errorf("okx: failed to decode response for %s: %w", symbol, err)
+	}
+	if len(parsed.Data) == 0 {
+		return Tick{}, fmt.Errorf("okx: no ticker data for %s", symbol)
+	}
+
+	return okxTickerToTick(parsed.Data[0])
+}
+
+type okxWSMessage struct {
+	Arg  json.RawMessage `json:"arg"`
+	Data []okxTickerData `json:"data"`
+}
+
+// Subscribe opens a websocket to OKX's public channel, subscribes to the
+// tickers channel for symbol, and pushes a Tick per update.
+func (o *OkxSource) Subscribe(symbol string) (<-chan Tick, error) {
+	conn, _, err := websocket.DefaultDialer.Dial("wss://ws.okx.com:8443/ws/v5/public", nil)
+	if err != nil {
+		return nil, fmt.Errorf("okx: failed to dial public channel: %w", err)
+	}
+
+	subscribe := map[string]interface{}{
+		"op": "subscribe",
+		"args": []map[string]string{
+			{"channel": "tickers", "instId": strings.ToUpper(symbol)},
+		},
+	}
+	if err := conn.WriteJSON(subscribe); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("okx: failed to subscribe to %s: %w", symbol, err)
+	}
+
+	ch := make(chan Tick, 16)
+	go func() {
+		defer conn.Close()
+		defer close(ch)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var msg okxWSMessage
+			if err := json.Unmarshal(message, &msg); err != nil || len(msg.Data) == 0 {
+				continue
+			}
+			tick, err := okxTickerToTick(msg.Data[0])
+			if err != nil {
+				continue
+			}
+			ch <- tick
+		}
+	}()
+
+	return ch, nil
+}
+
+func okxTickerToTick(d okxTickerData) (Tick, error) {
+	bid, err := strconv.ParseFloat(d.BidPx, 64)
+	if err != nil {
+		return Tick{}, fmt.Errorf("okx: invalid bidPx %q: %w", d.BidPx, err)
+	}
+	ask, err := strconv.ParseFloat(d.AskPx, 64)
+	if err != nil {
+		return Tick{}, fmt.Errorf("okx: invalid askPx %q: %w", d.AskPx, err)
+	}
+	last, _ := strconv.ParseFloat(d.Last, 64)
+	volFloat, _ := strconv.ParseFloat(d.Vol24h, 64)
+
+	return Tick{
+		Symbol:    d.InstID,
+		Bid:       bid,
+		Ask:       ask,
+		Last:      last,
+		Volume:    int64(volFloat),
+		Timestamp: time.Now(),
+	}, nil
+}
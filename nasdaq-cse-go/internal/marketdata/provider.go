@@ -7,6 +7,7 @@ import (
 	"math"
 	"math/rand"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,17 +18,28 @@ import (
 type GoldPriceProvider struct {
 	basePrice    float64
 	currentPrice float64
-	priceHistory []core.MarketDataResponse
+	symbol       string
+	store        TickStore
 	lastUpdate   time.Time
 	mutex        sync.RWMutex
 }
 
-// NewGoldPriceProvider creates a new gold price provider
+// NewGoldPriceProvider creates a new gold price provider backed by an
+// in-memory TickStore capped at 1000 points, matching the old ring-buffer
+// behavior.
 func NewGoldPriceProvider() *GoldPriceProvider {
+	return NewGoldPriceProviderWithStore(NewMemoryTickStore(1000), "GOLD")
+}
+
+// NewGoldPriceProviderWithStore creates a gold price provider that persists
+// its tick history to store under symbol, so deployments can plug in
+// JSONFileStore or RedisStore instead of the in-memory default.
+func NewGoldPriceProviderWithStore(store TickStore, symbol string) *GoldPriceProvider {
 	return &GoldPriceProvider{
 		basePrice:    2050.0,
 		currentPrice: 2050.0,
-		priceHistory: make([]core.MarketDataResponse, 0),
+		symbol:       symbol,
+		store:        store,
 		lastUpdate:   time.Now(),
 	}
 }
@@ -114,36 +126,81 @@ func (gpp *GoldPriceProvider) GetCurrentPrice() core.MarketDataResponse {
 		Price:         math.Round(price*100) / 100,
 		Bid:           math.Round((price-spread/2)*100) / 100,
 		Ask:           math.Round((price+spread/2)*100) / 100,
-		Volume:        int64(rand.Intn(900) + 100), // 100-1000 volume
-		Change24h:     math.Round((rand.Float64()*4.0-2.0)*100) / 100, // -2.0 to +2.0
+		Volume:        int64(rand.Intn(900) + 100),                        // 100-1000 volume
+		Change24h:     math.Round((rand.Float64()*4.0-2.0)*100) / 100,     // -2.0 to +2.0
 		ChangePercent: math.Round((rand.Float64()*0.2-0.1)*10000) / 10000, // -0.1 to +0.1
 	}
 
-	// Store in history
-	gpp.mutex.Lock()
-	gpp.priceHistory = append(gpp.priceHistory, priceData)
-	
-	// Keep only last 1000 price points
-	if len(gpp.priceHistory) > 1000 {
-		gpp.priceHistory = gpp.priceHistory[len(gpp.priceHistory)-1000:]
-	}
-	gpp.mutex.Unlock()
+	// Persist to the tick store rather than an in-memory ring buffer.
+	_ = gpp.store.Append(Tick{
+		Symbol:    gpp.symbol,
+		Bid:       priceData.Bid,
+		Ask:       priceData.Ask,
+		Last:      priceData.Price,
+		Volume:    priceData.Volume,
+		Timestamp: priceData.Timestamp,
+	})
 
 	return priceData
 }
 
+// Name returns the source's identifier, satisfying PriceSource.
+func (gpp *GoldPriceProvider) Name() string {
+	return "metals.live"
+}
+
+// Snapshot returns the current gold price as a Tick, satisfying
+// PriceSource. symbol is accepted for interface compatibility but ignored:
+// this source only ever quotes spot gold.
+func (gpp *GoldPriceProvider) Snapshot(symbol string) (Tick, error) {
+	price := gpp.GetCurrentPrice()
+	return Tick{
+		Symbol:    symbol,
+		Bid:       price.Bid,
+		Ask:       price.Ask,
+		Last:      price.Price,
+		Volume:    price.Volume,
+		Timestamp: price.Timestamp,
+	}, nil
+}
+
+// Subscribe pushes a Tick every 5 seconds, satisfying PriceSource. There's
+// no real streaming gold feed here, so this just re-samples GetCurrentPrice
+// on an interval the way simulateMarketData already did.
+func (gpp *GoldPriceProvider) Subscribe(symbol string) (<-chan Tick, error) {
+	ch := make(chan Tick, 16)
+	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			tick, _ := gpp.Snapshot(symbol)
+			ch <- tick
+		}
+	}()
+	return ch, nil
+}
+
 // GetPriceHistory returns historical price data for specified duration
 func (gpp *GoldPriceProvider) GetPriceHistory(hours int) []core.MarketDataResponse {
-	gpp.mutex.RLock()
-	defer gpp.mutex.RUnlock()
-
 	cutoffTime := time.Now().Add(-time.Duration(hours) * time.Hour)
-	var result []core.MarketDataResponse
 
-	for _, price := range gpp.priceHistory {
-		if price.Timestamp.After(cutoffTime) {
-			result = append(result, price)
-		}
+	ticks, err := gpp.store.Query(gpp.symbol, cutoffTime)
+	if err != nil {
+		return nil
+	}
+
+	result := make([]core.MarketDataResponse, 0, len(ticks))
+	for _, t := range ticks {
+		// Change24h/ChangePercent aren't persisted per tick (they're a
+		// display-only annotation on the live quote), so history entries
+		// come back with those left at zero.
+		result = append(result, core.MarketDataResponse{
+			Timestamp: t.Timestamp,
+			Price:     t.Last,
+			Bid:       t.Bid,
+			Ask:       t.Ask,
+			Volume:    t.Volume,
+		})
 	}
 
 	return result
@@ -162,7 +219,7 @@ func NewChartGenerator(provider *GoldPriceProvider) *ChartGenerator {
 // CreatePriceChartData creates price chart data
 func (cg *ChartGenerator) CreatePriceChartData(hours int) core.ChartDataResponse {
 	priceHistory := cg.priceProvider.GetPriceHistory(hours)
-	
+
 	if len(priceHistory) == 0 {
 		return core.ChartDataResponse{
 			Data: []core.ChartDataPoint{},
@@ -199,7 +256,7 @@ func (cg *ChartGenerator) CreatePnLChartData(positionsData []map[string]interfac
 	for _, pos := range positionsData {
 		timestamp, _ := time.Parse(time.RFC3339, pos["timestamp"].(string))
 		unrealizedPnL, _ := pos["unrealized_pnl"].(float64)
-		
+
 		chartData = append(chartData, core.ChartDataPoint{
 			Timestamp: timestamp,
 			Price:     unrealizedPnL,
@@ -230,21 +287,171 @@ func (cg *ChartGenerator) CreateExposureChartData(exposureData map[string]float6
 	}
 }
 
+// SymbolRoute maps symbols matching Pattern (an exact symbol, or a prefix
+// ending in "*", or "*" for everything) to an ordered list of sources to
+// try: Sources[0] first, falling back to the next on error or staleness.
+type SymbolRoute struct {
+	Pattern string
+	Sources []PriceSource
+}
+
+func (r SymbolRoute) matches(symbol string) bool {
+	if r.Pattern == "*" {
+		return true
+	}
+	if strings.HasSuffix(r.Pattern, "*") {
+		return strings.HasPrefix(symbol, strings.TrimSuffix(r.Pattern, "*"))
+	}
+	return r.Pattern == symbol
+}
+
 // MarketDataService provides market data functionality
 type MarketDataService struct {
 	priceProvider  *GoldPriceProvider
 	chartGenerator *ChartGenerator
+
+	routes     []SymbolRoute
+	staleAfter time.Duration
+
+	mutex    sync.RWMutex
+	lastTick map[string]Tick
+
+	// depthBooks holds one *DepthBook per symbol, created lazily on first
+	// access via DepthBookFor. sync.Map suits this better than a mutex-
+	// guarded map since every constructor would otherwise need to
+	// initialize it.
+	depthBooks sync.Map
 }
 
-// NewMarketDataService creates a new market data service
+// NewMarketDataService creates a new market data service, routing GOLD*
+// symbols to the metals.live-backed provider and everything else to
+// Binance, with MAX and OKX available as additional sources via
+// NewMarketDataServiceWithRoutes.
 func NewMarketDataService() *MarketDataService {
 	provider := NewGoldPriceProvider()
 	generator := NewChartGenerator(provider)
 
+	mds := &MarketDataService{
+		priceProvider:  provider,
+		chartGenerator: generator,
+		staleAfter:     30 * time.Second,
+		lastTick:       make(map[string]Tick),
+	}
+	mds.routes = []SymbolRoute{
+		{Pattern: "GOLD*", Sources: []PriceSource{provider}},
+		{Pattern: "*", Sources: []PriceSource{NewBinanceSource()}},
+	}
+	return mds
+}
+
+// NewMarketDataServiceWithRoutes creates a market data service with custom
+// symbol-to-source routing, for deployments that need MAX/OKX primaries or
+// a different failover order than the GOLD*/Binance default.
+func NewMarketDataServiceWithRoutes(routes []SymbolRoute) *MarketDataService {
+	provider := NewGoldPriceProvider()
+	generator := NewChartGenerator(provider)
+
 	return &MarketDataService{
 		priceProvider:  provider,
 		chartGenerator: generator,
+		routes:         routes,
+		staleAfter:     30 * time.Second,
+		lastTick:       make(map[string]Tick),
+	}
+}
+
+// NewMarketDataServiceWithStore creates a market data service whose GOLD
+// price history is persisted to store (e.g. JSONFileStore or RedisStore)
+// instead of the in-memory default, mirroring the `persistence:` block of
+// a bbgo-style strategy config.
+func NewMarketDataServiceWithStore(store TickStore) *MarketDataService {
+	provider := NewGoldPriceProviderWithStore(store, "GOLD")
+	generator := NewChartGenerator(provider)
+
+	mds := &MarketDataService{
+		priceProvider:  provider,
+		chartGenerator: generator,
+		staleAfter:     30 * time.Second,
+		lastTick:       make(map[string]Tick),
+	}
+	mds.routes = []SymbolRoute{
+		{Pattern: "GOLD*", Sources: []PriceSource{provider}},
+		{Pattern: "*", Sources: []PriceSource{NewBinanceSource()}},
+	}
+	return mds
+}
+
+// DepthBookFor returns the DepthBook for symbol, creating an empty one on
+// first access.
+func (mds *MarketDataService) DepthBookFor(symbol string) *DepthBook {
+	if existing, ok := mds.depthBooks.Load(symbol); ok {
+		return existing.(*DepthBook)
+	}
+	created, _ := mds.depthBooks.LoadOrStore(symbol, NewDepthBook(symbol))
+	return created.(*DepthBook)
+}
+
+// SnapshotDepth returns the top limit price levels of symbol's order book
+// on each side.
+func (mds *MarketDataService) SnapshotDepth(symbol string, limit int) DepthSnapshot {
+	return mds.DepthBookFor(symbol).Snapshot(limit)
+}
+
+// sourcesFor returns the ordered sources configured for symbol, or nil if
+// no route matches it.
+func (mds *MarketDataService) sourcesFor(symbol string) []PriceSource {
+	for _, route := range mds.routes {
+		if route.matches(symbol) {
+			return route.Sources
+		}
 	}
+	return nil
+}
+
+// SnapshotSymbol fetches the latest tick for symbol, trying each routed
+// source in order and falling back to the next on error. If every source
+// fails, it returns the last known tick as long as it isn't stale.
+func (mds *MarketDataService) SnapshotSymbol(symbol string) (Tick, error) {
+	var lastErr error
+	for _, source := range mds.sourcesFor(symbol) {
+		tick, err := source.Snapshot(symbol)
+		if err == nil {
+			mds.mutex.Lock()
+			mds.lastTick[symbol] = tick
+			mds.mutex.Unlock()
+			return tick, nil
+		}
+		lastErr = fmt.Errorf("%s: %w", source.Name(), err)
+	}
+
+	mds.mutex.RLock()
+	cached, ok := mds.lastTick[symbol]
+	mds.mutex.RUnlock()
+	if ok && time.Since(cached.Timestamp) < mds.staleAfter {
+		return cached, nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no source routed for symbol %s", symbol)
+	}
+	return Tick{}, fmt.Errorf("failed to snapshot %s: %w", symbol, lastErr)
+}
+
+// SubscribeSymbol subscribes to symbol's primary source, failing over to
+// the next routed source if the primary's Subscribe call itself fails.
+func (mds *MarketDataService) SubscribeSymbol(symbol string) (<-chan Tick, error) {
+	var lastErr error
+	for _, source := range mds.sourcesFor(symbol) {
+		ch, err := source.Subscribe(symbol)
+		if err == nil {
+			return ch, nil
+		}
+		lastErr = fmt.Errorf("%s: %w", source.Name(), err)
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no source routed for symbol %s", symbol)
+	}
+	return nil, fmt.Errorf("failed to subscribe to %s: %w", symbol, lastErr)
 }
 
 // GetCurrentPrice returns current market price
@@ -270,4 +477,4 @@ func (mds *MarketDataService) GetPnLChartData(positionsData []map[string]interfa
 // GetExposureChartData returns chart data for exposure analysis
 func (mds *MarketDataService) GetExposureChartData(exposureData map[string]float64) map[string]interface{} {
 	return mds.chartGenerator.CreateExposureChartData(exposureData)
-}
\ No newline at end of file
+}
@@ -0,0 +1,162 @@
+package marketdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Tick is one normalized quote/trade update from a PriceSource, regardless
+// of which exchange or feed produced it.
+type Tick struct {
+	Symbol    string
+	Bid       float64
+	Ask       float64
+	Last      float64
+	Volume    int64
+	Timestamp time.Time
+}
+
+// PriceSource is anything that can stream or snapshot ticks for a symbol:
+// an exchange websocket feed, a REST poller, or (for tests) a fake. This is
+// the seam MarketDataService fans its multiple upstreams through, the way
+// bbgo/goex abstract one exchange interface behind several adapters.
+type PriceSource interface {
+	// Name identifies the source for logging and routing (e.g. "binance").
+	Name() string
+	// Subscribe starts streaming ticks for symbol, returning a channel the
+	// caller should drain until it closes (the source stopped or errored).
+	Subscribe(symbol string) (<-chan Tick, error)
+	// Snapshot returns the latest known tick for symbol, fetching one
+	// on-demand if the source doesn't keep a running feed.
+	Snapshot(symbol string) (Tick, error)
+}
+
+// RESTPollSource is a generic PriceSource that polls a per-symbol HTTP
+// endpoint on an interval and parses the response with a caller-supplied
+// function. It's the building block for simple REST-only feeds (the
+// metals.live behavior GoldPriceProvider retains in full, plus any future
+// single-endpoint source that doesn't need GoldPriceProvider's mean-
+// reversion simulation fallback).
+type RESTPollSource struct {
+	name     string
+	interval time.Duration
+	client   *http.Client
+	parse    func(body []byte) (bid, ask, last float64, volume int64, err error)
+
+	mutex sync.RWMutex
+	urls  map[string]string
+	last  map[string]Tick
+}
+
+// NewRESTPollSource creates a RESTPollSource identified by name, polling
+// every interval and parsing responses with parse.
+func NewRESTPollSource(name string, interval time.Duration, parse func(body []byte) (bid, ask, last float64, volume int64, err error)) *RESTPollSource {
+	return &RESTPollSource{
+		name:     name,
+		interval: interval,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		parse:    parse,
+		urls:     make(map[string]string),
+		last:     make(map[string]Tick),
+	}
+}
+
+// AddSymbol registers the endpoint to poll for symbol.
+func (s *RESTPollSource) AddSymbol(symbol, url string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.urls[symbol] = url
+}
+
+// Name returns the source's identifier.
+func (s *RESTPollSource) Name() string {
+	return s.name
+}
+
+// Snapshot fetches and parses the endpoint registered for symbol.
+func (s *RESTPollSource) Snapshot(symbol string) (Tick, error) {
+	s.mutex.RLock()
+	url, ok := s.urls[symbol]
+	s.mutex.RUnlock()
+	if !ok {
+		return Tick{}, fmt.Errorf("%s: no endpoint registered for symbol %s", s.name, symbol)
+	}
+
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return Tick{}, fmt.Errorf("%s: failed to fetch %s: %w", s.name, symbol, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Tick{}, fmt.Errorf("%s: %s returned status %d", s.name, symbol, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Tick{}, fmt.Errorf("%s: failed to read response for %s: %w", s.name, symbol, err)
+	}
+
+	bid, ask, last, volume, err := s.parse(body)
+	if err != nil {
+		return Tick{}, fmt.Errorf("%s: failed to parse response for %s: %w", s.name, symbol, err)
+	}
+
+	tick := Tick{Symbol: symbol, Bid: bid, Ask: ask, Last: last, Volume: volume, Timestamp: time.Now()}
+	s.mutex.Lock()
+	s.last[symbol] = tick
+	s.mutex.Unlock()
+	return tick, nil
+}
+
+// Subscribe polls the registered endpoint for symbol every interval,
+// pushing ticks to the returned channel until the source is garbage
+// collected (there's no explicit stop; callers drop the channel to stop
+// reading from it).
+func (s *RESTPollSource) Subscribe(symbol string) (<-chan Tick, error) {
+	s.mutex.RLock()
+	_, ok := s.urls[symbol]
+	s.mutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%s: no endpoint registered for symbol %s", s.name, symbol)
+	}
+
+	ch := make(chan Tick, 16)
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if tick, err := s.Snapshot(symbol); err == nil {
+				ch <- tick
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// NewMetalsLiveSource creates a RESTPollSource wired to metals.live's spot
+// price endpoint for symbol, for deployments that want the plain REST feed
+// (no mean-reversion simulation fallback) as a routed PriceSource.
+func NewMetalsLiveSource(symbol string, interval time.Duration) *RESTPollSource {
+	s := NewRESTPollSource("metals.live", interval, parseMetalsLiveResponse)
+	s.AddSymbol(symbol, "https://api.metals.live/v1/spot/gold")
+	return s
+}
+
+// parseMetalsLiveResponse parses the metals.live spot price payload
+// ({"price": 2050.12}) into a Tick's fields, synthesizing a bid/ask spread
+// since the feed only publishes a single mid price.
+func parseMetalsLiveResponse(body []byte) (bid, ask, last float64, volume int64, err error) {
+	var payload struct {
+		Price float64 `json:"price"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	const halfSpread = 0.5
+	return payload.Price - halfSpread, payload.Price + halfSpread, payload.Price, 0, nil
+}
@@ -0,0 +1,327 @@
+package marketdata
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// OHLCVBar is a downsampled candle produced by TickCompactor once ticks age
+// out of their full-resolution retention window.
+type OHLCVBar struct {
+	Symbol    string
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    int64
+	Timestamp time.Time // bar open time, truncated to the bucket interval
+}
+
+// TickStore persists tick history per symbol so GoldPriceProvider (and any
+// other PriceSource-backed feed) doesn't have to keep an unbounded
+// in-memory slice. GetPriceHistory queries it instead of a ring buffer.
+type TickStore interface {
+	Append(tick Tick) error
+	Query(symbol string, since time.Time) ([]Tick, error)
+	AppendOHLCV(bar OHLCVBar) error
+}
+
+// MemoryTickStore is the default in-process TickStore, capped at maxPoints
+// per symbol the way GoldPriceProvider's old ring buffer was. It's what
+// NewGoldPriceProvider uses when callers don't configure persistence.
+type MemoryTickStore struct {
+	maxPoints int
+	mutex     sync.RWMutex
+	ticks     map[string][]Tick
+}
+
+// NewMemoryTickStore creates a MemoryTickStore keeping at most maxPoints
+// ticks per symbol.
+func NewMemoryTickStore(maxPoints int) *MemoryTickStore {
+	return &MemoryTickStore{maxPoints: maxPoints, ticks: make(map[string][]Tick)}
+}
+
+// Append records tick, trimming the oldest entries once maxPoints is
+// exceeded.
+func (m *MemoryTickStore) Append(tick Tick) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	series := append(m.ticks[tick.Symbol], tick)
+	if len(series) > m.maxPoints {
+		series = series[len(series)-m.maxPoints:]
+	}
+	m.ticks[tick.Symbol] = series
+	return nil
+}
+
+// Query returns symbol's ticks recorded at or after since.
+func (m *MemoryTickStore) Query(symbol string, since time.Time) ([]Tick, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	var result []Tick
+	for _, t := range m.ticks[symbol] {
+		if !t.Timestamp.Before(since) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// AppendOHLCV is a no-op for MemoryTickStore: it only keeps full-resolution
+// ticks, so there's nothing to compact down.
+func (m *MemoryTickStore) AppendOHLCV(bar OHLCVBar) error {
+	return nil
+}
+
+// JSONFileStore appends ticks as NDJSON (one JSON object per line) under
+// dir, one file per symbol per UTC day, and OHLCV bars to a parallel
+// per-symbol file.
+type JSONFileStore struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewJSONFileStore creates a JSONFileStore rooted at dir.
+func NewJSONFileStore(dir string) (*JSONFileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create tick store directory: %w", err)
+	}
+	return &JSONFileStore{dir: dir}, nil
+}
+
+func (j *JSONFileStore) tickPath(symbol string, day time.Time) string {
+	return filepath.Join(j.dir, fmt.Sprintf("%s_%s.ndjson", symbol, day.UTC().Format("20060102")))
+}
+
+func (j *JSONFileStore) ohlcvPath(symbol string) string {
+	return filepath.Join(j.dir, fmt.Sprintf("%s_ohlcv_1m.ndjson", symbol))
+}
+
+// Append writes tick to today's (UTC) NDJSON file for its symbol.
+func (j *JSONFileStore) Append(tick Tick) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return appendNDJSON(j.tickPath(tick.Symbol, tick.Timestamp), tick)
+}
+
+// Query reads every daily file between since and now for symbol and
+// returns the ticks recorded at or after since.
+func (j *JSONFileStore) Query(symbol string, since time.Time) ([]Tick, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	var result []Tick
+	for day := since.UTC().Truncate(24 * time.Hour); !day.After(time.Now().UTC()); day = day.Add(24 * time.Hour) {
+		f, err := os.Open(j.tickPath(symbol, day))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to open tick history for %s on %s: %w", symbol, day.Format("2006-01-02"), err)
+		}
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var tick Tick
+			if err := json.Unmarshal(scanner.Bytes(), &tick); err != nil {
+				continue
+			}
+			if !tick.Timestamp.Before(since) {
+				result = append(result, tick)
+			}
+		}
+		f.Close()
+	}
+
+	sort.Slice(result, func(i, k int) bool { return result[i].Timestamp.Before(result[k].Timestamp) })
+	return result, nil
+}
+
+// AppendOHLCV appends bar to symbol's 1-minute OHLCV NDJSON file.
+func (j *JSONFileStore) AppendOHLCV(bar OHLCVBar) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return appendNDJSON(j.ohlcvPath(bar.Symbol), bar)
+}
+
+func appendNDJSON(path string, v interface{}) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %T: %w", v, err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to append to %s: %w", path, err)
+	}
+	return nil
+}
+
+// RedisStore persists ticks in a Redis sorted set per symbol
+// (ZADD symbol:ticks <unix-nano> <json>), trimming anything older than
+// retention on every write, and OHLCV bars under a parallel
+// symbol:ohlcv:1m sorted set.
+type RedisStore struct {
+	client    *redis.Client
+	retention time.Duration
+}
+
+// NewRedisStore creates a RedisStore against addr (host:port), keeping
+// retention worth of history per symbol.
+func NewRedisStore(addr string, db int, retention time.Duration) *RedisStore {
+	return &RedisStore{
+		client:    redis.NewClient(&redis.Options{Addr: addr, DB: db}),
+		retention: retention,
+	}
+}
+
+func (r *RedisStore) tickKey(symbol string) string {
+	return symbol + ":ticks"
+}
+
+func (r *RedisStore) ohlcvKey(symbol string) string {
+	return symbol + ":ohlcv:1m"
+}
+
+// Append ZADDs tick under its symbol's sorted set and trims entries older
+// than the configured retention window.
+func (r *RedisStore) Append(tick Tick) error {
+	ctx := context.Background()
+	data, err := json.Marshal(tick)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tick: %w", err)
+	}
+
+	key := r.tickKey(tick.Symbol)
+	score := float64(tick.Timestamp.UnixNano())
+	if err := r.client.ZAdd(ctx, key, redis.Z{Score: score, Member: data}).Err(); err != nil {
+		return fmt.Errorf("failed to append tick to redis: %w", err)
+	}
+
+	cutoff := float64(time.Now().Add(-r.retention).UnixNano())
+	return r.client.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatFloat(cutoff, 'f', 0, 64)).Err()
+}
+
+// Query returns ticks recorded for symbol at or after since.
+func (r *RedisStore) Query(symbol string, since time.Time) ([]Tick, error) {
+	ctx := context.Background()
+	key := r.tickKey(symbol)
+	members, err := r.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(since.UnixNano(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticks from redis: %w", err)
+	}
+
+	ticks := make([]Tick, 0, len(members))
+	for _, m := range members {
+		var tick Tick
+		if err := json.Unmarshal([]byte(m), &tick); err != nil {
+			continue
+		}
+		ticks = append(ticks, tick)
+	}
+	return ticks, nil
+}
+
+// AppendOHLCV ZADDs bar under symbol's OHLCV sorted set, keyed by its bar
+// open time.
+func (r *RedisStore) AppendOHLCV(bar OHLCVBar) error {
+	ctx := context.Background()
+	data, err := json.Marshal(bar)
+	if err != nil {
+		return fmt.Errorf("failed to marshal OHLCV bar: %w", err)
+	}
+	key := r.ohlcvKey(bar.Symbol)
+	return r.client.ZAdd(ctx, key, redis.Z{Score: float64(bar.Timestamp.UnixNano()), Member: data}).Err()
+}
+
+// TickCompactor periodically downsamples ticks older than MaxAge into
+// 1-minute OHLCV bars and writes them back to the same TickStore. It does
+// not remove the ticks it folds: bounding tick history is left to the
+// store (MemoryTickStore's cap, RedisStore's retention), and a tick still
+// in the store is folded again on every later pass.
+type TickCompactor struct {
+	store   TickStore
+	symbols []string
+	maxAge  time.Duration
+	stop    chan struct{}
+}
+
+// NewTickCompactor creates a TickCompactor for the given symbols, folding
+// ticks older than maxAge into 1-minute bars on each compaction pass.
+func NewTickCompactor(store TickStore, symbols []string, maxAge time.Duration) *TickCompactor {
+	return &TickCompactor{store: store, symbols: symbols, maxAge: maxAge, stop: make(chan struct{})}
+}
+
+// Start runs the compaction pass every interval until Stop is called.
+func (c *TickCompactor) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.compactOnce()
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background compaction loop.
+func (c *TickCompactor) Stop() {
+	close(c.stop)
+}
+
+func (c *TickCompactor) compactOnce() {
+	cutoff := time.Now().Add(-c.maxAge)
+	for _, symbol := range c.symbols {
+		ticks, err := c.store.Query(symbol, time.Time{})
+		if err != nil {
+			continue
+		}
+
+		bars := make(map[int64]*OHLCVBar)
+		var order []int64
+		for _, t := range ticks {
+			if !t.Timestamp.Before(cutoff) {
+				continue
+			}
+			bucket := t.Timestamp.Truncate(time.Minute).Unix()
+			bar, ok := bars[bucket]
+			if !ok {
+				bar = &OHLCVBar{Symbol: symbol, Open: t.Last, High: t.Last, Low: t.Last, Timestamp: time.Unix(bucket, 0).UTC()}
+				bars[bucket] = bar
+				order = append(order, bucket)
+			}
+			if t.Last > bar.High {
+				bar.High = t.Last
+			}
+			if t.Last < bar.Low {
+				bar.Low = t.Last
+			}
+			bar.Close = t.Last
+			bar.Volume += t.Volume
+		}
+
+		for _, bucket := range order {
+			_ = c.store.AppendOHLCV(*bars[bucket])
+		}
+	}
+}
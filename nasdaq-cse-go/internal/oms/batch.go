@@ -0,0 +1,257 @@
+package oms
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"gorm.io/gorm"
+)
+
+// batchEntry pairs a request with the order/contract it resolved to, so
+// BatchSubmitOrders can sort and process it while still reporting back to
+// the caller's original index.
+type batchEntry struct {
+	index    int
+	order    *core.Order
+	contract core.Contract
+}
+
+// BatchSubmitOrders submits reqs for userID as a single unit instead of
+// reqs-many round trips: it resolves every contract symbol with one
+// query, bulk-inserts the orders in one transaction, then feeds them to
+// the matching engine in a deterministic best-priced-first order. This is
+// what a 10-50 order grid/market-making placement should cost, rather
+// than the 3+ round trips per order SubmitOrder needs one at a time.
+func (om *OrderManager) BatchSubmitOrders(userID uint, reqs []core.OrderCreateRequest) []SubmitOrderResult {
+	results := make([]SubmitOrderResult, len(reqs))
+	if len(reqs) == 0 {
+		return results
+	}
+
+	symbols := make([]string, 0, len(reqs))
+	seenSymbol := make(map[string]bool, len(reqs))
+	for _, req := range reqs {
+		if !seenSymbol[req.ContractSymbol] {
+			seenSymbol[req.ContractSymbol] = true
+			symbols = append(symbols, req.ContractSymbol)
+		}
+	}
+
+	var contracts []core.Contract
+	if err := om.db.Where("symbol IN ?", symbols).Find(&contracts).Error; err != nil {
+		for i := range results {
+			results[i] = SubmitOrderResult{Success: false, Error: fmt.Sprintf("Failed to load contracts: %v", err)}
+		}
+		return results
+	}
+	contractBySymbol := make(map[string]core.Contract, len(contracts))
+	for _, c := range contracts {
+		contractBySymbol[c.Symbol] = c
+	}
+
+	orders := make([]*core.Order, 0, len(reqs))
+	entries := make([]*batchEntry, 0, len(reqs))
+	for i, req := range reqs {
+		contract, ok := contractBySymbol[req.ContractSymbol]
+		if !ok {
+			results[i] = SubmitOrderResult{Success: false, Error: "Contract not found"}
+			continue
+		}
+
+		strategy := req.Strategy
+		if strategy == "" {
+			strategy = "manual"
+		}
+
+		order := &core.Order{
+			OrderID:    uuid.New().String(),
+			UserID:     userID,
+			ContractID: contract.ID,
+			Side:       req.Side,
+			OrderType:  req.OrderType,
+			Quantity:   req.Quantity,
+			Price:      req.Price,
+			StopPrice:  req.StopPrice,
+			Strategy:   strategy,
+			Status:     core.OrderStatusPending,
+		}
+		orders = append(orders, order)
+		entries = append(entries, &batchEntry{index: i, order: order, contract: contract})
+	}
+
+	if len(orders) == 0 {
+		return results
+	}
+
+	err := om.db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(orders, 50).Error
+	})
+	if err != nil {
+		for _, e := range entries {
+			results[e.index] = SubmitOrderResult{Success: false, Error: fmt.Sprintf("Failed to create orders: %v", err)}
+		}
+		return results
+	}
+
+	for _, e := range entries {
+		om.emitOrderNew(*e.order)
+	}
+
+	sortForMatching(entries)
+
+	for _, e := range entries {
+		results[e.index] = om.processAndSave(e.order, &e.contract)
+	}
+
+	return results
+}
+
+// processAndSave runs order through the matching engine, persists the
+// resulting status, and folds any fills into positions — the same steps
+// SubmitOrder takes after creating its single order.
+func (om *OrderManager) processAndSave(order *core.Order, contract *core.Contract) SubmitOrderResult {
+	trades, err := om.matchingEngine.ProcessOrder(order, contract, om.db)
+	if err != nil {
+		return SubmitOrderResult{Success: false, Error: fmt.Sprintf("Failed to process order: %v", err)}
+	}
+
+	if err := om.db.Save(order).Error; err != nil {
+		return SubmitOrderResult{Success: false, Error: fmt.Sprintf("Failed to update order: %v", err)}
+	}
+
+	if len(trades) > 0 {
+		if err := om.updatePositions(trades); err != nil {
+			return SubmitOrderResult{Success: false, Error: fmt.Sprintf("Failed to update positions: %v", err)}
+		}
+		om.emitOrderFilled(*order, trades)
+		for _, trade := range trades {
+			om.emitTrade(trade)
+		}
+	}
+
+	var tradeData []map[string]interface{}
+	for _, trade := range trades {
+		tradeData = append(tradeData, map[string]interface{}{
+			"trade_id": trade.TradeID,
+			"price":    trade.Price,
+			"quantity": trade.Quantity,
+		})
+	}
+
+	return SubmitOrderResult{
+		Success: true,
+		OrderID: order.OrderID,
+		Status:  string(order.Status),
+		Trades:  tradeData,
+	}
+}
+
+// sortForMatching orders entries so priced limit orders feed the matching
+// engine best-priced first on each side (highest bid, lowest ask first) —
+// the order a resting book would naturally want new liquidity placed in.
+// Priced buys come before priced sells. Market and stop orders (no firm
+// price to rank by) keep their original relative order and are processed
+// after priced limits.
+func sortForMatching(entries []*batchEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].order, entries[j].order
+		aPriced := a.OrderType == core.OrderTypeLimit && a.Price != nil
+		bPriced := b.OrderType == core.OrderTypeLimit && b.Price != nil
+		if aPriced != bPriced {
+			return aPriced
+		}
+		if !aPriced {
+			return false
+		}
+		if a.Side != b.Side {
+			return a.Side == core.OrderSideBuy
+		}
+		if a.Side == core.OrderSideBuy {
+			return *a.Price > *b.Price
+		}
+		return *a.Price < *b.Price
+	})
+}
+
+// RetryPolicy controls BatchRetryPlaceOrders' backoff between rounds:
+// delay doubles from BaseDelay up to MaxDelay after each round that still
+// has failures, plus jitter — the same shape as the exchange package's
+// backoffReconnect.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy is a sane default for grid/arb order placement: up
+// to 3 rounds total, starting at 250ms and capping at 5s.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 250 * time.Millisecond, MaxDelay: 5 * time.Second}
+
+// BatchRetryPlaceOrders submits reqs via BatchSubmitOrders and retries
+// only the entries that failed, backing off between rounds per policy,
+// until every entry succeeds, policy.MaxAttempts rounds have run, or ctx
+// is canceled. The returned slice is aligned to reqs regardless of how
+// many rounds an individual entry needed.
+func (om *OrderManager) BatchRetryPlaceOrders(ctx context.Context, userID uint, reqs []core.OrderCreateRequest, policy RetryPolicy) []SubmitOrderResult {
+	results := make([]SubmitOrderResult, len(reqs))
+	pending := make([]int, len(reqs))
+	for i := range reqs {
+		pending[i] = i
+	}
+
+	delay := policy.BaseDelay
+	for attempt := 0; len(pending) > 0 && attempt < policy.MaxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			failPending(results, pending, err)
+			return results
+		}
+
+		batch := make([]core.OrderCreateRequest, len(pending))
+		for i, idx := range pending {
+			batch[i] = reqs[idx]
+		}
+
+		batchResults := om.BatchSubmitOrders(userID, batch)
+
+		var stillPending []int
+		for i, idx := range pending {
+			results[idx] = batchResults[i]
+			if !batchResults[i].Success {
+				stillPending = append(stillPending, idx)
+			}
+		}
+		pending = stillPending
+
+		if len(pending) == 0 || attempt == policy.MaxAttempts-1 {
+			break
+		}
+
+		jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+		select {
+		case <-time.After(delay + jitter):
+		case <-ctx.Done():
+			failPending(results, pending, ctx.Err())
+			return results
+		}
+
+		delay *= 2
+		if delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+
+	return results
+}
+
+// failPending fills results[idx] for every idx still in pending with err,
+// used when BatchRetryPlaceOrders gives up early on a canceled context.
+func failPending(results []SubmitOrderResult, pending []int, err error) {
+	for _, idx := range pending {
+		results[idx] = SubmitOrderResult{Success: false, Error: err.Error()}
+	}
+}
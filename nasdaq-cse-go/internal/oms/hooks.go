@@ -0,0 +1,139 @@
+package oms
+
+import "github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+
+// hookBufferSize bounds each registered listener's pending-callback queue.
+const hookBufferSize = 64
+
+// hookQueue runs one subscriber's callbacks on its own goroutine, off of a
+// buffered queue of already-bound closures, so a slow or wedged listener
+// can never block SubmitOrder/BatchSubmitOrders/CancelOrder waiting for a
+// send. Unlike MatchingEngine.FillCh's publishFill, which drops the
+// newest trade on overflow, a full queue here drops its oldest pending
+// callback instead: lifecycle hooks are more naturally consumed as a
+// running log by a recomputer or broadcaster, where losing a constant
+// trickle of the very oldest backlog is less surprising than losing
+// whatever just happened.
+type hookQueue struct {
+	ch chan func()
+}
+
+func newHookQueue() *hookQueue {
+	q := &hookQueue{ch: make(chan func(), hookBufferSize)}
+	go func() {
+		for task := range q.ch {
+			task()
+		}
+	}()
+	return q
+}
+
+// publish enqueues task without blocking, dropping the oldest still-queued
+// task to make room if the buffer is full.
+func (q *hookQueue) publish(task func()) {
+	select {
+	case q.ch <- task:
+		return
+	default:
+	}
+	select {
+	case <-q.ch:
+	default:
+	}
+	select {
+	case q.ch <- task:
+	default:
+	}
+}
+
+// OnOrderNew registers fn to be called, off the caller's goroutine, every
+// time SubmitOrder or BatchSubmitOrders persists a brand-new order. fn
+// runs on its own hookQueue, so a slow fn only delays its own deliveries.
+func (om *OrderManager) OnOrderNew(fn func(*core.Order)) {
+	q := newHookQueue()
+	om.hooksMu.Lock()
+	defer om.hooksMu.Unlock()
+	om.onOrderNew = append(om.onOrderNew, func(order core.Order) {
+		q.publish(func() { fn(&order) })
+	})
+}
+
+// OnOrderFilled registers fn to be called whenever processing an order
+// produces at least one trade, whether it filled fully or partially.
+func (om *OrderManager) OnOrderFilled(fn func(*core.Order, []core.Trade)) {
+	q := newHookQueue()
+	om.hooksMu.Lock()
+	defer om.hooksMu.Unlock()
+	om.onOrderFilled = append(om.onOrderFilled, func(order core.Order, trades []core.Trade) {
+		q.publish(func() { fn(&order, trades) })
+	})
+}
+
+// OnOrderCancelled registers fn to be called whenever CancelOrder
+// successfully cancels an order.
+func (om *OrderManager) OnOrderCancelled(fn func(*core.Order)) {
+	q := newHookQueue()
+	om.hooksMu.Lock()
+	defer om.hooksMu.Unlock()
+	om.onOrderCancelled = append(om.onOrderCancelled, func(order core.Order) {
+		q.publish(func() { fn(&order) })
+	})
+}
+
+// OnTrade registers fn to be called once per trade, whether it came from
+// a single SubmitOrder call or one leg of a BatchSubmitOrders batch.
+func (om *OrderManager) OnTrade(fn func(core.Trade)) {
+	q := newHookQueue()
+	om.hooksMu.Lock()
+	defer om.hooksMu.Unlock()
+	om.onTrade = append(om.onTrade, func(trade core.Trade) {
+		q.publish(func() { fn(trade) })
+	})
+}
+
+// emitOrderNew notifies every OnOrderNew listener that order was created.
+func (om *OrderManager) emitOrderNew(order core.Order) {
+	om.hooksMu.Lock()
+	listeners := append([]func(core.Order){}, om.onOrderNew...)
+	om.hooksMu.Unlock()
+
+	for _, notify := range listeners {
+		notify(order)
+	}
+}
+
+// emitOrderFilled notifies every OnOrderFilled listener that order
+// produced trades.
+func (om *OrderManager) emitOrderFilled(order core.Order, trades []core.Trade) {
+	om.hooksMu.Lock()
+	listeners := append([]func(core.Order, []core.Trade){}, om.onOrderFilled...)
+	om.hooksMu.Unlock()
+
+	tradesCopy := append([]core.Trade{}, trades...)
+	for _, notify := range listeners {
+		notify(order, tradesCopy)
+	}
+}
+
+// emitOrderCancelled notifies every OnOrderCancelled listener that order
+// was cancelled.
+func (om *OrderManager) emitOrderCancelled(order core.Order) {
+	om.hooksMu.Lock()
+	listeners := append([]func(core.Order){}, om.onOrderCancelled...)
+	om.hooksMu.Unlock()
+
+	for _, notify := range listeners {
+		notify(order)
+	}
+}
+
+// emitTrade notifies every OnTrade listener of trade.
+func (om *OrderManager) emitTrade(trade core.Trade) {
+	om.hooksMu.Lock()
+	listeners := append([]func(core.Trade){}, om.onTrade...)
+	om.hooksMu.Unlock()
+
+	for _, notify := range listeners {
+		notify(trade)
+	}
+}
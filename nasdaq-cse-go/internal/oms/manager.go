@@ -3,7 +3,8 @@ package oms
 
 import (
 	"fmt"
-	"math/rand"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,177 +12,20 @@ import (
 	"gorm.io/gorm"
 )
 
-// MatchingEngine handles order execution and matching
-type MatchingEngine struct {
-	orderBook     map[string][]core.Order // Key: contract symbol
-	lastTradePrice map[string]float64      // Key: contract symbol
-}
-
-// NewMatchingEngine creates a new matching engine
-func NewMatchingEngine() *MatchingEngine {
-	return &MatchingEngine{
-		orderBook:      make(map[string][]core.Order),
-		lastTradePrice: make(map[string]float64),
-	}
-}
-
-// ProcessOrder processes an order through the matching engine
-func (me *MatchingEngine) ProcessOrder(order *core.Order, contract *core.Contract, db *gorm.DB) ([]core.Trade, error) {
-	var trades []core.Trade
-
-	switch order.OrderType {
-	case core.OrderTypeMarket:
-		trade, err := me.executeMarketOrder(order, contract, db)
-		if err != nil {
-			return nil, err
-		}
-		trades = append(trades, *trade)
-	case core.OrderTypeLimit:
-		executedTrades, err := me.executeLimitOrder(order, contract, db)
-		if err != nil {
-			return nil, err
-		}
-		trades = append(trades, executedTrades...)
-	default:
-		return nil, fmt.Errorf("unsupported order type: %s", order.OrderType)
-	}
-
-	return trades, nil
-}
-
-// executeMarketOrder executes a market order at current market price
-func (me *MatchingEngine) executeMarketOrder(order *core.Order, contract *core.Contract, db *gorm.DB) (*core.Trade, error) {
-	// Get last trade price or use default
-	lastPrice, exists := me.lastTradePrice[contract.Symbol]
-	if !exists {
-		lastPrice = 2050.0 // Default gold price
-	}
-
-	// Simulate market execution with small slippage
-	slippage := (rand.Float64() - 0.5) * 0.002 // ±0.1% slippage
-	executionPrice := lastPrice * (1 + slippage)
-
-	// Create trade
-	trade := core.Trade{
-		TradeID:    uuid.New().String(),
-		ContractID: order.ContractID,
-		Quantity:   order.Quantity,
-		Price:      executionPrice,
-		TradeTime:  time.Now(),
-	}
-
-	// Set order IDs based on side
-	if order.Side == core.OrderSideBuy {
-		trade.BuyOrderID = &order.OrderID
-	} else {
-		trade.SellOrderID = &order.OrderID
-	}
-
-	// Update order status
-	order.Status = core.OrderStatusFilled
-	order.FilledQuantity = order.Quantity
-	avgPrice := executionPrice
-	order.AvgFillPrice = &avgPrice
-
-	// Save trade to database
-	if err := db.Create(&trade).Error; err != nil {
-		return nil, fmt.Errorf("failed to create trade: %w", err)
-	}
-
-	// Update last trade price
-	me.lastTradePrice[contract.Symbol] = executionPrice
-
-	return &trade, nil
-}
-
-// executeLimitOrder executes a limit order if price conditions are met
-func (me *MatchingEngine) executeLimitOrder(order *core.Order, contract *core.Contract, db *gorm.DB) ([]core.Trade, error) {
-	if order.Price == nil {
-		return nil, fmt.Errorf("limit order must have a price")
-	}
-
-	// Get current market price
-	lastPrice, exists := me.lastTradePrice[contract.Symbol]
-	if !exists {
-		lastPrice = 2050.0
-	}
-
-	canExecute := false
-	if order.Side == core.OrderSideBuy && *order.Price >= lastPrice {
-		canExecute = true
-	} else if order.Side == core.OrderSideSell && *order.Price <= lastPrice {
-		canExecute = true
-	}
-
-	if canExecute {
-		// Execute at limit price
-		trade := core.Trade{
-			TradeID:    uuid.New().String(),
-			ContractID: order.ContractID,
-			Quantity:   order.Quantity,
-			Price:      *order.Price,
-			TradeTime:  time.Now(),
-		}
-
-		if order.Side == core.OrderSideBuy {
-			trade.BuyOrderID = &order.OrderID
-		} else {
-			trade.SellOrderID = &order.OrderID
-		}
-
-		// Update order status
-		order.Status = core.OrderStatusFilled
-		order.FilledQuantity = order.Quantity
-		order.AvgFillPrice = order.Price
-
-		// Save trade
-		if err := db.Create(&trade).Error; err != nil {
-			return nil, fmt.Errorf("failed to create trade: %w", err)
-		}
-
-		me.lastTradePrice[contract.Symbol] = *order.Price
-		return []core.Trade{trade}, nil
-	}
-
-	// Order remains pending
-	order.Status = core.OrderStatusPending
-	return []core.Trade{}, nil
-}
-
-// GetMarketDepth returns current market depth
-func (me *MatchingEngine) GetMarketDepth(symbol string) map[string]interface{} {
-	orders := me.orderBook[symbol]
-	
-	var bids, asks []core.Order
-	for _, order := range orders {
-		if order.Status == core.OrderStatusPending {
-			if order.Side == core.OrderSideBuy {
-				bids = append(bids, order)
-			} else {
-				asks = append(asks, order)
-			}
-		}
-	}
-
-	// Limit to top 10
-	if len(bids) > 10 {
-		bids = bids[:10]
-	}
-	if len(asks) > 10 {
-		asks = asks[:10]
-	}
-
-	return map[string]interface{}{
-		"bids":       bids,
-		"asks":       asks,
-		"last_price": me.lastTradePrice[symbol],
-	}
-}
-
 // OrderManager manages order lifecycle and position tracking
 type OrderManager struct {
 	matchingEngine *MatchingEngine
 	db             *gorm.DB
+
+	// hooksMu guards the four listener slices below, registered via
+	// OnOrderNew/OnOrderFilled/OnOrderCancelled/OnTrade and fired by
+	// emitOrderNew/emitOrderFilled/emitOrderCancelled/emitTrade (see
+	// hooks.go) once the corresponding DB save has already succeeded.
+	hooksMu          sync.Mutex
+	onOrderNew       []func(core.Order)
+	onOrderFilled    []func(core.Order, []core.Trade)
+	onOrderCancelled []func(core.Order)
+	onTrade          []func(core.Trade)
 }
 
 // NewOrderManager creates a new order manager
@@ -192,6 +36,34 @@ func NewOrderManager(db *gorm.DB) *OrderManager {
 	}
 }
 
+// RestoreOpenOrders rebuilds the in-memory order book and stop book from
+// orders already sitting open in storage, so a restart doesn't silently
+// drop resting liquidity or armed stops.
+func (om *OrderManager) RestoreOpenOrders() error {
+	return om.matchingEngine.RestoreFromDB(om.db)
+}
+
+// GetMarketDepth returns the matching engine's current aggregated depth
+// for symbol.
+func (om *OrderManager) GetMarketDepth(symbol string) map[string]interface{} {
+	return om.matchingEngine.GetMarketDepth(symbol)
+}
+
+// FillCh returns the matching engine's stream of every trade as it's
+// created, for event-driven strategies (e.g. grid market-making) that
+// need to react to a fill rather than poll for one.
+func (om *OrderManager) FillCh() <-chan core.Trade {
+	return om.matchingEngine.FillCh
+}
+
+// Subscribe returns a new channel streaming every trade print and
+// top-of-book refresh across all symbols, for a future websocket endpoint
+// to fan out to connected clients. Unlike FillCh, which is one shared
+// channel, each Subscribe call gets its own independent channel.
+func (om *OrderManager) Subscribe() <-chan Event {
+	return om.matchingEngine.Subscribe()
+}
+
 // SubmitOrderResult represents the result of submitting an order
 type SubmitOrderResult struct {
 	Success bool                     `json:"success"`
@@ -212,6 +84,11 @@ func (om *OrderManager) SubmitOrder(userID uint, orderRequest core.OrderCreateRe
 		}
 	}
 
+	strategy := orderRequest.Strategy
+	if strategy == "" {
+		strategy = "manual"
+	}
+
 	// Create order
 	order := core.Order{
 		OrderID:    uuid.New().String(),
@@ -222,6 +99,7 @@ func (om *OrderManager) SubmitOrder(userID uint, orderRequest core.OrderCreateRe
 		Quantity:   orderRequest.Quantity,
 		Price:      orderRequest.Price,
 		StopPrice:  orderRequest.StopPrice,
+		Strategy:   strategy,
 		Status:     core.OrderStatusPending,
 	}
 
@@ -232,6 +110,7 @@ func (om *OrderManager) SubmitOrder(userID uint, orderRequest core.OrderCreateRe
 			Error:   fmt.Sprintf("Failed to create order: %v", err),
 		}
 	}
+	om.emitOrderNew(order)
 
 	// Process order through matching engine
 	trades, err := om.matchingEngine.ProcessOrder(&order, &contract, om.db)
@@ -252,15 +131,22 @@ func (om *OrderManager) SubmitOrder(userID uint, orderRequest core.OrderCreateRe
 
 	// Update positions if order was executed
 	if len(trades) > 0 {
-		if err := om.updatePositions(userID, trades); err != nil {
+		if err := om.updatePositions(trades); err != nil {
 			return SubmitOrderResult{
 				Success: false,
 				Error:   fmt.Sprintf("Failed to update positions: %v", err),
 			}
 		}
+		om.emitOrderFilled(order, trades)
+		for _, trade := range trades {
+			om.emitTrade(trade)
+		}
 	}
 
-	// Prepare trade data for response
+	// Prepare trade data for response. PnL isn't included here: it's
+	// computed by updatePositions and written straight to the trade's DB
+	// row, after this slice was already returned by the matching engine.
+	// GetUserTrades re-reads from storage and reflects it.
 	var tradeData []map[string]interface{}
 	for _, trade := range trades {
 		tradeData = append(tradeData, map[string]interface{}{
@@ -281,8 +167,9 @@ func (om *OrderManager) SubmitOrder(userID uint, orderRequest core.OrderCreateRe
 // CancelOrder cancels an existing order
 func (om *OrderManager) CancelOrder(orderID string, userID uint) map[string]interface{} {
 	var order core.Order
-	if err := om.db.Where("order_id = ? AND user_id = ? AND status = ?", 
-		orderID, userID, core.OrderStatusPending).First(&order).Error; err != nil {
+	if err := om.db.Where("order_id = ? AND user_id = ? AND status IN ?",
+		orderID, userID, []core.OrderStatus{core.OrderStatusPending, core.OrderStatusPartiallyFilled}).
+		First(&order).Error; err != nil {
 		return map[string]interface{}{
 			"success": false,
 			"error":   "Order not found or cannot be cancelled",
@@ -296,6 +183,12 @@ func (om *OrderManager) CancelOrder(orderID string, userID uint) map[string]inte
 			"error":   fmt.Sprintf("Failed to cancel order: %v", err),
 		}
 	}
+	om.emitOrderCancelled(order)
+
+	var contract core.Contract
+	if err := om.db.First(&contract, order.ContractID).Error; err == nil {
+		om.matchingEngine.Cancel(contract.Symbol, order.OrderID)
+	}
 
 	return map[string]interface{}{
 		"success": true,
@@ -310,7 +203,7 @@ func (om *OrderManager) GetUserOrders(userID uint, limit int) ([]map[string]inte
 	if limit > 0 {
 		query = query.Limit(limit)
 	}
-	
+
 	if err := query.Find(&orders).Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch orders: %w", err)
 	}
@@ -339,16 +232,16 @@ func (om *OrderManager) GetUserOrders(userID uint, limit int) ([]map[string]inte
 // GetUserTrades returns user's trade history
 func (om *OrderManager) GetUserTrades(userID uint, limit int) ([]map[string]interface{}, error) {
 	var trades []core.Trade
-	
+
 	// Join with orders to filter by user
 	query := om.db.Joins("JOIN orders ON (trades.buy_order_id = orders.order_id OR trades.sell_order_id = orders.order_id)").
 		Where("orders.user_id = ?", userID).
 		Order("trades.trade_time desc")
-	
+
 	if limit > 0 {
 		query = query.Limit(limit)
 	}
-	
+
 	if err := query.Find(&trades).Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch trades: %w", err)
 	}
@@ -360,6 +253,8 @@ func (om *OrderManager) GetUserTrades(userID uint, limit int) ([]map[string]inte
 			"contract_id": trade.ContractID,
 			"quantity":    trade.Quantity,
 			"price":       trade.Price,
+			"pnl":         trade.PnL,
+			"strategy":    trade.Strategy,
 			"trade_time":  trade.TradeTime.Format(time.RFC3339),
 		}
 		result = append(result, tradeData)
@@ -368,6 +263,71 @@ func (om *OrderManager) GetUserTrades(userID uint, limit int) ([]map[string]inte
 	return result, nil
 }
 
+// GetUserPnLByStrategy aggregates userID's realized trade P&L between from
+// and to, grouped by the Strategy tag on whichever of their own orders
+// (buy or sell side) each trade belongs to.
+func (om *OrderManager) GetUserPnLByStrategy(userID uint, from, to time.Time) ([]map[string]interface{}, error) {
+	var orders []core.Order
+	if err := om.db.Where("user_id = ?", userID).Find(&orders).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+	strategyByOrderID := make(map[string]string, len(orders))
+	for _, order := range orders {
+		strategyByOrderID[order.OrderID] = order.Strategy
+	}
+
+	var trades []core.Trade
+	err := om.db.Joins("JOIN orders ON (trades.buy_order_id = orders.order_id OR trades.sell_order_id = orders.order_id)").
+		Where("orders.user_id = ? AND trades.trade_time BETWEEN ? AND ?", userID, from, to).
+		Find(&trades).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trades: %w", err)
+	}
+
+	totals := make(map[string]float64)
+	for _, trade := range trades {
+		if trade.PnL == nil {
+			continue
+		}
+
+		strategy := ownStrategy(trade, strategyByOrderID)
+		totals[strategy] += *trade.PnL
+	}
+
+	var result []map[string]interface{}
+	for strategy, totalPnL := range totals {
+		result = append(result, map[string]interface{}{
+			"strategy":  strategy,
+			"total_pnl": totalPnL,
+		})
+	}
+
+	return result, nil
+}
+
+// ownStrategy picks whichever side of trade (buy or sell) belongs to the
+// user whose orders are keyed in strategyByOrderID, and returns that
+// order's Strategy tag, defaulting to "manual" if it was left blank.
+func ownStrategy(trade core.Trade, strategyByOrderID map[string]string) string {
+	if trade.BuyOrderID != nil {
+		if tag, ok := strategyByOrderID[*trade.BuyOrderID]; ok {
+			if tag == "" {
+				return "manual"
+			}
+			return tag
+		}
+	}
+	if trade.SellOrderID != nil {
+		if tag, ok := strategyByOrderID[*trade.SellOrderID]; ok {
+			if tag == "" {
+				return "manual"
+			}
+			return tag
+		}
+	}
+	return "manual"
+}
+
 // GetUserPositions returns user's current positions
 func (om *OrderManager) GetUserPositions(userID uint) ([]map[string]interface{}, error) {
 	var positions []core.Position
@@ -393,77 +353,124 @@ func (om *OrderManager) GetUserPositions(userID uint) ([]map[string]interface{},
 	return result, nil
 }
 
-// updatePositions updates user positions based on executed trades
-func (om *OrderManager) updatePositions(userID uint, trades []core.Trade) error {
+// updatePositions updates the positions of every real counterparty behind
+// a batch of trades. A matched trade carries up to two order IDs (buyer
+// and seller), and now that the matching engine trades real resting
+// liquidity rather than only synthetic fills, both sides need their own
+// position adjusted — not just the user who submitted the taking order.
+func (om *OrderManager) updatePositions(trades []core.Trade) error {
 	for _, trade := range trades {
-		// Find existing position
-		var position core.Position
-		err := om.db.Where("user_id = ? AND contract_id = ?", userID, trade.ContractID).First(&position).Error
-		
-		// Determine if this is a buy or sell
-		var order core.Order
 		if trade.BuyOrderID != nil {
-			if err := om.db.Where("order_id = ?", *trade.BuyOrderID).First(&order).Error; err != nil {
-				continue
+			if err := om.applyTradeToPosition(*trade.BuyOrderID, trade, core.OrderSideBuy); err != nil {
+				return err
 			}
-		} else if trade.SellOrderID != nil {
-			if err := om.db.Where("order_id = ?", *trade.SellOrderID).First(&order).Error; err != nil {
-				continue
+		}
+		if trade.SellOrderID != nil {
+			if err := om.applyTradeToPosition(*trade.SellOrderID, trade, core.OrderSideSell); err != nil {
+				return err
 			}
-		} else {
-			continue
 		}
+	}
 
-		tradeQuantity := trade.Quantity
-		if order.Side == core.OrderSideSell {
-			tradeQuantity = -trade.Quantity
-		}
+	return nil
+}
 
-		if err == nil {
-			// Update existing position
-			oldQuantity := position.Quantity
-			oldValue := oldQuantity * position.AvgEntryPrice
-			newValue := tradeQuantity * trade.Price
+// applyTradeToPosition looks up orderID's owner and folds trade into that
+// user's position for the traded contract, signing the quantity by side.
+func (om *OrderManager) applyTradeToPosition(orderID string, trade core.Trade, side core.OrderSide) error {
+	var order core.Order
+	if err := om.db.Where("order_id = ?", orderID).First(&order).Error; err != nil {
+		return nil
+	}
+
+	tradeQuantity := trade.Quantity
+	if side == core.OrderSideSell {
+		tradeQuantity = -trade.Quantity
+	}
+
+	var position core.Position
+	err := om.db.Where("user_id = ? AND contract_id = ?", order.UserID, trade.ContractID).First(&position).Error
+	if err == nil {
+		// Realized P&L for the portion of this fill that closes existing
+		// exposure, computed against the position's AvgEntryPrice before
+		// it gets updated below. Added to whatever PnL is already recorded
+		// for this trade rather than overwriting it, since a trade whose
+		// buy and sell legs both close out an existing position (the
+		// matching engine trading real resting liquidity on both sides)
+		// calls into this function twice for the same TradeID.
+		if pnl := closingPnL(position.Quantity, position.AvgEntryPrice, tradeQuantity, trade.Price); pnl != 0 {
+			var existing core.Trade
+			if err := om.db.Where("trade_id = ?", trade.TradeID).First(&existing).Error; err != nil {
+				return fmt.Errorf("failed to load trade for P&L update: %w", err)
+			}
+			if existing.PnL != nil {
+				pnl += *existing.PnL
+			}
+			if err := om.db.Model(&core.Trade{}).Where("trade_id = ?", trade.TradeID).Update("PnL", pnl).Error; err != nil {
+				return fmt.Errorf("failed to record trade P&L: %w", err)
+			}
+		}
 
-			position.Quantity += tradeQuantity
+		// Update existing position
+		oldQuantity := position.Quantity
+		oldValue := oldQuantity * position.AvgEntryPrice
+		newValue := tradeQuantity * trade.Price
 
-			if position.Quantity != 0 {
-				position.AvgEntryPrice = (oldValue + newValue) / position.Quantity
-			} else {
-				// Position closed
-				position.RealizedPnL += oldValue + newValue
-				position.AvgEntryPrice = 0
-			}
+		position.Quantity += tradeQuantity
 
-			position.LastUpdated = time.Now()
-			if err := om.db.Save(&position).Error; err != nil {
-				return fmt.Errorf("failed to update position: %w", err)
-			}
+		if position.Quantity != 0 {
+			position.AvgEntryPrice = (oldValue + newValue) / position.Quantity
 		} else {
-			// Create new position
-			var contract core.Contract
-			if err := om.db.First(&contract, trade.ContractID).Error; err != nil {
-				continue
-			}
+			// Position closed
+			position.RealizedPnL += oldValue + newValue
+			position.AvgEntryPrice = 0
+		}
 
-			position = core.Position{
-				UserID:            userID,
-				ContractID:        trade.ContractID,
-				Quantity:          tradeQuantity,
-				AvgEntryPrice:     trade.Price,
-				MarginRequirement: contract.InitialMargin,
-				LastUpdated:       time.Now(),
-			}
+		position.LastUpdated = time.Now()
+		if err := om.db.Save(&position).Error; err != nil {
+			return fmt.Errorf("failed to update position: %w", err)
+		}
+	} else {
+		// Create new position
+		var contract core.Contract
+		if err := om.db.First(&contract, trade.ContractID).Error; err != nil {
+			return nil
+		}
 
-			if err := om.db.Create(&position).Error; err != nil {
-				return fmt.Errorf("failed to create position: %w", err)
-			}
+		position = core.Position{
+			UserID:            order.UserID,
+			ContractID:        trade.ContractID,
+			Quantity:          tradeQuantity,
+			AvgEntryPrice:     trade.Price,
+			MarginRequirement: contract.InitialMargin,
+			LastUpdated:       time.Now(),
+		}
+
+		if err := om.db.Create(&position).Error; err != nil {
+			return fmt.Errorf("failed to create position: %w", err)
 		}
 	}
 
 	return nil
 }
 
+// closingPnL returns the realized P&L contributed by a fill of tradeQty
+// at fillPrice against an existing position of positionQty at avgEntry.
+// A fill that only opens or adds to a position has no closing portion and
+// returns 0.
+func closingPnL(positionQty, avgEntry, tradeQty, fillPrice float64) float64 {
+	if positionQty == 0 || (positionQty > 0) == (tradeQty > 0) {
+		return 0
+	}
+
+	closingQty := math.Min(math.Abs(positionQty), math.Abs(tradeQty))
+	sign := 1.0
+	if positionQty < 0 {
+		sign = -1.0
+	}
+	return closingQty * (fillPrice - avgEntry) * sign
+}
+
 // UpdatePositionPnL updates unrealized P&L for all positions based on current market prices
 func (om *OrderManager) UpdatePositionPnL(currentPrices map[uint]float64) error {
 	var positions []core.Position
@@ -476,7 +483,7 @@ func (om *OrderManager) UpdatePositionPnL(currentPrices map[uint]float64) error
 			if position.Quantity != 0 {
 				position.UnrealizedPnL = (currentPrice - position.AvgEntryPrice) * position.Quantity
 				position.LastUpdated = time.Now()
-				
+
 				if err := om.db.Save(&position).Error; err != nil {
 					return fmt.Errorf("failed to update position P&L: %w", err)
 				}
@@ -485,4 +492,4 @@ func (om *OrderManager) UpdatePositionPnL(currentPrices map[uint]float64) error
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,584 @@
+package oms
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"gorm.io/gorm"
+)
+
+// fillEpsilon is the tolerance ProcessOrder uses when comparing a
+// quantity against zero, so float64 rounding doesn't leave a resting
+// order stuck at e.g. 1e-14 remaining.
+const fillEpsilon = 1e-9
+
+// symbolBook is one symbol's resting limit orders: price level buckets of
+// FIFO queues, giving price-time priority without needing a red-black
+// tree — at this simulator's scale, sorting the handful of resting price
+// levels on demand is simpler and plenty fast.
+type symbolBook struct {
+	bids map[float64][]*core.Order // buy side, best = highest price
+	asks map[float64][]*core.Order // sell side, best = lowest price
+}
+
+func newSymbolBook() *symbolBook {
+	return &symbolBook{
+		bids: make(map[float64][]*core.Order),
+		asks: make(map[float64][]*core.Order),
+	}
+}
+
+func (b *symbolBook) levels(side core.OrderSide) map[float64][]*core.Order {
+	if side == core.OrderSideSell {
+		return b.asks
+	}
+	return b.bids
+}
+
+// rest adds order to the book at its limit price. Callers must not rest
+// an order without a Price.
+func (b *symbolBook) rest(order *core.Order) {
+	levels := b.levels(order.Side)
+	levels[*order.Price] = append(levels[*order.Price], order)
+}
+
+// sortedPrices returns side's resting price levels, best first (highest
+// for bids, lowest for asks).
+func (b *symbolBook) sortedPrices(side core.OrderSide) []float64 {
+	levels := b.levels(side)
+	prices := make([]float64, 0, len(levels))
+	for price := range levels {
+		prices = append(prices, price)
+	}
+	if side == core.OrderSideBuy {
+		sort.Sort(sort.Reverse(sort.Float64Slice(prices)))
+	} else {
+		sort.Float64s(prices)
+	}
+	return prices
+}
+
+// depthLevel is one aggregated price level, for GetMarketDepth.
+type depthLevel struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// depth aggregates resting quantity by price level on side, best-first,
+// capped at limit levels.
+func (b *symbolBook) depth(side core.OrderSide, limit int) []depthLevel {
+	levels := b.levels(side)
+	prices := b.sortedPrices(side)
+	if limit > 0 && limit < len(prices) {
+		prices = prices[:limit]
+	}
+
+	result := make([]depthLevel, 0, len(prices))
+	for _, price := range prices {
+		var quantity float64
+		for _, order := range levels[price] {
+			quantity += order.Quantity - order.FilledQuantity
+		}
+		result = append(result, depthLevel{Price: price, Quantity: quantity})
+	}
+	return result
+}
+
+// applyFill records a qty-at-price execution against o, updating its
+// weighted-average fill price and status.
+func applyFill(o *core.Order, qty, price float64) {
+	previousNotional := o.FilledQuantity * derefOrZero(o.AvgFillPrice)
+	o.FilledQuantity += qty
+	avg := (previousNotional + qty*price) / o.FilledQuantity
+	o.AvgFillPrice = &avg
+
+	if o.Quantity-o.FilledQuantity <= fillEpsilon {
+		o.Status = core.OrderStatusFilled
+	} else {
+		o.Status = core.OrderStatusPartiallyFilled
+	}
+}
+
+func derefOrZero(f *float64) float64 {
+	if f == nil {
+		return 0
+	}
+	return *f
+}
+
+// MatchingEngine is a per-symbol central limit order book: it matches
+// incoming orders against resting liquidity in price-time priority,
+// rests whatever doesn't immediately fill, and promotes triggered
+// stop/stop-limit orders once a trade prints.
+type MatchingEngine struct {
+	mutex          sync.Mutex
+	books          map[string]*symbolBook // Key: contract symbol
+	lastTradePrice map[string]float64     // Key: contract symbol
+	stops          *stopBook
+
+	// FillCh streams every trade the instant it's created, letting
+	// event-driven consumers like a grid strategy react without polling
+	// GetMarketDepth. Sends are non-blocking (see publishFill): with no
+	// guarantee a subscriber is draining it, the engine must never stall
+	// order processing waiting on a send while holding mutex.
+	FillCh chan core.Trade
+
+	// subMu guards subscribers, the fan-out list for Subscribe().
+	subMu       sync.Mutex
+	subscribers []chan Event
+}
+
+// NewMatchingEngine creates a new matching engine
+func NewMatchingEngine() *MatchingEngine {
+	return &MatchingEngine{
+		books:          make(map[string]*symbolBook),
+		lastTradePrice: make(map[string]float64),
+		stops:          newStopBook(),
+		FillCh:         make(chan core.Trade, 256),
+	}
+}
+
+// publishFill sends trade to FillCh without blocking: if nothing is
+// draining it, or the buffer is momentarily full, the event is dropped
+// rather than stalling the caller, which holds me.mutex for the duration
+// of a ProcessOrder call.
+func (me *MatchingEngine) publishFill(trade core.Trade) {
+	select {
+	case me.FillCh <- trade:
+	default:
+	}
+}
+
+// EventKind identifies what an Event streamed from Subscribe carries.
+type EventKind string
+
+const (
+	EventTrade     EventKind = "trade"
+	EventBookDelta EventKind = "book_delta"
+)
+
+// Event is one item streamed from Subscribe: either a trade print or a
+// refreshed top-of-book snapshot for Symbol, tagged by Kind so a single
+// channel can carry both without a second subscription. Depth mirrors
+// GetMarketDepth's shape; it's a full snapshot rather than a true
+// incremental delta, which is enough for a websocket endpoint to just
+// re-render the book each time one arrives.
+type Event struct {
+	Kind   EventKind
+	Symbol string
+	Trade  *core.Trade
+	Depth  map[string]interface{}
+}
+
+// eventBufferSize bounds each subscriber's pending-event queue.
+const eventBufferSize = 256
+
+// Subscribe returns a channel streaming every trade print and
+// top-of-book refresh across all symbols, meant for a future websocket
+// endpoint to fan out to connected clients. Each call creates an
+// independent buffered channel with a drop-oldest overflow policy (see
+// broadcast), so one slow subscriber can never stall another or the
+// matching engine itself. The returned channel is never closed; callers
+// should select on their own cancellation signal alongside it.
+func (me *MatchingEngine) Subscribe() <-chan Event {
+	ch := make(chan Event, eventBufferSize)
+	me.subMu.Lock()
+	me.subscribers = append(me.subscribers, ch)
+	me.subMu.Unlock()
+	return ch
+}
+
+// broadcast fans event out to every subscriber without blocking: a full
+// channel has its oldest queued event dropped to make room for event,
+// rather than stalling the caller (which holds me.mutex for the duration
+// of a match call).
+func (me *MatchingEngine) broadcast(event Event) {
+	me.subMu.Lock()
+	subs := me.subscribers
+	me.subMu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- event:
+			continue
+		default:
+		}
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (me *MatchingEngine) bookFor(symbol string) *symbolBook {
+	book, ok := me.books[symbol]
+	if !ok {
+		book = newSymbolBook()
+		me.books[symbol] = book
+	}
+	return book
+}
+
+// ProcessOrder processes an order through the matching engine
+func (me *MatchingEngine) ProcessOrder(order *core.Order, contract *core.Contract, db *gorm.DB) ([]core.Trade, error) {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	switch order.OrderType {
+	case core.OrderTypeStop, core.OrderTypeStopLimit:
+		if order.StopPrice == nil {
+			return nil, fmt.Errorf("stop order must have a stop price")
+		}
+		me.stops.add(order, contract.Symbol)
+		order.Status = core.OrderStatusPending
+		return []core.Trade{}, nil
+	case core.OrderTypeMarket, core.OrderTypeLimit:
+		return me.match(order, contract, db)
+	default:
+		return nil, fmt.Errorf("unsupported order type: %s", order.OrderType)
+	}
+}
+
+// match runs order against the resting book for contract, trading
+// against the opposite side in price-time priority, then rests any
+// unfilled limit remainder (or, for a market order that found no
+// counterparty at all, falls back to a synthetic fill at the last trade
+// price — preserving this simulator's ability to demo a single-sided
+// market without needing seeded liquidity on both sides).
+func (me *MatchingEngine) match(order *core.Order, contract *core.Contract, db *gorm.DB) ([]core.Trade, error) {
+	symbol := contract.Symbol
+	book := me.bookFor(symbol)
+
+	opposite := core.OrderSideSell
+	if order.Side == core.OrderSideSell {
+		opposite = core.OrderSideBuy
+	}
+
+	var trades []core.Trade
+	for order.Quantity-order.FilledQuantity > fillEpsilon {
+		prices := book.sortedPrices(opposite)
+		if len(prices) == 0 {
+			break
+		}
+		price := prices[0]
+		if order.OrderType == core.OrderTypeLimit {
+			if order.Side == core.OrderSideBuy && *order.Price < price {
+				break
+			}
+			if order.Side == core.OrderSideSell && *order.Price > price {
+				break
+			}
+		}
+
+		levels := book.levels(opposite)
+		queue := levels[price]
+		for len(queue) > 0 && order.Quantity-order.FilledQuantity > fillEpsilon {
+			resting := queue[0]
+			fillQty := math.Min(order.Quantity-order.FilledQuantity, resting.Quantity-resting.FilledQuantity)
+
+			trade := core.Trade{
+				TradeID:    uuid.New().String(),
+				ContractID: contract.ID,
+				Quantity:   fillQty,
+				Price:      price,
+				Strategy:   order.Strategy,
+				TradeTime:  time.Now(),
+			}
+			if order.Side == core.OrderSideBuy {
+				trade.BuyOrderID = &order.OrderID
+				trade.SellOrderID = &resting.OrderID
+			} else {
+				trade.BuyOrderID = &resting.OrderID
+				trade.SellOrderID = &order.OrderID
+			}
+			if err := db.Create(&trade).Error; err != nil {
+				return trades, fmt.Errorf("failed to create trade: %w", err)
+			}
+			trades = append(trades, trade)
+			me.publishFill(trade)
+			me.broadcast(Event{Kind: EventTrade, Symbol: symbol, Trade: &trade})
+
+			applyFill(order, fillQty, price)
+			applyFill(resting, fillQty, price)
+			if err := db.Save(resting).Error; err != nil {
+				return trades, fmt.Errorf("failed to update resting order: %w", err)
+			}
+
+			me.lastTradePrice[symbol] = price
+			if resting.Quantity-resting.FilledQuantity <= fillEpsilon {
+				queue = queue[1:]
+			}
+		}
+
+		if len(queue) == 0 {
+			delete(levels, price)
+		} else {
+			levels[price] = queue
+		}
+	}
+
+	remaining := order.Quantity - order.FilledQuantity
+	switch {
+	case remaining <= fillEpsilon:
+		order.Status = core.OrderStatusFilled
+	case order.OrderType == core.OrderTypeLimit:
+		order.Status = core.OrderStatusPartiallyFilled
+		if order.FilledQuantity == 0 {
+			order.Status = core.OrderStatusPending
+		}
+		book.rest(order)
+	case order.FilledQuantity == 0:
+		// Market order with no resting counterparty at all: fall back to
+		// a synthetic fill so the simulator stays usable single-sided.
+		trade, err := me.syntheticFill(order, contract, db)
+		if err != nil {
+			return trades, err
+		}
+		trades = append(trades, *trade)
+	default:
+		// Market order partially filled against real liquidity, then ran
+		// out of book depth: treat the remainder as cancelled (IOC).
+		order.Status = core.OrderStatusPartiallyFilled
+	}
+
+	stopTrades, err := me.promoteTriggeredStops(symbol, db)
+	if err != nil {
+		return trades, err
+	}
+	trades = append(trades, stopTrades...)
+
+	me.broadcast(Event{Kind: EventBookDelta, Symbol: symbol, Depth: me.marketDepthLocked(symbol)})
+
+	return trades, nil
+}
+
+// syntheticFill executes order at the last known trade price (or a
+// default) with a small random slippage, the simulator's original
+// fallback for a market order with no resting counterparty.
+func (me *MatchingEngine) syntheticFill(order *core.Order, contract *core.Contract, db *gorm.DB) (*core.Trade, error) {
+	lastPrice, exists := me.lastTradePrice[contract.Symbol]
+	if !exists {
+		lastPrice = 2050.0 // Default gold price
+	}
+
+	slippage := (rand.Float64() - 0.5) * 0.002 // ±0.1% slippage
+	executionPrice := lastPrice * (1 + slippage)
+
+	trade := core.Trade{
+		TradeID:    uuid.New().String(),
+		ContractID: order.ContractID,
+		Quantity:   order.Quantity,
+		Price:      executionPrice,
+		Strategy:   order.Strategy,
+		TradeTime:  time.Now(),
+	}
+	if order.Side == core.OrderSideBuy {
+		trade.BuyOrderID = &order.OrderID
+	} else {
+		trade.SellOrderID = &order.OrderID
+	}
+
+	if err := db.Create(&trade).Error; err != nil {
+		return nil, fmt.Errorf("failed to create trade: %w", err)
+	}
+
+	applyFill(order, order.Quantity, executionPrice)
+	me.lastTradePrice[contract.Symbol] = executionPrice
+	me.publishFill(trade)
+	me.broadcast(Event{Kind: EventTrade, Symbol: contract.Symbol, Trade: &trade})
+
+	return &trade, nil
+}
+
+// promoteTriggeredStops checks symbol's stop book against its last trade
+// price and runs every triggered order through match, looping until a
+// round produces no new triggers (a promoted order's own fill can, in
+// turn, trigger another stop at the same price).
+func (me *MatchingEngine) promoteTriggeredStops(symbol string, db *gorm.DB) ([]core.Trade, error) {
+	var trades []core.Trade
+	for {
+		lastPrice, ok := me.lastTradePrice[symbol]
+		if !ok {
+			return trades, nil
+		}
+
+		triggered := me.stops.triggered(symbol, lastPrice)
+		if len(triggered) == 0 {
+			return trades, nil
+		}
+
+		for _, stopOrder := range triggered {
+			if stopOrder.OrderType == core.OrderTypeStop {
+				stopOrder.OrderType = core.OrderTypeMarket
+			} else {
+				stopOrder.OrderType = core.OrderTypeLimit
+			}
+
+			var contract core.Contract
+			if err := db.First(&contract, stopOrder.ContractID).Error; err != nil {
+				return trades, fmt.Errorf("failed to look up contract for triggered stop: %w", err)
+			}
+
+			promoted, err := me.match(stopOrder, &contract, db)
+			if err != nil {
+				return trades, err
+			}
+			trades = append(trades, promoted...)
+		}
+	}
+}
+
+// Cancel removes orderID from symbol's resting book or stop book, if
+// it's there, so a cancelled order can never go on to match. It's safe to
+// call for an order the engine never rested (e.g. one that filled
+// immediately or was rejected).
+func (me *MatchingEngine) Cancel(symbol, orderID string) {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if book, ok := me.books[symbol]; ok {
+		book.remove(orderID)
+	}
+	me.stops.remove(symbol, orderID)
+}
+
+// remove deletes orderID from whichever side/price-level it rests at.
+func (b *symbolBook) remove(orderID string) {
+	for _, levels := range []map[float64][]*core.Order{b.bids, b.asks} {
+		for price, queue := range levels {
+			for i, order := range queue {
+				if order.OrderID == orderID {
+					levels[price] = append(queue[:i], queue[i+1:]...)
+					if len(levels[price]) == 0 {
+						delete(levels, price)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// remove deletes orderID from symbol's pending stop list, if present.
+func (sb *stopBook) remove(symbol, orderID string) {
+	sb.mutex.Lock()
+	defer sb.mutex.Unlock()
+
+	orders := sb.pending[symbol]
+	for i, order := range orders {
+		if order.OrderID == orderID {
+			sb.pending[symbol] = append(orders[:i], orders[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetMarketDepth returns current market depth: the top 10 aggregated
+// resting price levels on each side, plus the last trade price.
+func (me *MatchingEngine) GetMarketDepth(symbol string) map[string]interface{} {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	return me.marketDepthLocked(symbol)
+}
+
+// marketDepthLocked is GetMarketDepth's body, factored out so match can
+// build the same snapshot for a book_delta broadcast without re-entering
+// me.mutex, which it already holds. Callers must hold me.mutex.
+func (me *MatchingEngine) marketDepthLocked(symbol string) map[string]interface{} {
+	book := me.bookFor(symbol)
+	return map[string]interface{}{
+		"bids":       book.depth(core.OrderSideBuy, 10),
+		"asks":       book.depth(core.OrderSideSell, 10),
+		"last_price": me.lastTradePrice[symbol],
+	}
+}
+
+// RestoreFromDB rebuilds the in-memory book from pending/partially-filled
+// orders already in storage, so a restart doesn't silently drop resting
+// liquidity or armed stop orders.
+func (me *MatchingEngine) RestoreFromDB(db *gorm.DB) error {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	var orders []core.Order
+	err := db.Where("status IN ?", []core.OrderStatus{core.OrderStatusPending, core.OrderStatusPartiallyFilled}).
+		Order("created_at ASC").
+		Find(&orders).Error
+	if err != nil {
+		return fmt.Errorf("failed to load open orders: %w", err)
+	}
+
+	for i := range orders {
+		order := &orders[i]
+
+		var contract core.Contract
+		if err := db.First(&contract, order.ContractID).Error; err != nil {
+			continue
+		}
+
+		switch order.OrderType {
+		case core.OrderTypeLimit:
+			if order.Price != nil {
+				me.bookFor(contract.Symbol).rest(order)
+			}
+		case core.OrderTypeStop, core.OrderTypeStopLimit:
+			me.stops.add(order, contract.Symbol)
+		}
+	}
+	return nil
+}
+
+// stopBook holds stop/stop-limit orders waiting for their trigger price
+// to trade, keyed by symbol.
+type stopBook struct {
+	mutex   sync.Mutex
+	pending map[string][]*core.Order
+}
+
+func newStopBook() *stopBook {
+	return &stopBook{pending: make(map[string][]*core.Order)}
+}
+
+func (sb *stopBook) add(order *core.Order, symbol string) {
+	sb.mutex.Lock()
+	defer sb.mutex.Unlock()
+	sb.pending[symbol] = append(sb.pending[symbol], order)
+}
+
+// triggered removes and returns every order on symbol whose StopPrice has
+// traded through: a buy stop triggers when the tape trades at or above
+// it, a sell stop when it trades at or below.
+func (sb *stopBook) triggered(symbol string, lastPrice float64) []*core.Order {
+	sb.mutex.Lock()
+	defer sb.mutex.Unlock()
+
+	orders := sb.pending[symbol]
+	remaining := orders[:0]
+	var fired []*core.Order
+	for _, order := range orders {
+		triggered := false
+		if order.Side == core.OrderSideBuy && lastPrice >= *order.StopPrice {
+			triggered = true
+		} else if order.Side == core.OrderSideSell && lastPrice <= *order.StopPrice {
+			triggered = true
+		}
+
+		if triggered {
+			fired = append(fired, order)
+		} else {
+			remaining = append(remaining, order)
+		}
+	}
+	sb.pending[symbol] = remaining
+	return fired
+}
@@ -0,0 +1,140 @@
+// Package persistence provides a generic key/value Store for AI-assistant
+// state that doesn't fit the domain-specific storage.JSONStorage/
+// storage.DatabaseManager tables - rolling indicator windows, analysis
+// history, and per-user chat context - plus reflection helpers that save
+// and load an entire tagged struct in one call.
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store persists arbitrary JSON-serializable values by key. Load leaves dest
+// untouched and returns ErrNotFound when key has never been saved, unlike
+// communication.SessionStore's Load, which returns fresh default state for
+// an unseen session without an error.
+type Store interface {
+	Save(key string, value interface{}) error
+	Load(key string, dest interface{}) error
+	Delete(key string) error
+}
+
+// ErrNotFound is returned by Load when key has never been saved.
+var ErrNotFound = fmt.Errorf("persistence: key not found")
+
+// JSONStore persists one JSON file per key under a configurable directory,
+// following the same one-file-per-key layout as
+// communication.FileSessionStore.
+type JSONStore struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewJSONStore creates a JSONStore rooted at dir, creating it if necessary.
+func NewJSONStore(dir string) (*JSONStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create persistence store directory: %w", err)
+	}
+	return &JSONStore{dir: dir}, nil
+}
+
+// Save marshals value as JSON and writes it to key's file.
+func (js *JSONStore) Save(key string, value interface{}) error {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal value for key %q: %w", key, err)
+	}
+	return os.WriteFile(js.path(key), data, 0644)
+}
+
+// Load reads key's file and unmarshals it into dest. It returns ErrNotFound
+// if key has never been saved.
+func (js *JSONStore) Load(key string, dest interface{}) error {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+
+	data, err := os.ReadFile(js.path(key))
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to read value for key %q: %w", key, err)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("failed to parse value for key %q: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes key's file. Deleting an unseen key is not an error.
+func (js *JSONStore) Delete(key string) error {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+
+	err := os.Remove(js.path(key))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete value for key %q: %w", key, err)
+	}
+	return nil
+}
+
+func (js *JSONStore) path(key string) string {
+	return filepath.Join(js.dir, key+".json")
+}
+
+// RedisStore persists values in Redis under a configurable key prefix.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore creates a RedisStore against addr (host:port) using database
+// db, prefixing every key with prefix.
+func NewRedisStore(addr string, db int, prefix string) *RedisStore {
+	return &RedisStore{
+		client: redis.NewClient(&redis.Options{Addr: addr, DB: db}),
+		prefix: prefix,
+	}
+}
+
+// Save marshals value as JSON and writes it to key.
+func (rs *RedisStore) Save(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value for key %q: %w", key, err)
+	}
+	return rs.client.Set(context.Background(), rs.prefixed(key), data, 0).Err()
+}
+
+// Load reads key and unmarshals it into dest. It returns ErrNotFound if key
+// has never been saved.
+func (rs *RedisStore) Load(key string, dest interface{}) error {
+	raw, err := rs.client.Get(context.Background(), rs.prefixed(key)).Bytes()
+	if err == redis.Nil {
+		return ErrNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to load value for key %q from redis: %w", key, err)
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		return fmt.Errorf("failed to parse value for key %q: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes key. Deleting an unseen key is not an error.
+func (rs *RedisStore) Delete(key string) error {
+	return rs.client.Del(context.Background(), rs.prefixed(key)).Err()
+}
+
+func (rs *RedisStore) prefixed(key string) string {
+	return rs.prefix + ":" + key
+}
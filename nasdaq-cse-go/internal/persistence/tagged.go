@@ -0,0 +1,55 @@
+package persistence
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SaveTagged saves every field of the struct pointed to by v that carries a
+// `persistence:"<key>"` tag, one Store.Save call per field, under that key.
+func SaveTagged(store Store, v interface{}) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("persistence: SaveTagged requires a pointer to a struct, got %T", v)
+	}
+	val = val.Elem()
+	typ := val.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		key := typ.Field(i).Tag.Get("persistence")
+		if key == "" {
+			continue
+		}
+		if err := store.Save(key, val.Field(i).Interface()); err != nil {
+			return fmt.Errorf("persistence: failed to save field %q under key %q: %w", typ.Field(i).Name, key, err)
+		}
+	}
+	return nil
+}
+
+// LoadTagged loads every field of the struct pointed to by v that carries a
+// `persistence:"<key>"` tag from Store, leaving a field at its current
+// (zero) value when its key has never been saved.
+func LoadTagged(store Store, v interface{}) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("persistence: LoadTagged requires a pointer to a struct, got %T", v)
+	}
+	val = val.Elem()
+	typ := val.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		key := typ.Field(i).Tag.Get("persistence")
+		if key == "" {
+			continue
+		}
+		field := val.Field(i)
+		if err := store.Load(key, field.Addr().Interface()); err != nil {
+			if err == ErrNotFound {
+				continue
+			}
+			return fmt.Errorf("persistence: failed to load field %q under key %q: %w", typ.Field(i).Name, key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+// Package circuitbreaker halts AI-suggested trading per user once realized
+// losses breach one of three guards: cumulative loss over a rolling window,
+// a losing streak, or drawdown from peak equity. It plays the same role as
+// aiassistant.CircuitBreaker (which trips the whole bot's suggestions on
+// one combined PnL series) but tracks every user separately, keyed by the
+// core.Trade stream rather than a single Observe(combinedPnL) call, and
+// optionally persists trip state so a restart doesn't clear it.
+package circuitbreaker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/persistence"
+)
+
+// Config parameterizes CircuitBreaker's three trip conditions and its
+// cooldown.
+type Config struct {
+	// Window bounds how far back RecordTrade looks when summing realized
+	// PnL for the cumulative-loss guard.
+	Window time.Duration
+	// MaxCumulativeLoss trips the breaker once realized losses within
+	// Window exceed this (positive) amount.
+	MaxCumulativeLoss float64
+	// MaxConsecutiveLosses trips the breaker once that many losing trades
+	// have printed in a row.
+	MaxConsecutiveLosses int
+	// MaxDrawdownPercent trips the breaker once peak-to-trough drawdown on
+	// cumulative realized PnL exceeds this fraction of accountBalance
+	// passed to RecordTrade.
+	MaxDrawdownPercent float64
+	// Cooldown is how long the breaker stays tripped before it auto-resets.
+	Cooldown time.Duration
+}
+
+// DefaultConfig returns a 24-hour window, a $5,000 cumulative loss cap, a
+// 5-consecutive-loss cap, 20% max drawdown, and a 1-hour cooldown.
+func DefaultConfig() Config {
+	return Config{
+		Window:               24 * time.Hour,
+		MaxCumulativeLoss:    5000.0,
+		MaxConsecutiveLosses: 5,
+		MaxDrawdownPercent:   0.20,
+		Cooldown:             time.Hour,
+	}
+}
+
+// lossSample is one realized-PnL trade folded into a user's rolling window.
+type lossSample struct {
+	Time time.Time `json:"time"`
+	PnL  float64   `json:"pnl"`
+}
+
+// userState is one user's trip tracking, persisted under a per-user key
+// when the breaker has a Store configured.
+type userState struct {
+	Samples           []lossSample `json:"samples"`
+	ConsecutiveLosses int          `json:"consecutive_losses"`
+	CumulativePnL     float64      `json:"cumulative_pnl"`
+	PeakPnL           float64      `json:"peak_pnl"`
+	HavePeak          bool         `json:"have_peak"`
+	Tripped           bool         `json:"tripped"`
+	Reason            string       `json:"reason"`
+	CooldownUntil     time.Time    `json:"cooldown_until"`
+}
+
+// CircuitBreaker tracks realized-PnL trip conditions per user.
+type CircuitBreaker struct {
+	cfg Config
+
+	mu    sync.Mutex
+	users map[uint]*userState
+
+	store     persistence.Store
+	keyPrefix string
+}
+
+// NewCircuitBreaker creates a CircuitBreaker with no persistence - trip
+// state is lost on restart.
+func NewCircuitBreaker(cfg Config) *CircuitBreaker {
+	return NewCircuitBreakerWithStore(cfg, nil, "")
+}
+
+// NewCircuitBreakerWithStore creates a CircuitBreaker that persists each
+// user's trip state to store under "<keyPrefix>_<userID>", so a restart
+// doesn't clear an open breaker. Pass a nil store to keep state in-memory
+// only, as NewCircuitBreaker does.
+func NewCircuitBreakerWithStore(cfg Config, store persistence.Store, keyPrefix string) *CircuitBreaker {
+	return &CircuitBreaker{
+		cfg:       cfg,
+		users:     make(map[uint]*userState),
+		store:     store,
+		keyPrefix: keyPrefix,
+	}
+}
+
+// stateLocked returns userID's state, loading it from store on first access
+// if one is configured. Callers must hold cb.mu.
+func (cb *CircuitBreaker) stateLocked(userID uint) *userState {
+	if state, ok := cb.users[userID]; ok {
+		return state
+	}
+
+	state := &userState{}
+	if cb.store != nil {
+		_ = cb.store.Load(cb.key(userID), state)
+	}
+	cb.users[userID] = state
+	return state
+}
+
+func (cb *CircuitBreaker) key(userID uint) string {
+	return fmt.Sprintf("%s_%d", cb.keyPrefix, userID)
+}
+
+func (cb *CircuitBreaker) persistLocked(userID uint, state *userState) {
+	if cb.store == nil {
+		return
+	}
+	_ = cb.store.Save(cb.key(userID), state)
+}
+
+// RecordTrade folds trade's realized PnL into userID's rolling window,
+// consecutive-loss streak, and drawdown tracking, tripping the breaker if
+// any of Config's three guards is breached. A trade with no PnL (still
+// open) is ignored.
+func (cb *CircuitBreaker) RecordTrade(userID uint, trade core.Trade, accountBalance float64) {
+	if trade.PnL == nil {
+		return
+	}
+	pnl := *trade.PnL
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	state := cb.stateLocked(userID)
+	if state.Tripped && !cb.cooldownElapsedLocked(state) {
+		return
+	}
+	if state.Tripped {
+		cb.resetLocked(state)
+	}
+
+	tradeTime := trade.TradeTime
+	if tradeTime.IsZero() {
+		tradeTime = time.Now()
+	}
+
+	state.Samples = append(state.Samples, lossSample{Time: tradeTime, PnL: pnl})
+	state.Samples = trimToWindowLocked(state.Samples, tradeTime, cb.cfg.Window)
+
+	state.CumulativePnL = 0
+	for _, sample := range state.Samples {
+		state.CumulativePnL += sample.PnL
+	}
+
+	if pnl < 0 {
+		state.ConsecutiveLosses++
+	} else {
+		state.ConsecutiveLosses = 0
+	}
+
+	if !state.HavePeak || state.CumulativePnL > state.PeakPnL {
+		state.PeakPnL = state.CumulativePnL
+		state.HavePeak = true
+	}
+
+	switch {
+	case -state.CumulativePnL >= cb.cfg.MaxCumulativeLoss:
+		cb.tripLocked(state, fmt.Sprintf("cumulative loss of %.2f over the last %s breached the %.2f limit", -state.CumulativePnL, cb.cfg.Window, cb.cfg.MaxCumulativeLoss))
+	case state.ConsecutiveLosses >= cb.cfg.MaxConsecutiveLosses:
+		cb.tripLocked(state, fmt.Sprintf("%d consecutive losing trades", state.ConsecutiveLosses))
+	case accountBalance > 0 && (state.PeakPnL-state.CumulativePnL)/accountBalance >= cb.cfg.MaxDrawdownPercent:
+		drawdown := (state.PeakPnL - state.CumulativePnL) / accountBalance
+		cb.tripLocked(state, fmt.Sprintf("drawdown of %.1f%% breached the %.1f%% threshold", drawdown*100, cb.cfg.MaxDrawdownPercent*100))
+	}
+
+	cb.persistLocked(userID, state)
+}
+
+// trimToWindowLocked drops samples older than window relative to now.
+func trimToWindowLocked(samples []lossSample, now time.Time, window time.Duration) []lossSample {
+	if window <= 0 {
+		return samples
+	}
+	cutoff := now.Add(-window)
+	trimmed := samples[:0]
+	for _, sample := range samples {
+		if sample.Time.After(cutoff) {
+			trimmed = append(trimmed, sample)
+		}
+	}
+	return trimmed
+}
+
+// tripLocked opens the breaker for Config.Cooldown. Callers must hold cb.mu.
+func (cb *CircuitBreaker) tripLocked(state *userState, reason string) {
+	state.Tripped = true
+	state.Reason = reason
+	state.CooldownUntil = time.Now().Add(cb.cfg.Cooldown)
+}
+
+// resetLocked clears a user's trip state and loss tracking. Callers must
+// hold cb.mu.
+func (cb *CircuitBreaker) resetLocked(state *userState) {
+	state.Tripped = false
+	state.Reason = ""
+	state.CooldownUntil = time.Time{}
+	state.Samples = nil
+	state.ConsecutiveLosses = 0
+	state.CumulativePnL = 0
+	state.PeakPnL = 0
+	state.HavePeak = false
+}
+
+func (cb *CircuitBreaker) cooldownElapsedLocked(state *userState) bool {
+	return !time.Now().Before(state.CooldownUntil)
+}
+
+// CanTrade reports whether userID's breaker is closed. A tripped breaker
+// whose cooldown has elapsed reports closed (true) without requiring a
+// further RecordTrade call. When false, reason explains why.
+func (cb *CircuitBreaker) CanTrade(userID uint) (allowed bool, reason string) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	state := cb.stateLocked(userID)
+	if state.Tripped && !cb.cooldownElapsedLocked(state) {
+		return false, state.Reason
+	}
+	return true, ""
+}
+
+// Reset manually closes userID's breaker and clears its loss tracking,
+// regardless of cooldown.
+func (cb *CircuitBreaker) Reset(userID uint) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	state := cb.stateLocked(userID)
+	cb.resetLocked(state)
+	cb.persistLocked(userID, state)
+}
@@ -0,0 +1,163 @@
+package rms
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+)
+
+// Circuit breaker states, mirroring the closed/open/half-open state machine
+// the AI-gateway client stub (ai-gateway/examples/client.go) reports via
+// HealthResponse.CircuitState.
+const (
+	CircuitStateClosed   = "closed"
+	CircuitStateOpen     = "open"
+	CircuitStateHalfOpen = "half-open"
+)
+
+// CircuitBreakerConfig configures RiskManager's EMA-based PnL circuit
+// breaker.
+type CircuitBreakerConfig struct {
+	// EMAInterval is the bucket width: at most one PnL sample feeds the
+	// EMA per interval, no matter how often EvaluateCircuitBreaker runs.
+	EMAInterval time.Duration
+	// EMAWindow derives the smoothing factor α = 2/(EMAWindow+1).
+	EMAWindow int
+	// LossThreshold opens the circuit once EMA_t <= LossThreshold.
+	LossThreshold float64
+	// Cooldown is how long the circuit stays open before moving to
+	// half-open and allowing a single probe order.
+	Cooldown time.Duration
+}
+
+// DefaultCircuitBreakerConfig returns a 1-minute bucket, 10-bucket EMA
+// window, a $-5000 combined PnL loss threshold, and a 5-minute cooldown.
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		EMAInterval:   time.Minute,
+		EMAWindow:     10,
+		LossThreshold: -5000,
+		Cooldown:      5 * time.Minute,
+	}
+}
+
+// SetCircuitBreakerConfig overrides the default circuit-breaker
+// configuration.
+func (rm *RiskManager) SetCircuitBreakerConfig(cfg CircuitBreakerConfig) {
+	rm.circuitBreakerCfg = cfg
+}
+
+// RiskAlerts returns every RiskAlert accumulated so far, including
+// CIRCUIT_BREAKER alerts emitted by EvaluateCircuitBreaker transitions.
+func (rm *RiskManager) RiskAlerts() []RiskAlert {
+	return rm.riskAlerts
+}
+
+// EvaluateCircuitBreaker folds the user's current combined realized +
+// unrealized PnL into a time-bucketed EMA and returns the resulting
+// circuit state (closed, half-open, or open) and a human-readable reason.
+// State is persisted to core.CircuitBreakerState so a restart resumes an
+// open circuit instead of forgetting it.
+func (rm *RiskManager) EvaluateCircuitBreaker(userID uint) (string, string) {
+	cfg := rm.circuitBreakerCfg
+
+	var state core.CircuitBreakerState
+	if err := rm.db.Where("user_id = ?", userID).First(&state).Error; err != nil {
+		state = core.CircuitBreakerState{UserID: userID, State: CircuitStateClosed}
+	}
+
+	now := time.Now()
+	if now.Sub(state.LastBucketStart) >= cfg.EMAInterval {
+		pnl := rm.combinedPnL(userID)
+		alpha := 2.0 / (float64(cfg.EMAWindow) + 1)
+		if state.LastBucketStart.IsZero() {
+			state.EMA = pnl
+		} else {
+			state.EMA = alpha*pnl + (1-alpha)*state.EMA
+		}
+		state.LastBucketStart = now
+	}
+
+	reason := "circuit breaker closed"
+	switch state.State {
+	case CircuitStateOpen:
+		if state.OpenedAt != nil && now.Sub(*state.OpenedAt) >= cfg.Cooldown {
+			state.State = CircuitStateHalfOpen
+			state.ProbeConsumed = false
+			state.ProbeBaselineEMA = state.EMA
+			rm.emitCircuitBreakerAlert(userID, "Circuit breaker cooldown elapsed; allowing one probe order")
+			reason = "circuit breaker half-open: probe order allowed"
+		} else {
+			reason = "circuit breaker open"
+		}
+
+	case CircuitStateHalfOpen:
+		if state.ProbeConsumed {
+			if state.EMA >= state.ProbeBaselineEMA {
+				state.State = CircuitStateClosed
+				rm.emitCircuitBreakerAlert(userID, "Probe order didn't worsen PnL EMA; circuit breaker closed")
+				reason = "circuit breaker closed"
+			} else {
+				state.State = CircuitStateOpen
+				state.OpenedAt = &now
+				rm.emitCircuitBreakerAlert(userID, "Probe order worsened PnL EMA; circuit breaker re-opened")
+				reason = "circuit breaker open"
+			}
+		} else {
+			reason = "circuit breaker half-open: probe order allowed"
+		}
+
+	default: // closed
+		if state.EMA <= cfg.LossThreshold {
+			state.State = CircuitStateOpen
+			state.OpenedAt = &now
+			rm.emitCircuitBreakerAlert(userID, "PnL EMA breached loss threshold")
+			reason = "circuit breaker open"
+		}
+	}
+
+	rm.db.Save(&state)
+	return state.State, reason
+}
+
+// consumeProbeOrder marks userID's half-open probe order as used, returning
+// true the first time it's called while half-open and false on every call
+// after (so CheckPreTradeRisk lets exactly one order through per half-open
+// period).
+func (rm *RiskManager) consumeProbeOrder(userID uint) bool {
+	var state core.CircuitBreakerState
+	if err := rm.db.Where("user_id = ?", userID).First(&state).Error; err != nil {
+		return false
+	}
+	if state.State != CircuitStateHalfOpen || state.ProbeConsumed {
+		return false
+	}
+	state.ProbeConsumed = true
+	rm.db.Save(&state)
+	return true
+}
+
+// combinedPnL sums realized + unrealized PnL across userID's positions,
+// the sample EvaluateCircuitBreaker folds into its EMA each bucket.
+func (rm *RiskManager) combinedPnL(userID uint) float64 {
+	var positions []core.Position
+	rm.db.Where("user_id = ?", userID).Find(&positions)
+
+	total := 0.0
+	for _, pos := range positions {
+		total += pos.UnrealizedPnL + pos.RealizedPnL
+	}
+	return total
+}
+
+// emitCircuitBreakerAlert records a CIRCUIT_BREAKER RiskAlert for a state
+// transition.
+func (rm *RiskManager) emitCircuitBreakerAlert(userID uint, message string) {
+	rm.riskAlerts = append(rm.riskAlerts, RiskAlert{
+		Type:           "CIRCUIT_BREAKER",
+		Severity:       "HIGH",
+		Message:        fmt.Sprintf("User %d: %s", userID, message),
+		Recommendation: "Review recent trades before placing new orders",
+	})
+}
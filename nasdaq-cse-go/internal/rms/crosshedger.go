@@ -0,0 +1,175 @@
+package rms
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"gorm.io/gorm"
+)
+
+// CrossHedgeConfig configures CrossHedger's trigger and sizing.
+type CrossHedgeConfig struct {
+	TriggerUtilization float64
+	HedgeVenue         string
+	MaxHedgeNotional   float64
+	HedgeRatio         float64
+}
+
+// HedgeExecutor is the pluggable seam CrossHedger uses to place the hedging
+// order on the secondary venue.
+type HedgeExecutor interface {
+	SubmitHedgeOrder(venue, contractSymbol string, side core.OrderSide, quantity, price float64) error
+}
+
+// CrossHedger hedges the riskiest position on a secondary venue once
+// MarginStatus.MarginUtilization crosses TriggerUtilization, instead of
+// force-liquidating on the primary venue.
+type CrossHedger struct {
+	db          *gorm.DB
+	riskManager *RiskManager
+	cfg         CrossHedgeConfig
+	executor    HedgeExecutor
+
+	mu      sync.Mutex
+	covered map[string]bool // userID:contractID already hedged on this tick cycle
+}
+
+// NewCrossHedger creates a new cross-exchange hedger.
+func NewCrossHedger(db *gorm.DB, riskManager *RiskManager, cfg CrossHedgeConfig, executor HedgeExecutor) *CrossHedger {
+	return &CrossHedger{
+		db:          db,
+		riskManager: riskManager,
+		cfg:         cfg,
+		executor:    executor,
+		covered:     make(map[string]bool),
+	}
+}
+
+// MonitorTick evaluates userID's margin utilization and, if it has crossed
+// TriggerUtilization, hedges the position with the largest notional
+// exposure. It returns nil when no hedge was opened (utilization below
+// trigger, no positions, or the riskiest position is already covered).
+func (ch *CrossHedger) MonitorTick(userID uint, currentPrices map[uint]float64) (*core.HedgeLeg, error) {
+	status := ch.riskManager.MonitorMarginRequirements(userID, currentPrices)
+	if status.MarginUtilization < ch.cfg.TriggerUtilization {
+		return nil, nil
+	}
+
+	var positions []core.Position
+	ch.db.Where("user_id = ?", userID).Find(&positions)
+	if len(positions) == 0 {
+		return nil, nil
+	}
+
+	var riskiest *core.Position
+	riskiestNotional := 0.0
+	for i := range positions {
+		pos := &positions[i]
+		price := pos.AvgEntryPrice
+		if p, ok := currentPrices[pos.ContractID]; ok {
+			price = p
+		}
+		notional := math.Abs(pos.Quantity * price)
+		if notional > riskiestNotional {
+			riskiestNotional = notional
+			riskiest = pos
+		}
+	}
+	if riskiest == nil {
+		return nil, nil
+	}
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	key := coveredKey(userID, riskiest.ContractID)
+	if ch.covered[key] {
+		return nil, nil
+	}
+
+	var contract core.Contract
+	if err := ch.db.First(&contract, riskiest.ContractID).Error; err != nil {
+		return nil, fmt.Errorf("contract not found: %w", err)
+	}
+
+	price := riskiest.AvgEntryPrice
+	if p, ok := currentPrices[riskiest.ContractID]; ok {
+		price = p
+	}
+
+	hedgeQty := ch.cfg.HedgeRatio * math.Abs(riskiest.Quantity)
+	if price > 0 && hedgeQty*price > ch.cfg.MaxHedgeNotional {
+		hedgeQty = ch.cfg.MaxHedgeNotional / price
+	}
+	if hedgeQty <= 0 {
+		return nil, nil
+	}
+
+	// Hedge offsets the primary position's direction: long primary exposure
+	// is hedged with a sell on the secondary venue, and vice versa.
+	side := core.OrderSideSell
+	if riskiest.Quantity < 0 {
+		side = core.OrderSideBuy
+	}
+
+	if err := ch.executor.SubmitHedgeOrder(ch.cfg.HedgeVenue, contract.Symbol, side, hedgeQty, price); err != nil {
+		return nil, fmt.Errorf("failed to submit hedge order: %w", err)
+	}
+
+	leg := core.HedgeLeg{
+		UserID:     userID,
+		ContractID: riskiest.ContractID,
+		Venue:      ch.cfg.HedgeVenue,
+		Quantity:   hedgeQty,
+		EntryPrice: price,
+		OpenedAt:   time.Now(),
+	}
+	if err := ch.db.Create(&leg).Error; err != nil {
+		return nil, fmt.Errorf("failed to persist hedge leg: %w", err)
+	}
+	ch.covered[key] = true
+
+	return &leg, nil
+}
+
+// UnwindHedge closes a previously opened hedge leg once utilization has
+// normalized, clearing it for re-hedging on a future MonitorTick.
+func (ch *CrossHedger) UnwindHedge(userID, legID uint) error {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	var leg core.HedgeLeg
+	if err := ch.db.First(&leg, legID).Error; err != nil {
+		return fmt.Errorf("hedge leg not found: %w", err)
+	}
+	if leg.UserID != userID {
+		return fmt.Errorf("hedge leg %d does not belong to user %d", legID, userID)
+	}
+	if leg.ClosedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	leg.ClosedAt = &now
+	if err := ch.db.Save(&leg).Error; err != nil {
+		return err
+	}
+	delete(ch.covered, coveredKey(userID, leg.ContractID))
+	return nil
+}
+
+// OpenHedges returns userID's currently open hedge legs.
+func (ch *CrossHedger) OpenHedges(userID uint) ([]core.HedgeLeg, error) {
+	var legs []core.HedgeLeg
+	err := ch.db.Where("user_id = ? AND closed_at IS NULL", userID).Find(&legs).Error
+	return legs, err
+}
+
+// coveredKey identifies a user/contract pair for CrossHedger's
+// already-covered guard.
+func coveredKey(userID, contractID uint) string {
+	return fmt.Sprintf("%d:%d", userID, contractID)
+}
@@ -59,37 +59,84 @@ type VaRMetrics struct {
 	ConfidenceLevel   float64 `json:"confidence_level"`
 	TimeHorizonDays   int     `json:"time_horizon_days"`
 	TotalExposure     float64 `json:"total_exposure"`
+	// Degraded is true when HistoricalSimulationVaR fell back to
+	// ParametricVaR because the price history was too short.
+	Degraded bool `json:"degraded"`
 }
 
 // MarginStatus contains margin monitoring information
 type MarginStatus struct {
-	MarginAdequate       bool    `json:"margin_adequate"`
-	MarginCall           bool    `json:"margin_call"`
-	ForceLiquidation     bool    `json:"force_liquidation"`
-	MarginUtilization    float64 `json:"margin_utilization"`
-	TotalMarginRequired  float64 `json:"total_margin_required"`
-	AvailableMargin      float64 `json:"available_margin"`
-	AccountEquity        float64 `json:"account_equity"`
+	MarginAdequate      bool    `json:"margin_adequate"`
+	MarginCall          bool    `json:"margin_call"`
+	ForceLiquidation    bool    `json:"force_liquidation"`
+	MarginUtilization   float64 `json:"margin_utilization"`
+	TotalMarginRequired float64 `json:"total_margin_required"`
+	AvailableMargin     float64 `json:"available_margin"`
+	AccountEquity       float64 `json:"account_equity"`
 }
 
 // RiskManager manages trading risks and monitoring
 type RiskManager struct {
-	db         *gorm.DB
-	riskLimits RiskLimits
-	riskAlerts []RiskAlert
+	db                *gorm.DB
+	riskLimits        RiskLimits
+	riskAlerts        []RiskAlert
+	circuitBreakerCfg CircuitBreakerConfig
+	marginCredit      *MarginCreditManager
+	crossHedger       *CrossHedger
+	varMethod         VaRMethod
+}
+
+// SetVaRMethod overrides the VaRMethod CalculateVaR delegates to. The
+// default is ParametricVaR.
+func (rm *RiskManager) SetVaRMethod(method VaRMethod) {
+	rm.varMethod = method
+}
+
+// SetCrossHedger wires a CrossHedger into GenerateRiskReport so its report
+// includes an open_hedges section.
+func (rm *RiskManager) SetCrossHedger(ch *CrossHedger) {
+	rm.crossHedger = ch
+}
+
+// MarginCreditAsset is the asset MarginCreditManager auto-borrows/auto-repays
+// against to cover a margin shortfall, since this simulator tracks a single
+// cash balance per user rather than a multi-asset wallet.
+const MarginCreditAsset = "USD"
+
+// SetMarginCreditManager wires a MarginCreditManager into CheckPreTradeRisk
+// so a margin shortfall triggers an auto-borrow top-up instead of an
+// outright rejection.
+func (rm *RiskManager) SetMarginCreditManager(mcm *MarginCreditManager) {
+	rm.marginCredit = mcm
 }
 
 // NewRiskManager creates a new risk manager
 func NewRiskManager(db *gorm.DB) *RiskManager {
 	return &RiskManager{
-		db:         db,
-		riskLimits: DefaultRiskLimits(),
-		riskAlerts: make([]RiskAlert, 0),
+		db:                db,
+		riskLimits:        DefaultRiskLimits(),
+		riskAlerts:        make([]RiskAlert, 0),
+		circuitBreakerCfg: DefaultCircuitBreakerConfig(),
+		varMethod:         ParametricVaR{},
 	}
 }
 
 // CheckPreTradeRisk checks risk limits before allowing a trade
 func (rm *RiskManager) CheckPreTradeRisk(userID uint, orderRequest core.OrderCreateRequest) map[string]interface{} {
+	if state, reason := rm.EvaluateCircuitBreaker(userID); state == CircuitStateOpen {
+		return map[string]interface{}{
+			"allowed": false,
+			"reason":  reason,
+		}
+	} else if state == CircuitStateHalfOpen {
+		if !rm.consumeProbeOrder(userID) {
+			return map[string]interface{}{
+				"allowed": false,
+				"reason":  "circuit breaker half-open: probe order already in flight",
+			}
+		}
+	}
+
 	var user core.User
 	if err := rm.db.First(&user, userID).Error; err != nil {
 		return map[string]interface{}{
@@ -226,66 +273,23 @@ func (rm *RiskManager) MonitorMarginRequirements(userID uint, currentPrices map[
 	rm.db.Save(&user)
 
 	return MarginStatus{
-		MarginAdequate:       !marginCall,
-		MarginCall:           marginCall,
-		ForceLiquidation:     forceLiquidation,
-		MarginUtilization:    marginUtilization,
-		TotalMarginRequired:  totalMarginRequired,
-		AvailableMargin:      availableMargin,
-		AccountEquity:        accountEquity,
+		MarginAdequate:      !marginCall,
+		MarginCall:          marginCall,
+		ForceLiquidation:    forceLiquidation,
+		MarginUtilization:   marginUtilization,
+		TotalMarginRequired: totalMarginRequired,
+		AvailableMargin:     availableMargin,
+		AccountEquity:       accountEquity,
 	}
 }
 
-// CalculateVaR calculates Value at Risk for user's portfolio
+// CalculateVaR calculates Value at Risk for user's portfolio using the
+// configured VaRMethod (ParametricVaR by default; see SetVaRMethod).
 func (rm *RiskManager) CalculateVaR(userID uint, confidenceLevel float64, timeHorizon int) VaRMetrics {
 	var positions []core.Position
 	rm.db.Where("user_id = ?", userID).Find(&positions)
 
-	if len(positions) == 0 {
-		return VaRMetrics{
-			VaR:               0.0,
-			ExpectedShortfall: 0.0,
-			ConfidenceLevel:   confidenceLevel,
-			TimeHorizonDays:   timeHorizon,
-		}
-	}
-
-	// Calculate total exposure
-	totalExposure := 0.0
-	for _, pos := range positions {
-		totalExposure += math.Abs(pos.Quantity * pos.AvgEntryPrice)
-	}
-
-	// Simplified VaR calculation (parametric method)
-	// Assume 2% daily volatility for gold
-	dailyVolatility := 0.02
-
-	// For 95% confidence level, z-score is approximately 1.65
-	// For 99% confidence level, z-score is approximately 2.33
-	var zScore float64
-	switch {
-	case confidenceLevel >= 0.99:
-		zScore = 2.33
-	case confidenceLevel >= 0.95:
-		zScore = 1.65
-	default:
-		zScore = 1.28 // 90% confidence
-	}
-
-	// VaR calculation
-	var1Day := totalExposure * dailyVolatility * zScore
-	varTimeHorizon := var1Day * math.Sqrt(float64(timeHorizon))
-
-	// Expected Shortfall (simplified)
-	expectedShortfall := varTimeHorizon * 1.3
-
-	return VaRMetrics{
-		VaR:               math.Abs(varTimeHorizon),
-		ExpectedShortfall: math.Abs(expectedShortfall),
-		ConfidenceLevel:   confidenceLevel,
-		TimeHorizonDays:   timeHorizon,
-		TotalExposure:     totalExposure,
-	}
+	return rm.varMethod.Compute(rm.db, positions, confidenceLevel, timeHorizon)
 }
 
 // GenerateRiskReport generates comprehensive risk report for a user
@@ -296,7 +300,7 @@ func (rm *RiskManager) GenerateRiskReport(userID uint, currentPrices map[uint]fl
 
 	riskScore := rm.calculateRiskScore(marginStatus, varMetrics, postTradeRisk)
 
-	return map[string]interface{}{
+	report := map[string]interface{}{
 		"user_id":         userID,
 		"timestamp":       time.Now().Format(time.RFC3339),
 		"risk_score":      riskScore,
@@ -306,6 +310,14 @@ func (rm *RiskManager) GenerateRiskReport(userID uint, currentPrices map[uint]fl
 		"alerts":          postTradeRisk["alerts"],
 		"recommendations": rm.generateRiskRecommendations(riskScore, marginStatus, varMetrics),
 	}
+
+	if rm.crossHedger != nil {
+		if openHedges, err := rm.crossHedger.OpenHedges(userID); err == nil {
+			report["open_hedges"] = openHedges
+		}
+	}
+
+	return report
 }
 
 // checkMarginRequirements checks if user has sufficient margin for new order
@@ -323,9 +335,16 @@ func (rm *RiskManager) checkMarginRequirements(user *core.User, positions []core
 	additionalMargin := orderRequest.Quantity * contract.InitialMargin
 
 	if user.MarginAvailable < additionalMargin {
-		return map[string]interface{}{
-			"sufficient": false,
-			"reason":     "Insufficient margin available",
+		if rm.marginCredit != nil {
+			if borrowed, err := rm.marginCredit.EvaluateAndBorrow(user.ID, MarginCreditAsset); err == nil && borrowed > 0 {
+				user.MarginAvailable += borrowed
+			}
+		}
+		if user.MarginAvailable < additionalMargin {
+			return map[string]interface{}{
+				"sufficient": false,
+				"reason":     "Insufficient margin available",
+			}
 		}
 	}
 
@@ -352,7 +371,7 @@ func (rm *RiskManager) checkConcentrationLimits(positions []core.Position, order
 	// Calculate exposure for the contract in the order (simplified)
 	contractExposure := 0.0
 	estimatedPrice := 2000.0 // Default price estimate
-	
+
 	newExposure := orderRequest.Quantity * estimatedPrice
 	projectedContractExposure := contractExposure + newExposure
 	projectedConcentration := projectedContractExposure / (totalExposure + newExposure)
@@ -389,7 +408,7 @@ func (rm *RiskManager) calculateRiskMetrics(user *core.User, positions []core.Po
 	for _, pos := range positions {
 		exposure := math.Abs(pos.Quantity * pos.AvgEntryPrice)
 		totalExposure += exposure
-		totalUnrealizedPnL += pos.UnrealizedPnL
+		totalUnrealizedPnL += pos.UnrealizedPnL - rm.accruedInterestSince(user.ID, pos.CreatedAt)
 		positionExposures = append(positionExposures, exposure)
 	}
 
@@ -421,6 +440,18 @@ func (rm *RiskManager) calculateRiskMetrics(user *core.User, positions []core.Po
 	}
 }
 
+// accruedInterestSince sums MarginInterestRecord.Interest for userID from
+// since onward, the cost of carry calculateRiskMetrics nets out of
+// UnrealizedPnL so leverage/loss alerts reflect true economics rather than
+// mark-to-market alone.
+func (rm *RiskManager) accruedInterestSince(userID uint, since time.Time) float64 {
+	var total float64
+	rm.db.Model(&core.MarginInterestRecord{}).
+		Where("user_id = ? AND time >= ?", userID, since).
+		Select("COALESCE(SUM(interest),0)").Scan(&total)
+	return total
+}
+
 // generateRiskAlerts generates risk alerts based on metrics
 func (rm *RiskManager) generateRiskAlerts(riskMetrics RiskMetrics) []RiskAlert {
 	var alerts []RiskAlert
@@ -497,4 +528,4 @@ func (rm *RiskManager) generateRiskRecommendations(riskScore float64, marginStat
 	}
 
 	return recommendations
-}
\ No newline at end of file
+}
@@ -0,0 +1,227 @@
+package rms
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"gorm.io/gorm"
+)
+
+// AssetRule bounds auto-borrow/auto-repay behaviour for a single borrowable
+// asset. Low and High override the manager's MinMarginLevel/MaxMarginLevel
+// for this asset when non-zero.
+type AssetRule struct {
+	Low                  float64
+	High                 float64
+	MaxQuantityPerBorrow float64
+	MaxTotalBorrow       float64
+}
+
+// MarginCreditConfig configures MarginCreditManager's auto-borrow/auto-repay
+// behaviour, keyed off margin *level* (totalAssetValue / totalBorrowed)
+// rather than margin utilization ratio.
+type MarginCreditConfig struct {
+	MinMarginLevel   float64
+	MaxMarginLevel   float64
+	RepayWhenDeposit bool
+	AssetRules       map[string]AssetRule
+}
+
+// MarginCreditManager sits alongside RiskManager and actively borrows or
+// repays margin on a user's behalf to keep their margin level inside
+// [MinMarginLevel, MaxMarginLevel].
+type MarginCreditManager struct {
+	db  *gorm.DB
+	cfg MarginCreditConfig
+}
+
+// NewMarginCreditManager creates a new margin credit manager.
+func NewMarginCreditManager(db *gorm.DB, cfg MarginCreditConfig) *MarginCreditManager {
+	return &MarginCreditManager{db: db, cfg: cfg}
+}
+
+// MarginLevel returns totalAssetValue / totalBorrowed for userID across all
+// assets, or +Inf when nothing is borrowed.
+func (m *MarginCreditManager) MarginLevel(userID uint) float64 {
+	var user core.User
+	if err := m.db.First(&user, userID).Error; err != nil {
+		return math.Inf(1)
+	}
+
+	totalBorrowed := m.totalBorrowed(userID)
+	if totalBorrowed <= 0 {
+		return math.Inf(1)
+	}
+	return user.AccountBalance / totalBorrowed
+}
+
+// EvaluateAndBorrow checks userID's margin level and, if it has fallen below
+// MinMarginLevel (or the asset's Low override), borrows the smaller of the
+// asset's MaxQuantityPerBorrow and the gap needed to reach MinMarginLevel,
+// subject to MaxTotalBorrow. It returns the amount borrowed, which is zero
+// when no borrow was needed or possible.
+func (m *MarginCreditManager) EvaluateAndBorrow(userID uint, asset string) (float64, error) {
+	rule, ok := m.cfg.AssetRules[asset]
+	if !ok {
+		return 0, fmt.Errorf("no asset rule configured for %s", asset)
+	}
+
+	minLevel := m.cfg.MinMarginLevel
+	if rule.Low > 0 {
+		minLevel = rule.Low
+	}
+	if m.MarginLevel(userID) >= minLevel {
+		return 0, nil
+	}
+
+	var user core.User
+	if err := m.db.First(&user, userID).Error; err != nil {
+		return 0, fmt.Errorf("user not found: %w", err)
+	}
+
+	outstanding := m.outstandingBorrow(userID, asset)
+	headroom := rule.MaxTotalBorrow - outstanding
+	if headroom <= 0 {
+		return 0, fmt.Errorf("max total borrow reached for %s", asset)
+	}
+
+	totalBorrowed := m.totalBorrowed(userID)
+	gapNeeded := user.AccountBalance/minLevel - totalBorrowed
+	amount := math.Min(rule.MaxQuantityPerBorrow, gapNeeded)
+	amount = math.Min(amount, headroom)
+	if amount <= 0 {
+		return 0, nil
+	}
+
+	if err := m.recordLoan(userID, asset, core.MarginLoanActionBorrow, amount); err != nil {
+		return 0, err
+	}
+	user.MarginAvailable += amount
+	if err := m.db.Save(&user).Error; err != nil {
+		return 0, err
+	}
+	return amount, nil
+}
+
+// EvaluateAndRepay checks userID's margin level and, if it has risen above
+// MaxMarginLevel (or the asset's High override), repays the smaller of
+// freeBalance and the outstanding borrow for asset, proportional to how far
+// above MaxMarginLevel the account sits.
+func (m *MarginCreditManager) EvaluateAndRepay(userID uint, asset string, freeBalance float64) (float64, error) {
+	rule, ok := m.cfg.AssetRules[asset]
+	if !ok {
+		return 0, fmt.Errorf("no asset rule configured for %s", asset)
+	}
+
+	maxLevel := m.cfg.MaxMarginLevel
+	if rule.High > 0 {
+		maxLevel = rule.High
+	}
+	level := m.MarginLevel(userID)
+	if level <= maxLevel || math.IsInf(level, 1) || freeBalance <= 0 {
+		return 0, nil
+	}
+
+	outstanding := m.outstandingBorrow(userID, asset)
+	if outstanding <= 0 {
+		return 0, nil
+	}
+
+	excessRatio := math.Min(1, (level-maxLevel)/maxLevel)
+	amount := math.Min(freeBalance, outstanding*excessRatio)
+	if amount <= 0 {
+		return 0, nil
+	}
+
+	if err := m.recordLoan(userID, asset, core.MarginLoanActionRepay, amount); err != nil {
+		return 0, err
+	}
+	return amount, nil
+}
+
+// AccrueInterest records a MarginInterestRecord for the outstanding
+// principal of asset at the given periodic rate (e.g. 0.0003 for a daily
+// rate). It is a no-op when nothing is outstanding.
+func (m *MarginCreditManager) AccrueInterest(userID uint, asset string, rate float64) error {
+	principle := m.outstandingBorrow(userID, asset)
+	if principle <= 0 {
+		return nil
+	}
+
+	record := core.MarginInterestRecord{
+		UserID:        userID,
+		TransactionID: uuid.New().String(),
+		Asset:         asset,
+		Principle:     principle,
+		Interest:      principle * rate,
+		InterestRate:  rate,
+		Time:          time.Now(),
+	}
+	return m.db.Create(&record).Error
+}
+
+// QueryLoanHistory returns userID's borrow transactions, most recent first.
+func (m *MarginCreditManager) QueryLoanHistory(userID uint) ([]core.MarginLoanRecord, error) {
+	var records []core.MarginLoanRecord
+	err := m.db.Where("user_id = ? AND action = ?", userID, core.MarginLoanActionBorrow).
+		Order("time desc").Find(&records).Error
+	return records, err
+}
+
+// QueryRepayHistory returns userID's repay transactions, most recent first.
+func (m *MarginCreditManager) QueryRepayHistory(userID uint) ([]core.MarginLoanRecord, error) {
+	var records []core.MarginLoanRecord
+	err := m.db.Where("user_id = ? AND action = ?", userID, core.MarginLoanActionRepay).
+		Order("time desc").Find(&records).Error
+	return records, err
+}
+
+// QueryInterestHistory returns userID's accrued interest records, most
+// recent first.
+func (m *MarginCreditManager) QueryInterestHistory(userID uint) ([]core.MarginInterestRecord, error) {
+	var records []core.MarginInterestRecord
+	err := m.db.Where("user_id = ?", userID).Order("time desc").Find(&records).Error
+	return records, err
+}
+
+// recordLoan appends a MarginLoanRecord for a borrow or repay action.
+func (m *MarginCreditManager) recordLoan(userID uint, asset string, action core.MarginLoanAction, principle float64) error {
+	record := core.MarginLoanRecord{
+		UserID:        userID,
+		TransactionID: uuid.New().String(),
+		Action:        action,
+		Asset:         asset,
+		Principle:     principle,
+		Time:          time.Now(),
+	}
+	return m.db.Create(&record).Error
+}
+
+// outstandingBorrow returns the net outstanding principal for userID and
+// asset (total borrowed minus total repaid).
+func (m *MarginCreditManager) outstandingBorrow(userID uint, asset string) float64 {
+	var borrowed, repaid float64
+	m.db.Model(&core.MarginLoanRecord{}).
+		Where("user_id = ? AND asset = ? AND action = ?", userID, asset, core.MarginLoanActionBorrow).
+		Select("COALESCE(SUM(principle),0)").Scan(&borrowed)
+	m.db.Model(&core.MarginLoanRecord{}).
+		Where("user_id = ? AND asset = ? AND action = ?", userID, asset, core.MarginLoanActionRepay).
+		Select("COALESCE(SUM(principle),0)").Scan(&repaid)
+	return borrowed - repaid
+}
+
+// totalBorrowed returns the net outstanding principal for userID across all
+// assets.
+func (m *MarginCreditManager) totalBorrowed(userID uint) float64 {
+	var borrowed, repaid float64
+	m.db.Model(&core.MarginLoanRecord{}).
+		Where("user_id = ? AND action = ?", userID, core.MarginLoanActionBorrow).
+		Select("COALESCE(SUM(principle),0)").Scan(&borrowed)
+	m.db.Model(&core.MarginLoanRecord{}).
+		Where("user_id = ? AND action = ?", userID, core.MarginLoanActionRepay).
+		Select("COALESCE(SUM(principle),0)").Scan(&repaid)
+	return borrowed - repaid
+}
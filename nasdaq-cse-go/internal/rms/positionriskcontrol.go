@@ -0,0 +1,93 @@
+package rms
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+)
+
+// PositionUpdate is the post-trade position snapshot a TradeCollector
+// publishes after each fill.
+type PositionUpdate struct {
+	UserID         uint
+	ContractSymbol string
+	NetQuantity    float64
+}
+
+// TradeCollector is implemented by whatever publishes post-trade position
+// updates, so PositionRiskControl can observe them without importing oms.
+// oms.OrderManager does not implement it: its Subscribe streams oms.Event
+// values instead.
+type TradeCollector interface {
+	Subscribe(func(PositionUpdate))
+}
+
+// OrderSubmitter is the pluggable seam PositionRiskControl's default
+// release callback uses to place the offsetting market order, so both
+// nasdaq-cse-go and external adapters can plug in.
+type OrderSubmitter interface {
+	SubmitMarketOrder(userID uint, contractSymbol string, side core.OrderSide, quantity float64) error
+}
+
+// PositionRiskControl enforces positionHardLimit by automatically emitting
+// offsetting orders once a position has already accumulated past the
+// limit, complementing CheckPreTradeRisk which can only block new orders.
+type PositionRiskControl struct {
+	hardLimit        float64
+	maxQtyPerRelease float64
+	onRelease        func(update PositionUpdate, quantity float64, side core.OrderSide)
+}
+
+// NewPositionRiskControl creates a PositionRiskControl enforcing hardLimit
+// and subscribes it to collector's post-trade position updates. Each
+// release is capped at maxQtyPerRelease.
+func NewPositionRiskControl(hardLimit, maxQtyPerRelease float64, collector TradeCollector) *PositionRiskControl {
+	prc := &PositionRiskControl{
+		hardLimit:        hardLimit,
+		maxQtyPerRelease: maxQtyPerRelease,
+	}
+	collector.Subscribe(prc.onPositionUpdate)
+	return prc
+}
+
+// OnReleasePosition registers the callback fired when a position exceeds
+// the hard limit, receiving the release quantity and the offsetting side.
+func (prc *PositionRiskControl) OnReleasePosition(fn func(update PositionUpdate, quantity float64, side core.OrderSide)) {
+	prc.onRelease = fn
+}
+
+// onPositionUpdate is the TradeCollector subscription handler.
+func (prc *PositionRiskControl) onPositionUpdate(update PositionUpdate) {
+	net := math.Abs(update.NetQuantity)
+	if net <= prc.hardLimit || prc.onRelease == nil {
+		return
+	}
+
+	releaseQty := math.Min(net-prc.hardLimit, prc.maxQtyPerRelease)
+	side := core.OrderSideSell
+	if update.NetQuantity < 0 {
+		side = core.OrderSideBuy
+	}
+	prc.onRelease(update, releaseQty, side)
+}
+
+// WirePositionRiskControl registers RiskManager's default release callback
+// on prc: it submits an offsetting market order through submitter and
+// records a HARD_LIMIT_RELEASE RiskAlert for every release.
+func (rm *RiskManager) WirePositionRiskControl(prc *PositionRiskControl, submitter OrderSubmitter) {
+	prc.OnReleasePosition(func(update PositionUpdate, quantity float64, side core.OrderSide) {
+		err := submitter.SubmitMarketOrder(update.UserID, update.ContractSymbol, side, quantity)
+
+		alert := RiskAlert{
+			Type:           "HARD_LIMIT_RELEASE",
+			Severity:       "HIGH",
+			Message:        fmt.Sprintf("User %d: position hard limit exceeded on %s, releasing %.2f %s", update.UserID, update.ContractSymbol, quantity, side),
+			Recommendation: "Review strategy sizing to avoid repeated hard-limit releases",
+		}
+		if err != nil {
+			alert.Message += fmt.Sprintf(" (release order failed: %v)", err)
+		}
+		rm.riskAlerts = append(rm.riskAlerts, alert)
+	})
+}
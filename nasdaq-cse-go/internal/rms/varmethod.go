@@ -0,0 +1,163 @@
+package rms
+
+import (
+	"math"
+	"sort"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"gorm.io/gorm"
+)
+
+// VaRMethod computes VaRMetrics for a portfolio of positions, letting
+// CalculateVaR swap parametric and historical-simulation approaches.
+type VaRMethod interface {
+	Compute(db *gorm.DB, positions []core.Position, confidenceLevel float64, timeHorizonDays int) VaRMetrics
+}
+
+// ParametricVaR assumes a fixed daily volatility and looks up a z-score for
+// the requested confidence level. It needs no price history, so it also
+// serves as HistoricalSimulationVaR's fallback for short series.
+type ParametricVaR struct{}
+
+// Compute implements VaRMethod.
+func (ParametricVaR) Compute(db *gorm.DB, positions []core.Position, confidenceLevel float64, timeHorizonDays int) VaRMetrics {
+	if len(positions) == 0 {
+		return VaRMetrics{
+			ConfidenceLevel: confidenceLevel,
+			TimeHorizonDays: timeHorizonDays,
+		}
+	}
+
+	totalExposure := 0.0
+	for _, pos := range positions {
+		totalExposure += math.Abs(pos.Quantity * pos.AvgEntryPrice)
+	}
+
+	// Assume 2% daily volatility for gold.
+	dailyVolatility := 0.02
+
+	// For 95% confidence level, z-score is approximately 1.65.
+	// For 99% confidence level, z-score is approximately 2.33.
+	var zScore float64
+	switch {
+	case confidenceLevel >= 0.99:
+		zScore = 2.33
+	case confidenceLevel >= 0.95:
+		zScore = 1.65
+	default:
+		zScore = 1.28 // 90% confidence
+	}
+
+	var1Day := totalExposure * dailyVolatility * zScore
+	varTimeHorizon := var1Day * math.Sqrt(float64(timeHorizonDays))
+
+	// Expected Shortfall (simplified)
+	expectedShortfall := varTimeHorizon * 1.3
+
+	return VaRMetrics{
+		VaR:               math.Abs(varTimeHorizon),
+		ExpectedShortfall: math.Abs(expectedShortfall),
+		ConfidenceLevel:   confidenceLevel,
+		TimeHorizonDays:   timeHorizonDays,
+		TotalExposure:     totalExposure,
+	}
+}
+
+// HistoricalSimulationVaR builds a portfolio PnL vector from the last
+// WindowDays+1 closes (WindowDays daily changes) of core.PriceHistory per
+// position, and reports the
+// (1-confidence)-quantile loss as VaR and the mean of the tail beyond it as
+// Expected Shortfall. It falls back to Fallback (ParametricVaR by default)
+// and sets VaRMetrics.Degraded when any position's price history is
+// shorter than two points.
+type HistoricalSimulationVaR struct {
+	WindowDays int
+	Fallback   VaRMethod
+}
+
+// NewHistoricalSimulationVaR creates a HistoricalSimulationVaR with the
+// given lookback window, falling back to ParametricVaR on short series.
+func NewHistoricalSimulationVaR(windowDays int) *HistoricalSimulationVaR {
+	return &HistoricalSimulationVaR{WindowDays: windowDays, Fallback: ParametricVaR{}}
+}
+
+// Compute implements VaRMethod.
+func (h *HistoricalSimulationVaR) Compute(db *gorm.DB, positions []core.Position, confidenceLevel float64, timeHorizonDays int) VaRMetrics {
+	if len(positions) == 0 {
+		return VaRMetrics{
+			ConfidenceLevel: confidenceLevel,
+			TimeHorizonDays: timeHorizonDays,
+		}
+	}
+
+	histories := make(map[uint][]float64, len(positions))
+	minLen := -1
+	for _, pos := range positions {
+		if _, loaded := histories[pos.ContractID]; loaded {
+			continue
+		}
+		var rows []core.PriceHistory
+		db.Where("contract_id = ?", pos.ContractID).Order("date desc").Limit(h.WindowDays + 1).Find(&rows)
+
+		prices := make([]float64, len(rows))
+		for i, row := range rows {
+			prices[len(rows)-1-i] = row.Price // oldest first
+		}
+		histories[pos.ContractID] = prices
+
+		if minLen == -1 || len(prices) < minLen {
+			minLen = len(prices)
+		}
+	}
+
+	if minLen < 2 {
+		fallback := h.Fallback
+		if fallback == nil {
+			fallback = ParametricVaR{}
+		}
+		metrics := fallback.Compute(db, positions, confidenceLevel, timeHorizonDays)
+		metrics.Degraded = true
+		return metrics
+	}
+
+	numSamples := minLen - 1
+	portfolioPnL := make([]float64, numSamples)
+	totalExposure := 0.0
+
+	for _, pos := range positions {
+		prices := histories[pos.ContractID]
+		offset := len(prices) - (numSamples + 1)
+		for t := 1; t <= numSamples; t++ {
+			portfolioPnL[t-1] += pos.Quantity * (prices[offset+t] - prices[offset+t-1])
+		}
+		totalExposure += math.Abs(pos.Quantity * prices[len(prices)-1])
+	}
+
+	sort.Float64s(portfolioPnL)
+
+	tailIndex := int(math.Floor((1 - confidenceLevel) * float64(len(portfolioPnL))))
+	if tailIndex >= len(portfolioPnL) {
+		tailIndex = len(portfolioPnL) - 1
+	}
+	if tailIndex < 0 {
+		tailIndex = 0
+	}
+
+	horizonScale := math.Sqrt(float64(timeHorizonDays))
+	varLoss := -portfolioPnL[tailIndex] * horizonScale
+
+	tail := portfolioPnL[:tailIndex+1]
+	tailSum := 0.0
+	for _, pnl := range tail {
+		tailSum += pnl
+	}
+	esLoss := -(tailSum / float64(len(tail))) * horizonScale
+
+	return VaRMetrics{
+		VaR:               math.Abs(varLoss),
+		ExpectedShortfall: math.Abs(esLoss),
+		ConfidenceLevel:   confidenceLevel,
+		TimeHorizonDays:   timeHorizonDays,
+		TotalExposure:     totalExposure,
+	}
+}
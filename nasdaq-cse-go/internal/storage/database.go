@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -37,13 +38,24 @@ func NewDatabaseManager(databasePath string) (*DatabaseManager, error) {
 		&core.Position{},
 		&core.MarketData{},
 		&core.AIAnalysis{},
+		&core.CircuitBreakerState{},
+		&core.MarginLoanRecord{},
+		&core.MarginInterestRecord{},
+		&core.HedgeLeg{},
+		&core.PriceHistory{},
+		&core.FIXSessionRecord{},
+		&core.FIXPositionTierRecord{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if err := migrateTradeStrategyColumns(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate trade P&L/strategy columns: %w", err)
+	}
+
 	dm := &DatabaseManager{db: db}
-	
+
 	// Initialize sample data
 	if err := dm.initSampleData(); err != nil {
 		return nil, fmt.Errorf("failed to initialize sample data: %w", err)
@@ -52,6 +64,29 @@ func NewDatabaseManager(databasePath string) (*DatabaseManager, error) {
 	return dm, nil
 }
 
+// migrateTradeStrategyColumns is a belt-and-suspenders check that runs
+// after AutoMigrate: on a database created before trades carried P&L and
+// strategy attribution, AutoMigrate already adds the two new nullable
+// columns without touching existing rows (their pnl stays NULL, their
+// strategy stays ""), but we add the columns explicitly too so this
+// keeps working even if AutoMigrate's own column-add behavior is ever
+// disabled for this table. It's a no-op on a database that already has
+// them.
+func migrateTradeStrategyColumns(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasColumn(&core.Trade{}, "PnL") {
+		if err := migrator.AddColumn(&core.Trade{}, "PnL"); err != nil {
+			return fmt.Errorf("failed to add pnl column: %w", err)
+		}
+	}
+	if !migrator.HasColumn(&core.Trade{}, "Strategy") {
+		if err := migrator.AddColumn(&core.Trade{}, "Strategy"); err != nil {
+			return fmt.Errorf("failed to add strategy column: %w", err)
+		}
+	}
+	return nil
+}
+
 // GetDB returns the database instance
 func (dm *DatabaseManager) GetDB() *gorm.DB {
 	return dm.db
@@ -115,10 +150,17 @@ func (dm *DatabaseManager) initSampleData() error {
 		}
 	}
 
-	// Create sample user
+	// Create sample user with a bcrypt-hashed default password so the demo
+	// account can log in through internal/auth without a separate setup step.
+	demoPasswordHash, err := bcrypt.GenerateFromPassword([]byte("demo123"), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash sample user password: %w", err)
+	}
+
 	user := core.User{
 		Username:        "demo_trader",
 		Email:           "demo@example.com",
+		PasswordHash:    string(demoPasswordHash),
 		AccountBalance:  100000.0,
 		MarginAvailable: 100000.0,
 		IsActive:        true,
@@ -205,10 +247,40 @@ func (js *JSONStorage) LoadAIAnalysis() (map[string]interface{}, error) {
 	return analysis, nil
 }
 
+// SaveGridState saves a grid strategy's open-level bookkeeping to JSON file
+func (js *JSONStorage) SaveGridState(state map[string]interface{}) error {
+	return js.saveToFile("grid_state.json", state)
+}
+
+// LoadGridState loads a grid strategy's open-level bookkeeping from JSON file
+func (js *JSONStorage) LoadGridState() (map[string]interface{}, error) {
+	var state map[string]interface{}
+	err := js.loadFromFile("grid_state.json", &state)
+	if err != nil {
+		return make(map[string]interface{}), nil // Return empty map if file doesn't exist
+	}
+	return state, nil
+}
+
+// SaveInstruments saves the instrument registry's contents to JSON file
+func (js *JSONStorage) SaveInstruments(instruments []core.InstrumentInfo) error {
+	return js.saveToFile("instruments.json", instruments)
+}
+
+// LoadInstruments loads the instrument registry's contents from JSON file
+func (js *JSONStorage) LoadInstruments() ([]core.InstrumentInfo, error) {
+	var instruments []core.InstrumentInfo
+	err := js.loadFromFile("instruments.json", &instruments)
+	if err != nil {
+		return nil, nil
+	}
+	return instruments, nil
+}
+
 // saveToFile saves data to a JSON file
 func (js *JSONStorage) saveToFile(filename string, data interface{}) error {
 	filePath := filepath.Join(js.storageDir, filename)
-	
+
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal data to JSON: %w", err)
@@ -224,7 +296,7 @@ func (js *JSONStorage) saveToFile(filename string, data interface{}) error {
 // loadFromFile loads data from a JSON file
 func (js *JSONStorage) loadFromFile(filename string, target interface{}) error {
 	filePath := filepath.Join(js.storageDir, filename)
-	
+
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return fmt.Errorf("file %s does not exist", filename)
 	}
@@ -254,4 +326,4 @@ type Storage interface {
 }
 
 // Ensure JSONStorage implements Storage interface
-var _ Storage = (*JSONStorage)(nil)
\ No newline at end of file
+var _ Storage = (*JSONStorage)(nil)
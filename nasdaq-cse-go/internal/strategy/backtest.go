@@ -0,0 +1,123 @@
+package strategy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/exchange"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/oms"
+)
+
+// BacktestReport summarizes a Backtest run: how much the strategy's paper
+// position made or lost replaying history, and how many fills it took to
+// get there.
+type BacktestReport struct {
+	StrategyID  string  `json:"strategy_id"`
+	Symbol      string  `json:"symbol"`
+	Klines      int     `json:"klines"`
+	Trades      int     `json:"trades"`
+	RealizedPnL float64 `json:"realized_pnl"`
+	OpenPnL     float64 `json:"open_pnl"`
+}
+
+// backtestLedger is a minimal paper-trading book: one position tracked by
+// weighted-average entry price, settling realized P&L only when the
+// position fully closes, mirroring oms.OrderManager.updatePositions'
+// simulator-grade bookkeeping. Realized P&L is positive for a profitable
+// round trip.
+type backtestLedger struct {
+	lastPrice     float64
+	position      float64
+	avgEntryPrice float64
+	realizedPnL   float64
+	trades        int
+}
+
+func (l *backtestLedger) apply(orderRequest core.OrderCreateRequest) oms.SubmitOrderResult {
+	price := l.lastPrice
+	if orderRequest.Price != nil {
+		price = *orderRequest.Price
+	}
+
+	quantity := orderRequest.Quantity
+	if orderRequest.Side == core.OrderSideSell {
+		quantity = -quantity
+	}
+
+	oldValue := l.position * l.avgEntryPrice
+	newValue := quantity * price
+	l.position += quantity
+
+	if l.position == 0 {
+		l.realizedPnL -= oldValue + newValue
+		l.avgEntryPrice = 0
+	} else {
+		l.avgEntryPrice = (oldValue + newValue) / l.position
+	}
+	l.trades++
+
+	return oms.SubmitOrderResult{
+		Success: true,
+		Status:  "filled",
+		Trades: []map[string]interface{}{
+			{"price": price, "quantity": orderRequest.Quantity},
+		},
+	}
+}
+
+func (l *backtestLedger) openPnL() float64 {
+	return l.position * (l.lastPrice - l.avgEntryPrice)
+}
+
+// Backtest replays symbol's stored core.PriceHistory between from and to
+// into id's strategy as single-price klines, settling every order the
+// strategy places against an isolated ledger, then returns a P&L report.
+// It never touches orderManager or riskManager, so it's safe to run
+// against a live strategy instance without affecting real positions.
+func (r *StrategyRunner) Backtest(id, symbol string, from, to time.Time) (*BacktestReport, error) {
+	r.mutex.Lock()
+	inst, ok := r.strategies[id]
+	r.mutex.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("strategy: %s is not registered", id)
+	}
+
+	var contract core.Contract
+	if err := r.db.Where("symbol = ?", symbol).First(&contract).Error; err != nil {
+		return nil, fmt.Errorf("strategy: failed to look up contract %s: %w", symbol, err)
+	}
+
+	var history []core.PriceHistory
+	if err := r.db.Where("contract_id = ? AND date BETWEEN ? AND ?", contract.ID, from, to).Order("date ASC").Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("strategy: failed to load price history for %s: %w", symbol, err)
+	}
+
+	ledger := &backtestLedger{}
+	ctx := &StrategyContext{UserID: inst.userID, id: id, runner: r, ledger: ledger}
+	inst.strategy.Init(ctx)
+
+	for _, point := range history {
+		ledger.lastPrice = point.Price
+		inst.strategy.OnKline(exchange.Kline{
+			Symbol:    symbol,
+			Interval:  "1d",
+			Open:      point.Price,
+			High:      point.Price,
+			Low:       point.Price,
+			Close:     point.Price,
+			OpenTime:  point.Date,
+			CloseTime: point.Date,
+			Closed:    true,
+		})
+	}
+
+	return &BacktestReport{
+		StrategyID:  id,
+		Symbol:      symbol,
+		Klines:      len(history),
+		Trades:      ledger.trades,
+		RealizedPnL: ledger.realizedPnL,
+		OpenPnL:     ledger.openPnL(),
+	}, nil
+}
@@ -0,0 +1,387 @@
+// Package calspread implements a calendar-spread arbitrage module that
+// watches the price difference between two maturities of the same
+// underlying and trades the spread back toward its rolling mean. It runs
+// its own polling goroutine rather than plugging into the kline-driven
+// Strategy interface in the parent strategy package, since a calendar
+// spread needs both legs' latest prices sampled together on a fixed
+// cadence, not reacted to tick by tick.
+package calspread
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/oms"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/storage"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// Pair is one calendar-spread leg pair to watch: Near and Far are
+// contract symbols on the same underlying, with Far's expiry later than
+// Near's.
+type Pair struct {
+	Near string `yaml:"near"`
+	Far  string `yaml:"far"`
+}
+
+// Config is calspread's YAML config document. Limits mirrors the
+// `limits:` block in bbgo's triangular-arbitrage config: a per-symbol
+// cap on net position size, keyed by contract symbol rather than
+// currency.
+type Config struct {
+	Pairs          []Pair             `yaml:"pairs"`
+	WindowSize     int                `yaml:"windowSize"`
+	MinSpreadRatio float64            `yaml:"minSpreadRatio"`
+	ExitRatio      float64            `yaml:"exitRatio"`
+	PollInterval   string             `yaml:"pollInterval"` // parsed with time.ParseDuration, e.g. "5s"
+	Quantity       float64            `yaml:"quantity"`
+	Limits         map[string]float64 `yaml:"limits"`
+
+	pollInterval time.Duration
+}
+
+// LoadConfig reads and parses calspread's YAML config file at path,
+// filling in defaults for anything left unset.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("calspread: failed to read config %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("calspread: failed to parse config %s: %w", path, err)
+	}
+
+	if cfg.WindowSize <= 0 {
+		cfg.WindowSize = 50
+	}
+	if cfg.MinSpreadRatio <= 0 {
+		cfg.MinSpreadRatio = 2.0
+	}
+	if cfg.ExitRatio <= 0 {
+		cfg.ExitRatio = 0.25
+	}
+	cfg.pollInterval = 5 * time.Second
+	if cfg.PollInterval != "" {
+		cfg.pollInterval, err = time.ParseDuration(cfg.PollInterval)
+		if err != nil {
+			return nil, fmt.Errorf("calspread: invalid pollInterval %q: %w", cfg.PollInterval, err)
+		}
+	}
+	return &cfg, nil
+}
+
+// spreadStats maintains a fixed-size rolling window of spread
+// observations and its mean/variance incrementally via Welford's
+// algorithm: each new observation, once the window is full, first
+// reverses the oldest sample's contribution (the sliding-window
+// counterpart to Welford's usual add-only update) before folding in the
+// new one, so updating mean/stddev never requires rescanning the window.
+type spreadStats struct {
+	window []float64
+	size   int
+	count  int // number of valid samples currently held, caps at size
+	next   int // index the next observation will occupy (and evict)
+	mean   float64
+	m2     float64 // running sum of squared deviations from mean
+}
+
+func newSpreadStats(size int) *spreadStats {
+	return &spreadStats{window: make([]float64, size), size: size}
+}
+
+// observe folds value into the rolling window and returns the updated
+// mean and sample standard deviation. stddev is 0 until at least 2
+// samples have been observed.
+func (s *spreadStats) observe(value float64) (mean, stddev float64) {
+	if s.count == s.size {
+		s.evict(s.window[s.next])
+	}
+	s.window[s.next] = value
+	s.next = (s.next + 1) % s.size
+	s.count++
+
+	n := float64(s.count)
+	delta := value - s.mean
+	s.mean += delta / n
+	s.m2 += delta * (value - s.mean)
+
+	if s.count < 2 {
+		return s.mean, 0
+	}
+	return s.mean, math.Sqrt(s.m2 / (n - 1))
+}
+
+// evict reverses old's contribution to the running mean/m2, making room
+// for the new sample replacing it without needing to rescan the window.
+func (s *spreadStats) evict(old float64) {
+	n := float64(s.count)
+	newN := n - 1
+	newMean := (s.mean*n - old) / newN
+	s.m2 -= (old - s.mean) * (old - newMean)
+	s.mean = newMean
+	s.count--
+}
+
+// openLeg records a currently-open calendar-spread position so CalSpread
+// knows which direction to unwind and refuses a second entry on the same
+// pair while one is live.
+type openLeg struct {
+	Near        string    `json:"near"`
+	Far         string    `json:"far"`
+	LongNear    bool      `json:"long_near"` // true: bought Near/sold Far; false: the reverse
+	Quantity    float64   `json:"quantity"`
+	EntrySpread float64   `json:"entry_spread"`
+	OpenedAt    time.Time `json:"opened_at"`
+}
+
+// CalSpread runs a calendar-spread arbitrage loop: it polls each
+// configured Pair's last trade price, maintains a rolling mean/stddev of
+// the far-minus-near spread, and fires a paired buy-near/sell-far (or
+// the reverse) through OrderManager.BatchSubmitOrders when the spread
+// strays more than Config.MinSpreadRatio standard deviations from its
+// mean. Open legs are tracked in memory and unwound back toward flat
+// once the spread has reverted inside Config.ExitRatio standard
+// deviations.
+type CalSpread struct {
+	orderManager *oms.OrderManager
+	jsonStorage  *storage.JSONStorage
+	db           *gorm.DB
+	userID       uint
+	cfg          Config
+
+	mu     sync.Mutex
+	stats  map[string]*spreadStats
+	open   map[string]*openLeg
+	events map[string]interface{} // latest snapshot per pair key, written out whole
+}
+
+// NewCalSpread creates a CalSpread trading userID's account. jsonStorage
+// may be nil, in which case event persistence is skipped.
+func NewCalSpread(orderManager *oms.OrderManager, db *gorm.DB, jsonStorage *storage.JSONStorage, userID uint, cfg Config) *CalSpread {
+	return &CalSpread{
+		orderManager: orderManager,
+		jsonStorage:  jsonStorage,
+		db:           db,
+		userID:       userID,
+		cfg:          cfg,
+		stats:        make(map[string]*spreadStats),
+		open:         make(map[string]*openLeg),
+		events:       make(map[string]interface{}),
+	}
+}
+
+// Run polls every Config.PollInterval until ctx is canceled, evaluating
+// every configured pair on each tick. It's meant to be started with `go`.
+func (cs *CalSpread) Run(ctx context.Context) {
+	ticker := time.NewTicker(cs.cfg.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, pair := range cs.cfg.Pairs {
+				cs.evaluate(pair)
+			}
+		}
+	}
+}
+
+func pairKey(pair Pair) string {
+	return pair.Near + "/" + pair.Far
+}
+
+// evaluate samples pair's current spread, updates its rolling stats, and
+// enters or unwinds a position per the configured thresholds.
+func (cs *CalSpread) evaluate(pair Pair) {
+	nearPrice, ok := cs.lastPrice(pair.Near)
+	if !ok {
+		return
+	}
+	farPrice, ok := cs.lastPrice(pair.Far)
+	if !ok {
+		return
+	}
+	spread := farPrice - nearPrice
+
+	cs.mu.Lock()
+	key := pairKey(pair)
+	stats, ok := cs.stats[key]
+	if !ok {
+		stats = newSpreadStats(cs.cfg.WindowSize)
+		cs.stats[key] = stats
+	}
+	mean, stddev := stats.observe(spread)
+	leg := cs.open[key]
+	cs.mu.Unlock()
+
+	cs.recordEvent(pair, map[string]interface{}{
+		"spread": spread,
+		"mean":   mean,
+		"stddev": stddev,
+		"open":   leg,
+	})
+
+	if stddev == 0 {
+		return
+	}
+	deviation := spread - mean
+
+	if leg != nil {
+		if math.Abs(deviation) < cs.cfg.ExitRatio*stddev {
+			cs.unwind(pair, leg)
+		}
+		return
+	}
+
+	zScore := deviation / stddev
+	if math.Abs(zScore) <= cs.cfg.MinSpreadRatio {
+		return
+	}
+
+	// A positive deviation means Far is rich relative to Near, so sell
+	// Far / buy Near expecting reversion; a negative deviation is the
+	// reverse trade.
+	cs.enter(pair, spread, zScore > 0)
+}
+
+// lastPrice returns symbol's most recent trade price from the matching
+// engine's depth snapshot, or false if no trade has occurred on it yet.
+func (cs *CalSpread) lastPrice(symbol string) (float64, bool) {
+	depth := cs.orderManager.GetMarketDepth(symbol)
+	price, _ := depth["last_price"].(float64)
+	if price <= 0 {
+		return 0, false
+	}
+	return price, true
+}
+
+// enter submits the paired calendar-spread trade: longNear true buys
+// Near and sells Far, false does the reverse. Submission is skipped (and
+// recorded in the emitted event) if opening it would breach either leg's
+// configured position limit.
+func (cs *CalSpread) enter(pair Pair, spread float64, longNear bool) {
+	quantity := cs.cfg.Quantity
+	if quantity <= 0 {
+		quantity = 1.0
+	}
+
+	nearSide, farSide := core.OrderSideSell, core.OrderSideBuy
+	if longNear {
+		nearSide, farSide = core.OrderSideBuy, core.OrderSideSell
+	}
+
+	if !cs.withinLimit(pair.Near, nearSide, quantity) || !cs.withinLimit(pair.Far, farSide, quantity) {
+		cs.recordEvent(pair, map[string]interface{}{"message": "entry skipped: would breach a configured position limit"})
+		return
+	}
+
+	reqs := []core.OrderCreateRequest{
+		{ContractSymbol: pair.Near, Side: nearSide, OrderType: core.OrderTypeMarket, Quantity: quantity, Strategy: "calspread"},
+		{ContractSymbol: pair.Far, Side: farSide, OrderType: core.OrderTypeMarket, Quantity: quantity, Strategy: "calspread"},
+	}
+	results := cs.orderManager.BatchSubmitOrders(cs.userID, reqs)
+	for i, result := range results {
+		if !result.Success {
+			cs.recordEvent(pair, map[string]interface{}{"message": fmt.Sprintf("entry leg %d failed: %s", i, result.Error)})
+			return
+		}
+	}
+
+	cs.mu.Lock()
+	cs.open[pairKey(pair)] = &openLeg{
+		Near:        pair.Near,
+		Far:         pair.Far,
+		LongNear:    longNear,
+		Quantity:    quantity,
+		EntrySpread: spread,
+		OpenedAt:    time.Now(),
+	}
+	cs.mu.Unlock()
+}
+
+// unwind closes leg by trading both contracts in the opposite direction
+// from entry, clearing the pair's open-position guard once done so a new
+// entry can fire on the next deviation.
+func (cs *CalSpread) unwind(pair Pair, leg *openLeg) {
+	nearSide, farSide := core.OrderSideBuy, core.OrderSideSell
+	if leg.LongNear {
+		nearSide, farSide = core.OrderSideSell, core.OrderSideBuy
+	}
+
+	reqs := []core.OrderCreateRequest{
+		{ContractSymbol: leg.Near, Side: nearSide, OrderType: core.OrderTypeMarket, Quantity: leg.Quantity, Strategy: "calspread"},
+		{ContractSymbol: leg.Far, Side: farSide, OrderType: core.OrderTypeMarket, Quantity: leg.Quantity, Strategy: "calspread"},
+	}
+	results := cs.orderManager.BatchSubmitOrders(cs.userID, reqs)
+	for i, result := range results {
+		if !result.Success {
+			cs.recordEvent(pair, map[string]interface{}{"message": fmt.Sprintf("unwind leg %d failed: %s", i, result.Error)})
+			return
+		}
+	}
+
+	cs.mu.Lock()
+	delete(cs.open, pairKey(pair))
+	cs.mu.Unlock()
+}
+
+// withinLimit reports whether adding a quantity-sized order on side to
+// symbol would keep the resulting net position within Config.Limits for
+// that symbol. A symbol with no configured limit is unconstrained.
+func (cs *CalSpread) withinLimit(symbol string, side core.OrderSide, quantity float64) bool {
+	limit, ok := cs.cfg.Limits[symbol]
+	if !ok || limit <= 0 {
+		return true
+	}
+
+	delta := quantity
+	if side == core.OrderSideSell {
+		delta = -quantity
+	}
+	return math.Abs(cs.netPosition(symbol)+delta) <= limit
+}
+
+// netPosition returns CalSpread's current net quantity in symbol
+// (positive long, negative short, 0 if flat or not yet opened).
+func (cs *CalSpread) netPosition(symbol string) float64 {
+	var quantity float64
+	cs.db.Table("positions").
+		Joins("JOIN contracts ON contracts.id = positions.contract_id").
+		Where("positions.user_id = ? AND contracts.symbol = ?", cs.userID, symbol).
+		Select("positions.quantity").Row().Scan(&quantity)
+	return quantity
+}
+
+// recordEvent merges fields into pair's latest in-memory snapshot and
+// persists the full set of pairs' snapshots via jsonStorage.SaveAIAnalysis,
+// preserving every other pair's most recent state rather than losing it
+// to SaveAIAnalysis's whole-document overwrite.
+func (cs *CalSpread) recordEvent(pair Pair, fields map[string]interface{}) {
+	if cs.jsonStorage == nil {
+		return
+	}
+
+	key := pairKey(pair)
+	fields["pair"] = key
+	fields["time"] = time.Now().Format(time.RFC3339)
+
+	cs.mu.Lock()
+	cs.events[key] = fields
+	snapshot := make(map[string]interface{}, len(cs.events))
+	for k, v := range cs.events {
+		snapshot[k] = v
+	}
+	cs.mu.Unlock()
+
+	_ = cs.jsonStorage.SaveAIAnalysis(snapshot)
+}
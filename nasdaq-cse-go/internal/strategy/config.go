@@ -0,0 +1,70 @@
+package strategy
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry is one parsed `exchangeStrategies:` list item: its optional "id"
+// (defaulting to "<type>-<index>"), the exchange session it runs on
+// ("on"), the registered strategy type name (any other key in the YAML
+// map), and that key's value as strategy params.
+// Mirrors bbgo's `exchangeStrategies: - on: binance\n   grid: {...}` shape.
+type Entry struct {
+	ID     string
+	On     string
+	Type   string
+	Params map[string]interface{}
+}
+
+// Config is the top-level `exchangeStrategies:` document.
+type Config struct {
+	ExchangeStrategies []Entry
+}
+
+// LoadConfig reads and parses a strategy config YAML file at path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("strategy: failed to read config %s: %w", path, err)
+	}
+
+	var raw struct {
+		ExchangeStrategies []map[string]interface{} `yaml:"exchangeStrategies"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("strategy: failed to parse config %s: %w", path, err)
+	}
+
+	entries, err := parseEntries(raw.ExchangeStrategies)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{ExchangeStrategies: entries}, nil
+}
+
+func parseEntries(raw []map[string]interface{}) ([]Entry, error) {
+	entries := make([]Entry, 0, len(raw))
+	for i, item := range raw {
+		onValue, ok := item["on"]
+		if !ok {
+			return nil, fmt.Errorf("strategy: entry %d is missing an \"on\" exchange session", i)
+		}
+		on, _ := onValue.(string)
+
+		for key, value := range item {
+			if key == "on" || key == "id" {
+				continue
+			}
+			params, _ := value.(map[string]interface{})
+			id, _ := item["id"].(string)
+			if id == "" {
+				id = fmt.Sprintf("%s-%d", key, i)
+			}
+			entries = append(entries, Entry{ID: id, On: on, Type: key, Params: params})
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,346 @@
+// Package grid implements a symmetric grid market-making strategy: a
+// price ladder of buy levels below and sell levels above a reference
+// price, each level's fill triggering an opposing order one step away to
+// lock in Margin profit per round trip. Like calspread, it runs outside
+// the kline-driven Strategy interface in the parent strategy package; it
+// reacts to the matching engine's fill stream directly, since a grid only
+// needs to act on its own fills, not on every tick.
+package grid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/oms"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/storage"
+	"gorm.io/gorm"
+)
+
+// Config configures Grid's ladder.
+type Config struct {
+	Symbol     string
+	GridNum    int     // total number of levels, split evenly above/below the reference price
+	Margin     float64 // step ratio between adjacent levels, e.g. 0.01 for 1%
+	Quantity   float64 // quantity per level
+	UpperPrice float64
+	LowerPrice float64
+}
+
+// level is one rung of the ladder: Price/Side/Quantity describe the
+// resting order, and OrderID is whichever order (initial or replacement)
+// currently occupies it.
+type level struct {
+	Price    float64        `json:"price"`
+	Side     core.OrderSide `json:"side"`
+	Quantity float64        `json:"quantity"`
+	OrderID  string         `json:"order_id"`
+}
+
+// Grid runs a symmetric grid market-making strategy on top of
+// OrderManager: Config.GridNum/2 buy levels below the reference price and
+// Config.GridNum/2 sell levels above it, each rebuilt one step further
+// out every time its order fills, so a round trip always locks in
+// Config.Margin.
+type Grid struct {
+	orderManager *oms.OrderManager
+	jsonStorage  *storage.JSONStorage
+	db           *gorm.DB
+	userID       uint
+	cfg          Config
+
+	mu     sync.Mutex
+	levels map[string]*level // keyed by OrderID
+}
+
+// NewGrid creates a Grid trading userID's account. jsonStorage may be
+// nil, in which case grid state persistence is skipped.
+func NewGrid(orderManager *oms.OrderManager, db *gorm.DB, jsonStorage *storage.JSONStorage, userID uint, cfg Config) *Grid {
+	return &Grid{
+		orderManager: orderManager,
+		jsonStorage:  jsonStorage,
+		db:           db,
+		userID:       userID,
+		cfg:          cfg,
+		levels:       make(map[string]*level),
+	}
+}
+
+// Start computes the initial ladder around the current reference price
+// and submits it in one batch via BatchSubmitOrders. Call LoadState
+// first if resuming after a restart: when levels are already loaded, Start
+// submits nothing, so it doesn't lay a fresh ladder on top of orders that
+// are already resting.
+func (g *Grid) Start() error {
+	g.mu.Lock()
+	resumed := len(g.levels) > 0
+	g.mu.Unlock()
+	if resumed {
+		return nil
+	}
+
+	ref, ok := g.referencePrice()
+	if !ok {
+		return fmt.Errorf("grid: no reference price available for %s yet", g.cfg.Symbol)
+	}
+
+	reqs := g.buildLadder(ref)
+	results := g.orderManager.BatchSubmitOrders(g.userID, reqs)
+
+	g.mu.Lock()
+	for i, result := range results {
+		if !result.Success {
+			continue
+		}
+		g.levels[result.OrderID] = &level{
+			Price:    *reqs[i].Price,
+			Side:     reqs[i].Side,
+			Quantity: reqs[i].Quantity,
+			OrderID:  result.OrderID,
+		}
+	}
+	g.mu.Unlock()
+
+	return g.persist()
+}
+
+// buildLadder computes Config.GridNum/2 buy levels below ref and
+// Config.GridNum/2 sell levels above it, stepped geometrically by
+// Config.Margin and clamped to [LowerPrice, UpperPrice].
+func (g *Grid) buildLadder(ref float64) []core.OrderCreateRequest {
+	half := g.cfg.GridNum / 2
+	reqs := make([]core.OrderCreateRequest, 0, half*2)
+
+	for i := 1; i <= half; i++ {
+		if buyPrice := ref * math.Pow(1-g.cfg.Margin, float64(i)); buyPrice >= g.cfg.LowerPrice {
+			price := buyPrice
+			reqs = append(reqs, core.OrderCreateRequest{
+				ContractSymbol: g.cfg.Symbol,
+				Side:           core.OrderSideBuy,
+				OrderType:      core.OrderTypeLimit,
+				Quantity:       g.cfg.Quantity,
+				Price:          &price,
+				Strategy:       "grid",
+			})
+		}
+		if sellPrice := ref * math.Pow(1+g.cfg.Margin, float64(i)); sellPrice <= g.cfg.UpperPrice {
+			price := sellPrice
+			reqs = append(reqs, core.OrderCreateRequest{
+				ContractSymbol: g.cfg.Symbol,
+				Side:           core.OrderSideSell,
+				OrderType:      core.OrderTypeLimit,
+				Quantity:       g.cfg.Quantity,
+				Price:          &price,
+				Strategy:       "grid",
+			})
+		}
+	}
+	return reqs
+}
+
+// referencePrice returns Config.Symbol's last trade price, or false if no
+// trade has happened on it yet.
+func (g *Grid) referencePrice() (float64, bool) {
+	depth := g.orderManager.GetMarketDepth(g.cfg.Symbol)
+	price, _ := depth["last_price"].(float64)
+	if price <= 0 {
+		return 0, false
+	}
+	return price, true
+}
+
+// Run drains the matching engine's fill stream until ctx is canceled,
+// replacing each grid level's order with an opposing one step away the
+// instant it fills. It's meant to be started with `go` alongside Start.
+func (g *Grid) Run(ctx context.Context) {
+	fills := g.orderManager.FillCh()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case trade, ok := <-fills:
+			if !ok {
+				return
+			}
+			g.onFill(trade)
+		}
+	}
+}
+
+// onFill checks whether trade closed one of this grid's resting levels
+// and, if so, replaces it with an opposing order one Margin step further
+// out, locking in the round trip's profit. Trades belonging to other
+// symbols or other strategies' orders are ignored.
+func (g *Grid) onFill(trade core.Trade) {
+	g.mu.Lock()
+	filled, filledOrderID := g.matchLevel(trade)
+	if filled != nil {
+		delete(g.levels, filledOrderID)
+	}
+	g.mu.Unlock()
+
+	if filled == nil {
+		return
+	}
+
+	opposingSide, opposingPrice := core.OrderSideSell, filled.Price*(1+g.cfg.Margin)
+	if filled.Side == core.OrderSideSell {
+		opposingSide, opposingPrice = core.OrderSideBuy, filled.Price*(1-g.cfg.Margin)
+	}
+	if opposingSide == core.OrderSideBuy && opposingPrice < g.cfg.LowerPrice {
+		return
+	}
+	if opposingSide == core.OrderSideSell && opposingPrice > g.cfg.UpperPrice {
+		return
+	}
+
+	result := g.orderManager.SubmitOrder(g.userID, core.OrderCreateRequest{
+		ContractSymbol: g.cfg.Symbol,
+		Side:           opposingSide,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       filled.Quantity,
+		Price:          &opposingPrice,
+		Strategy:       "grid",
+	})
+	if !result.Success {
+		return
+	}
+
+	g.mu.Lock()
+	g.levels[result.OrderID] = &level{Price: opposingPrice, Side: opposingSide, Quantity: filled.Quantity, OrderID: result.OrderID}
+	g.mu.Unlock()
+
+	_ = g.persist()
+}
+
+// matchLevel returns the grid level (and its order ID) that trade
+// closed, if either side of trade references one of g's resting orders.
+// Callers must hold g.mu.
+func (g *Grid) matchLevel(trade core.Trade) (*level, string) {
+	if trade.BuyOrderID != nil {
+		if lvl, ok := g.levels[*trade.BuyOrderID]; ok {
+			return lvl, *trade.BuyOrderID
+		}
+	}
+	if trade.SellOrderID != nil {
+		if lvl, ok := g.levels[*trade.SellOrderID]; ok {
+			return lvl, *trade.SellOrderID
+		}
+	}
+	return nil, ""
+}
+
+// Flatten cancels every resting grid order and, if closePosition is
+// true, market-closes the accumulated net position in Config.Symbol,
+// tagged Strategy="grid_flatten" so the flatten itself is distinguishable
+// from ordinary grid round trips in trade history.
+func (g *Grid) Flatten(closePosition bool) error {
+	g.mu.Lock()
+	orderIDs := make([]string, 0, len(g.levels))
+	for id := range g.levels {
+		orderIDs = append(orderIDs, id)
+	}
+	g.levels = make(map[string]*level)
+	g.mu.Unlock()
+
+	for _, orderID := range orderIDs {
+		g.orderManager.CancelOrder(orderID, g.userID)
+	}
+	if err := g.persist(); err != nil {
+		return err
+	}
+	if !closePosition {
+		return nil
+	}
+
+	quantity := g.netPosition()
+	if quantity == 0 {
+		return nil
+	}
+	side := core.OrderSideSell
+	if quantity < 0 {
+		side = core.OrderSideBuy
+	}
+	result := g.orderManager.SubmitOrder(g.userID, core.OrderCreateRequest{
+		ContractSymbol: g.cfg.Symbol,
+		Side:           side,
+		OrderType:      core.OrderTypeMarket,
+		Quantity:       math.Abs(quantity),
+		Strategy:       "grid_flatten",
+	})
+	if !result.Success {
+		return fmt.Errorf("grid: failed to flatten position: %s", result.Error)
+	}
+	return nil
+}
+
+// netPosition returns Grid's current net quantity in Config.Symbol
+// (positive long, negative short, 0 if flat or not yet opened).
+func (g *Grid) netPosition() float64 {
+	var quantity float64
+	g.db.Table("positions").
+		Joins("JOIN contracts ON contracts.id = positions.contract_id").
+		Where("positions.user_id = ? AND contracts.symbol = ?", g.userID, g.cfg.Symbol).
+		Select("positions.quantity").Row().Scan(&quantity)
+	return quantity
+}
+
+// persist writes the grid's current open levels through jsonStorage so a
+// restart can rebuild in-memory state via LoadState instead of
+// resubmitting (and orphaning) a fresh ladder on top of already-resting
+// orders. A nil jsonStorage is a no-op.
+func (g *Grid) persist() error {
+	if g.jsonStorage == nil {
+		return nil
+	}
+
+	g.mu.Lock()
+	levels := make([]*level, 0, len(g.levels))
+	for _, lvl := range g.levels {
+		levels = append(levels, lvl)
+	}
+	g.mu.Unlock()
+
+	return g.jsonStorage.SaveGridState(map[string]interface{}{g.cfg.Symbol: levels})
+}
+
+// LoadState restores g's in-memory open-level bookkeeping for
+// Config.Symbol from a previous persist call. Call it before Start/Run
+// when resuming after a restart; it does not resubmit or cancel
+// anything itself. A nil jsonStorage is a no-op.
+func (g *Grid) LoadState() error {
+	if g.jsonStorage == nil {
+		return nil
+	}
+
+	state, err := g.jsonStorage.LoadGridState()
+	if err != nil {
+		return err
+	}
+	raw, ok := state[g.cfg.Symbol]
+	if !ok {
+		return nil
+	}
+
+	// LoadGridState round-trips through a generic map[string]interface{},
+	// so raw's nested levels decoded as generic maps rather than *level -
+	// re-marshal/unmarshal through JSON to get back concrete values.
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("grid: failed to re-marshal persisted state: %w", err)
+	}
+	var levels []*level
+	if err := json.Unmarshal(data, &levels); err != nil {
+		return fmt.Errorf("grid: failed to parse persisted state: %w", err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, lvl := range levels {
+		g.levels[lvl.OrderID] = lvl
+	}
+	return nil
+}
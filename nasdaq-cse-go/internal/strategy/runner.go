@@ -0,0 +1,185 @@
+package strategy
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/exchange"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/marketdata"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/oms"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/rms"
+	"gorm.io/gorm"
+)
+
+// State is a registered strategy's current run state.
+type State string
+
+const (
+	StateStopped State = "stopped"
+	StateRunning State = "running"
+)
+
+// instance is a registered strategy plus its runtime bookkeeping.
+type instance struct {
+	id       string
+	userID   uint
+	strategy Strategy
+	state    State
+	stop     chan struct{}
+}
+
+// StrategyRunner routes marketData updates into registered strategies and,
+// in live mode, submits the orders they decide to place through
+// riskManager and orderManager. Backtest mode replays historical klines
+// instead of live ticks and settles fills against an in-memory ledger, so
+// it never touches orderManager.
+type StrategyRunner struct {
+	marketData   *marketdata.MarketDataService
+	orderManager *oms.OrderManager
+	riskManager  *rms.RiskManager
+	db           *gorm.DB
+
+	mutex      sync.Mutex
+	strategies map[string]*instance
+}
+
+// NewStrategyRunner creates a StrategyRunner wired to the simulator's
+// shared market data, order management, and risk services.
+func NewStrategyRunner(marketData *marketdata.MarketDataService, orderManager *oms.OrderManager, riskManager *rms.RiskManager, db *gorm.DB) *StrategyRunner {
+	return &StrategyRunner{
+		marketData:   marketData,
+		orderManager: orderManager,
+		riskManager:  riskManager,
+		db:           db,
+		strategies:   make(map[string]*instance),
+	}
+}
+
+// Register makes strategy runnable under id, owned by userID. It replaces
+// any existing stopped registration under the same id.
+func (r *StrategyRunner) Register(id string, userID uint, s Strategy) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if existing, ok := r.strategies[id]; ok && existing.state == StateRunning {
+		return fmt.Errorf("strategy: %s is already running", id)
+	}
+	r.strategies[id] = &instance{id: id, userID: userID, strategy: s, state: StateStopped}
+	return nil
+}
+
+// Start begins routing live market data ticks for id's subscribed symbols
+// into its Strategy.OnKline, converting each tick into a single-price
+// kline the way a 1-tick timeframe would.
+func (r *StrategyRunner) Start(id string) error {
+	r.mutex.Lock()
+	inst, ok := r.strategies[id]
+	if !ok {
+		r.mutex.Unlock()
+		return fmt.Errorf("strategy: %s is not registered", id)
+	}
+	if inst.state == StateRunning {
+		r.mutex.Unlock()
+		return fmt.Errorf("strategy: %s is already running", id)
+	}
+	inst.state = StateRunning
+	inst.stop = make(chan struct{})
+	r.mutex.Unlock()
+
+	inst.strategy.Init(&StrategyContext{UserID: inst.userID, id: inst.id, runner: r})
+
+	for _, symbol := range inst.strategy.Subscribe() {
+		ticks, err := r.marketData.SubscribeSymbol(symbol)
+		if err != nil {
+			r.Stop(id)
+			return fmt.Errorf("strategy: failed to subscribe %s to %s: %w", id, symbol, err)
+		}
+		go r.feedKlines(inst, symbol, ticks)
+	}
+	return nil
+}
+
+// Stop halts routing for id. It's safe to call on an already-stopped id.
+func (r *StrategyRunner) Stop(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	inst, ok := r.strategies[id]
+	if !ok {
+		return fmt.Errorf("strategy: %s is not registered", id)
+	}
+	if inst.state == StateRunning {
+		close(inst.stop)
+		inst.state = StateStopped
+	}
+	return nil
+}
+
+func (r *StrategyRunner) feedKlines(inst *instance, symbol string, ticks <-chan marketdata.Tick) {
+	for {
+		select {
+		case <-inst.stop:
+			return
+		case tick, ok := <-ticks:
+			if !ok {
+				return
+			}
+			kline := exchange.Kline{
+				Symbol:    symbol,
+				Interval:  "tick",
+				Open:      tick.Last,
+				High:      tick.Last,
+				Low:       tick.Last,
+				Close:     tick.Last,
+				Volume:    float64(tick.Volume),
+				OpenTime:  tick.Timestamp,
+				CloseTime: tick.Timestamp,
+				Closed:    true,
+			}
+			inst.strategy.OnKline(kline)
+		}
+	}
+}
+
+// submitOrder is the live-mode implementation of StrategyContext.SubmitOrder:
+// it applies the same pre-trade risk check and order routing a manual
+// order submission would.
+func (r *StrategyRunner) submitOrder(ctx *StrategyContext, orderRequest core.OrderCreateRequest) (oms.SubmitOrderResult, error) {
+	if orderRequest.Strategy == "" {
+		orderRequest.Strategy = ctx.id
+	}
+
+	riskCheck := r.riskManager.CheckPreTradeRisk(ctx.UserID, orderRequest)
+	if allowed, _ := riskCheck["allowed"].(bool); !allowed {
+		return oms.SubmitOrderResult{}, fmt.Errorf("strategy: order rejected by risk check: %v", riskCheck["reason"])
+	}
+
+	result := r.orderManager.SubmitOrder(ctx.UserID, orderRequest)
+	if !result.Success {
+		return result, fmt.Errorf("strategy: order submission failed: %s", result.Error)
+	}
+
+	for _, trade := range result.Trades {
+		price, _ := trade["price"].(float64)
+		quantity, _ := trade["quantity"].(float64)
+		r.notifyTrade(ctx.id, core.Trade{
+			Price:     price,
+			Quantity:  quantity,
+			TradeTime: time.Now(),
+		})
+	}
+	return result, nil
+}
+
+// notifyTrade calls id's strategy back via OnTrade, if it's still running.
+func (r *StrategyRunner) notifyTrade(id string, trade core.Trade) {
+	r.mutex.Lock()
+	inst, ok := r.strategies[id]
+	r.mutex.Unlock()
+
+	if ok && inst.state == StateRunning {
+		inst.strategy.OnTrade(trade)
+	}
+}
@@ -0,0 +1,84 @@
+// Package strategy hosts user-written trading strategies, following
+// bbgo's single-strategy-per-session config model: a Strategy is a small
+// plugin that reacts to kline/trade/order events and submits orders
+// through the StrategyContext it's given at Init, rather than reaching
+// into oms/rms directly.
+package strategy
+
+import (
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/exchange"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/oms"
+)
+
+// Strategy is a user-pluggable trading algorithm. Concrete strategies
+// register a Factory under a name via Register, the way database/sql
+// drivers register themselves, so the runtime itself ships with none.
+type Strategy interface {
+	// Subscribe returns the symbols this strategy wants klines for.
+	Subscribe() []string
+	// Init is called once, before the runner starts feeding the strategy
+	// events, giving it a handle to submit orders.
+	Init(ctx *StrategyContext)
+	// OnKline is called for every new kline on a subscribed symbol.
+	OnKline(kline exchange.Kline)
+	// OnTrade is called for every fill one of the strategy's own orders
+	// produced.
+	OnTrade(trade core.Trade)
+	// OnOrderUpdate is called whenever one of the strategy's orders
+	// changes status.
+	OnOrderUpdate(order core.Order)
+}
+
+// Factory constructs a Strategy from the params map parsed out of its
+// YAML config entry.
+type Factory func(params map[string]interface{}) (Strategy, error)
+
+var factories = make(map[string]Factory)
+
+// Register makes a strategy type available to NewStrategy under name (the
+// Type of a LoadConfig Entry). Concrete strategy packages call this from
+// an init() func.
+func Register(name string, factory Factory) {
+	factories[name] = factory
+}
+
+// NewStrategy builds the named strategy type from params, the way
+// NewConnector switches on exchange.SessionConfig.Exchange.
+func NewStrategy(name string, params map[string]interface{}) (Strategy, error) {
+	factory, ok := factories[name]
+	if !ok {
+		return nil, &UnknownStrategyError{Name: name}
+	}
+	return factory(params)
+}
+
+// UnknownStrategyError is returned by NewStrategy when name hasn't been
+// registered.
+type UnknownStrategyError struct {
+	Name string
+}
+
+func (e *UnknownStrategyError) Error() string {
+	return "strategy: unknown strategy type " + e.Name
+}
+
+// StrategyContext is the handle a Strategy uses to act on the data it's
+// handed, keeping strategies decoupled from oms/rms package internals.
+type StrategyContext struct {
+	UserID uint
+
+	id     string
+	runner *StrategyRunner
+	ledger *backtestLedger // non-nil only when running inside Backtest
+}
+
+// SubmitOrder runs orderRequest through the runner's risk check and order
+// manager in live mode, or settles it against the in-memory backtest
+// ledger when running inside Backtest.
+func (c *StrategyContext) SubmitOrder(orderRequest core.OrderCreateRequest) (oms.SubmitOrderResult, error) {
+	if c.ledger != nil {
+		return c.ledger.apply(orderRequest), nil
+	}
+	return c.runner.submitOrder(c, orderRequest)
+}
@@ -0,0 +1,275 @@
+// Package xmaker implements a cross-exchange market-making strategy: it
+// mirrors a "source" venue's gold price onto the local simulator's order
+// book as a layered ladder of bid/ask quotes around it, then hedges
+// whatever net position those quotes accumulate back out on the source
+// venue. Like calspread and grid, it reacts to its own price/fill events
+// directly rather than the kline-driven Strategy interface in the parent
+// strategy package, since quoting needs tight control over cancel/replace
+// timing that a single OnKline callback doesn't give it.
+package xmaker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/marketdata"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/oms"
+	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// checkInterval bounds how often Run checks its resting quotes against
+// PriceUpdateTimeout when no new source tick has arrived to trigger the
+// check itself.
+const checkInterval = 1 * time.Second
+
+// Config is xmaker's YAML config document.
+type Config struct {
+	Symbol             string  `yaml:"symbol"`
+	Margin             float64 `yaml:"margin"`             // half-spread around the source price, e.g. 0.001 for 10bps
+	NumLayers          int     `yaml:"numLayers"`          // quote pairs per side
+	PipOffset          float64 `yaml:"pipOffset"`          // price gap between successive layers
+	Quantity           float64 `yaml:"quantity"`           // quantity per layer
+	MoveThreshold      float64 `yaml:"moveThreshold"`      // re-quote once the source price has moved by more than this since the last quote
+	PriceUpdateTimeout string  `yaml:"priceUpdateTimeout"` // re-quote after this long even if MoveThreshold hasn't been crossed, e.g. "30s"
+	CoveredPosition    float64 `yaml:"coveredPosition"`    // cap on the quantity hedged on HedgeVenue per hedge call
+	HedgeVenue         string  `yaml:"hedgeVenue"`
+	OrdersPerSecond    float64 `yaml:"ordersPerSecond"` // rate.Limiter rate protecting order submission
+	Burst              int     `yaml:"burst"`
+
+	priceUpdateTimeout time.Duration
+}
+
+// LoadConfig reads and parses xmaker's YAML config file at path, filling
+// in defaults for anything left unset.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("xmaker: failed to read config %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("xmaker: failed to parse config %s: %w", path, err)
+	}
+
+	if cfg.NumLayers <= 0 {
+		cfg.NumLayers = 1
+	}
+	if cfg.OrdersPerSecond <= 0 {
+		cfg.OrdersPerSecond = 5
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = cfg.NumLayers * 2
+	}
+	cfg.priceUpdateTimeout = 30 * time.Second
+	if cfg.PriceUpdateTimeout != "" {
+		cfg.priceUpdateTimeout, err = time.ParseDuration(cfg.PriceUpdateTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("xmaker: invalid priceUpdateTimeout %q: %w", cfg.PriceUpdateTimeout, err)
+		}
+	}
+	return &cfg, nil
+}
+
+// HedgeExecutor is the pluggable seam Maker uses to offset its
+// accumulated local position on Config.HedgeVenue, the same shape as
+// rms.CrossHedger's HedgeExecutor.
+type HedgeExecutor interface {
+	SubmitHedgeOrder(venue, contractSymbol string, side core.OrderSide, quantity, price float64) error
+}
+
+// Maker runs a cross-exchange market-making loop: it quotes a layered
+// bid/ask ladder on the local simulator's book around Config.Symbol's
+// latest price from source, re-quoting whenever price has moved more
+// than Config.MoveThreshold or Config.PriceUpdateTimeout has elapsed, and
+// hedges whatever net position the quotes accumulate back out on
+// Config.HedgeVenue via hedger.
+type Maker struct {
+	orderManager *oms.OrderManager
+	db           *gorm.DB
+	source       marketdata.PriceSource
+	hedger       HedgeExecutor
+	limiter      *rate.Limiter
+	userID       uint
+	cfg          Config
+
+	mu              sync.Mutex
+	quoteOrderIDs   []string
+	lastSourcePrice float64
+	lastQuotePrice  float64
+	lastQuoteTime   time.Time
+}
+
+// NewMaker creates a Maker trading userID's account, quoting off of
+// source and hedging through hedger.
+func NewMaker(orderManager *oms.OrderManager, db *gorm.DB, source marketdata.PriceSource, hedger HedgeExecutor, userID uint, cfg Config) *Maker {
+	return &Maker{
+		orderManager: orderManager,
+		db:           db,
+		source:       source,
+		hedger:       hedger,
+		limiter:      rate.NewLimiter(rate.Limit(cfg.OrdersPerSecond), cfg.Burst),
+		userID:       userID,
+		cfg:          cfg,
+	}
+}
+
+// Run subscribes to source's feed for Config.Symbol and quotes/hedges off
+// of it until ctx is canceled. It's meant to be started with `go`.
+func (m *Maker) Run(ctx context.Context) error {
+	ticks, err := m.source.Subscribe(m.cfg.Symbol)
+	if err != nil {
+		return fmt.Errorf("xmaker: failed to subscribe to source feed for %s: %w", m.cfg.Symbol, err)
+	}
+
+	checkTicker := time.NewTicker(checkInterval)
+	defer checkTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case tick, ok := <-ticks:
+			if !ok {
+				return nil
+			}
+			m.onPriceUpdate(ctx, tick.Last)
+			m.hedgeIfNeeded(tick.Last)
+		case <-checkTicker.C:
+			m.refreshIfStale(ctx)
+		}
+	}
+}
+
+// onPriceUpdate records price as the latest known source price and
+// re-quotes if it has moved more than Config.MoveThreshold since the
+// current quotes were placed.
+func (m *Maker) onPriceUpdate(ctx context.Context, price float64) {
+	m.mu.Lock()
+	m.lastSourcePrice = price
+	moved := m.lastQuotePrice == 0 || math.Abs(price-m.lastQuotePrice) > m.cfg.MoveThreshold
+	m.mu.Unlock()
+
+	if moved {
+		m.requote(ctx, price)
+	}
+}
+
+// refreshIfStale re-quotes at the last known source price if the current
+// quotes have been resting longer than Config.PriceUpdateTimeout, even
+// though price hasn't moved enough on its own to trigger a re-quote.
+func (m *Maker) refreshIfStale(ctx context.Context) {
+	m.mu.Lock()
+	price := m.lastSourcePrice
+	stale := !m.lastQuoteTime.IsZero() && time.Since(m.lastQuoteTime) > m.cfg.priceUpdateTimeout
+	m.mu.Unlock()
+
+	if stale && price > 0 {
+		m.requote(ctx, price)
+	}
+}
+
+// requote cancels the current quote ladder and lays a fresh one around
+// price, rate-limited by Config.OrdersPerSecond/Burst.
+func (m *Maker) requote(ctx context.Context, price float64) {
+	m.mu.Lock()
+	previousIDs := m.quoteOrderIDs
+	m.mu.Unlock()
+	for _, orderID := range previousIDs {
+		m.orderManager.CancelOrder(orderID, m.userID)
+	}
+
+	reqs := m.buildLadder(price)
+	if err := m.limiter.WaitN(ctx, len(reqs)); err != nil {
+		return
+	}
+	results := m.orderManager.BatchSubmitOrders(m.userID, reqs)
+
+	ids := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.Success {
+			ids = append(ids, result.OrderID)
+		}
+	}
+
+	m.mu.Lock()
+	m.quoteOrderIDs = ids
+	m.lastQuotePrice = price
+	m.lastQuoteTime = time.Now()
+	m.mu.Unlock()
+}
+
+// buildLadder lays Config.NumLayers bid/ask pairs around price: the first
+// layer sits at Config.Margin's half-spread from price, and each later
+// layer one Config.PipOffset further out than the last.
+func (m *Maker) buildLadder(price float64) []core.OrderCreateRequest {
+	reqs := make([]core.OrderCreateRequest, 0, m.cfg.NumLayers*2)
+	for i := 0; i < m.cfg.NumLayers; i++ {
+		offset := float64(i) * m.cfg.PipOffset
+		bidPrice := price*(1-m.cfg.Margin) - offset
+		askPrice := price*(1+m.cfg.Margin) + offset
+
+		reqs = append(reqs,
+			core.OrderCreateRequest{ContractSymbol: m.cfg.Symbol, Side: core.OrderSideBuy, OrderType: core.OrderTypeLimit, Quantity: m.cfg.Quantity, Price: &bidPrice, Strategy: "xmaker"},
+			core.OrderCreateRequest{ContractSymbol: m.cfg.Symbol, Side: core.OrderSideSell, OrderType: core.OrderTypeLimit, Quantity: m.cfg.Quantity, Price: &askPrice, Strategy: "xmaker"},
+		)
+	}
+	return reqs
+}
+
+// hedgeIfNeeded offsets Maker's current net position in Config.Symbol on
+// Config.HedgeVenue at price, capping the hedged quantity at
+// Config.CoveredPosition per call.
+func (m *Maker) hedgeIfNeeded(price float64) {
+	quantity := m.netPosition()
+	if quantity == 0 {
+		return
+	}
+
+	hedgeQty := math.Abs(quantity)
+	if m.cfg.CoveredPosition > 0 && hedgeQty > m.cfg.CoveredPosition {
+		hedgeQty = m.cfg.CoveredPosition
+	}
+
+	// Hedge offsets the local position's direction: long locally is
+	// hedged with a sell on the source venue, and vice versa.
+	side := core.OrderSideSell
+	if quantity < 0 {
+		side = core.OrderSideBuy
+	}
+
+	_ = m.hedger.SubmitHedgeOrder(m.cfg.HedgeVenue, m.cfg.Symbol, side, hedgeQty, price)
+}
+
+// netPosition returns Maker's current net quantity in Config.Symbol
+// (positive long, negative short, 0 if flat or not yet opened).
+func (m *Maker) netPosition() float64 {
+	var quantity float64
+	m.db.Table("positions").
+		Joins("JOIN contracts ON contracts.id = positions.contract_id").
+		Where("positions.user_id = ? AND contracts.symbol = ?", m.userID, m.cfg.Symbol).
+		Select("positions.quantity").Row().Scan(&quantity)
+	return quantity
+}
+
+// Stop cancels every currently resting quote without touching whatever
+// net position has already accumulated; hedging is left to run its
+// course via hedgeIfNeeded on subsequent ticks, or to a caller who wants
+// to flatten the position directly through OrderManager.
+func (m *Maker) Stop() {
+	m.mu.Lock()
+	ids := m.quoteOrderIDs
+	m.quoteOrderIDs = nil
+	m.mu.Unlock()
+
+	for _, orderID := range ids {
+		m.orderManager.CancelOrder(orderID, m.userID)
+	}
+}
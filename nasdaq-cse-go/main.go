@@ -8,12 +8,20 @@ import (
 )
 
 func main() {
-	// Change to the project directory and run the server
-	cmd := exec.Command("go", "run", "./cmd/server")
+	// "nasdaq-cse-go backtest --config atrpin.yaml" replays history through
+	// a strategy instead of starting the live server.
+	target := "./cmd/server"
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "backtest" {
+		target = "./cmd/backtest"
+		args = args[1:]
+	}
+
+	cmd := exec.Command("go", append([]string{"run", target}, args...)...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	
+
 	if err := cmd.Run(); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+		log.Fatalf("Failed to start: %v", err)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,181 @@
+// Package backtest replays historical ticks from a marketdata.TickStore
+// through a strategy.Strategy, filling its orders against a simulated book
+// built from the recorded bid/ask, and reports the resulting PnL.
+package backtest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/marketdata"
+)
+
+// Fill is one order fill the SimulatedBroker produced during a run.
+type Fill struct {
+	Timestamp time.Time
+	Side      string // "BUY" or "SELL"
+	Quantity  float64
+	Price     float64
+	Fee       float64
+	Maker     bool
+}
+
+// SimulatedBroker implements strategy.OrderSender against replayed ticks
+// instead of a real FIX session: MARKET orders fill immediately at the
+// current bid/ask plus SlippageBps (taker), mirroring the fill logic in
+// communication's simulateExecutionReport, while LIMIT orders fill at
+// their specified price (maker) since this harness has no resting-order
+// book to cross against.
+type SimulatedBroker struct {
+	makerFeeRate float64
+	takerFeeRate float64
+	slippageBps  float64
+
+	mutex       sync.Mutex
+	lastTick    marketdata.Tick
+	position    float64
+	avgCost     float64
+	realizedPnL float64
+	fills       []Fill
+}
+
+// NewSimulatedBroker creates a SimulatedBroker charging makerFeeRate on
+// LIMIT fills and takerFeeRate plus slippageBps (basis points applied to
+// price) on MARKET fills.
+func NewSimulatedBroker(makerFeeRate, takerFeeRate, slippageBps float64) *SimulatedBroker {
+	return &SimulatedBroker{
+		makerFeeRate: makerFeeRate,
+		takerFeeRate: takerFeeRate,
+		slippageBps:  slippageBps,
+	}
+}
+
+// updateMarket records the latest tick so SendOrder and equity have a
+// current bid/ask to fill and mark against.
+func (b *SimulatedBroker) updateMarket(tick marketdata.Tick) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.lastTick = tick
+}
+
+// SendOrder fills orderData against the last recorded tick, satisfying
+// strategy.OrderSender the same way communication.CommunicationManager
+// does for live trading.
+func (b *SimulatedBroker) SendOrder(orderData map[string]interface{}) (string, error) {
+	side, _ := orderData["side"].(string)
+	orderType, _ := orderData["order_type"].(string)
+	quantity, _ := orderData["quantity"].(float64)
+	if quantity <= 0 {
+		return "", fmt.Errorf("backtest: order quantity must be positive, got %v", orderData["quantity"])
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.lastTick.Timestamp.IsZero() {
+		return "", fmt.Errorf("backtest: no market data available to fill order yet")
+	}
+
+	var fillPrice, feeRate float64
+	maker := orderType == "LIMIT"
+	if maker {
+		feeRate = b.makerFeeRate
+		if price, ok := orderData["price"].(float64); ok {
+			fillPrice = price
+		} else {
+			fillPrice = (b.lastTick.Bid + b.lastTick.Ask) / 2
+		}
+	} else {
+		feeRate = b.takerFeeRate
+		slippage := b.slippageBps / 10000.0
+		if side == "SELL" {
+			fillPrice = b.lastTick.Bid * (1 - slippage)
+		} else {
+			fillPrice = b.lastTick.Ask * (1 + slippage)
+		}
+	}
+
+	signedQty := quantity
+	if side == "SELL" {
+		signedQty = -quantity
+	}
+	fee := fillPrice * quantity * feeRate
+
+	b.applyFill(signedQty, fillPrice)
+	b.realizedPnL -= fee
+
+	b.fills = append(b.fills, Fill{
+		Timestamp: b.lastTick.Timestamp,
+		Side:      side,
+		Quantity:  quantity,
+		Price:     fillPrice,
+		Fee:       fee,
+		Maker:     maker,
+	})
+
+	return fmt.Sprintf("BT-%d", len(b.fills)), nil
+}
+
+// applyFill updates position/average cost/realized PnL using a simple
+// average-cost model: a fill that reduces (or flips) the position realizes
+// PnL against the prior average cost; a fill that extends it rolls into a
+// new weighted average cost. Callers must hold b.mutex.
+func (b *SimulatedBroker) applyFill(signedQty, price float64) {
+	switch {
+	case b.position == 0 || sameSign(b.position, signedQty):
+		newPosition := b.position + signedQty
+		b.avgCost = (b.avgCost*absFloat(b.position) + price*absFloat(signedQty)) / absFloat(newPosition)
+		b.position = newPosition
+
+	case absFloat(signedQty) <= absFloat(b.position):
+		closingQty := absFloat(signedQty)
+		if b.position > 0 {
+			b.realizedPnL += closingQty * (price - b.avgCost)
+		} else {
+			b.realizedPnL += closingQty * (b.avgCost - price)
+		}
+		b.position += signedQty
+
+	default:
+		closingQty := absFloat(b.position)
+		if b.position > 0 {
+			b.realizedPnL += closingQty * (price - b.avgCost)
+		} else {
+			b.realizedPnL += closingQty * (b.avgCost - price)
+		}
+		b.position += signedQty
+		b.avgCost = price
+	}
+}
+
+// unrealizedPnL marks the current position to the last recorded tick.
+func (b *SimulatedBroker) unrealizedPnL() float64 {
+	if b.position == 0 {
+		return 0
+	}
+	mark := (b.lastTick.Bid + b.lastTick.Ask) / 2
+	if b.position > 0 {
+		return b.position * (mark - b.avgCost)
+	}
+	return -b.position * (b.avgCost - mark)
+}
+
+// equity returns realized + unrealized PnL, the running value a drawdown/
+// Sharpe calculation marks against.
+func (b *SimulatedBroker) equity() float64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.realizedPnL + b.unrealizedPnL()
+}
+
+func sameSign(a, b float64) bool {
+	return (a >= 0) == (b >= 0)
+}
+
+func absFloat(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
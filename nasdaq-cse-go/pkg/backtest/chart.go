@@ -0,0 +1,115 @@
+package backtest
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+)
+
+// SaveCumulativePnLChart renders report.EquityCurve as a simple line chart
+// and writes it to path as a PNG, the same rough shape as
+// marketdata.ChartGenerator's chart data but rasterized for a CLI report
+// instead of served to the dashboard.
+func SaveCumulativePnLChart(report Report, path string) error {
+	const width, height = 800, 400
+	const margin = 20
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	background := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, background)
+		}
+	}
+
+	if len(report.EquityCurve) < 2 {
+		return writePNG(img, path)
+	}
+
+	minEquity, maxEquity := report.EquityCurve[0].Equity, report.EquityCurve[0].Equity
+	for _, point := range report.EquityCurve {
+		if point.Equity < minEquity {
+			minEquity = point.Equity
+		}
+		if point.Equity > maxEquity {
+			maxEquity = point.Equity
+		}
+	}
+	if maxEquity == minEquity {
+		maxEquity = minEquity + 1
+	}
+
+	line := color.RGBA{R: 30, G: 110, B: 200, A: 255}
+	plotWidth := float64(width - 2*margin)
+	plotHeight := float64(height - 2*margin)
+	n := len(report.EquityCurve)
+
+	prevX, prevY := margin, plotY(report.EquityCurve[0].Equity, minEquity, maxEquity, plotHeight, margin)
+	for i := 1; i < n; i++ {
+		x := margin + int(float64(i)/float64(n-1)*plotWidth)
+		y := plotY(report.EquityCurve[i].Equity, minEquity, maxEquity, plotHeight, margin)
+		drawLine(img, prevX, prevY, x, y, line)
+		prevX, prevY = x, y
+	}
+
+	return writePNG(img, path)
+}
+
+// plotY maps an equity value to a pixel row, inverted so larger equity
+// renders higher on the chart.
+func plotY(equity, minEquity, maxEquity, plotHeight float64, margin int) int {
+	normalized := (equity - minEquity) / (maxEquity - minEquity)
+	return margin + int(plotHeight*(1-normalized))
+}
+
+// drawLine rasterizes a simple Bresenham line from (x0,y0) to (x1,y1).
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+
+	for {
+		img.Set(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			break
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func writePNG(img *image.RGBA, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("backtest: failed to create chart file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, img); err != nil {
+		return fmt.Errorf("backtest: failed to encode chart PNG: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package backtest
+
+import (
+	"math"
+	"time"
+)
+
+// EquityPoint is one sample of mark-to-market equity taken during a run,
+// the shape marketdata.ChartGenerator.CreatePnLChartData expects.
+type EquityPoint struct {
+	Timestamp time.Time
+	Equity    float64
+}
+
+// Report summarizes one backtest.Run.
+type Report struct {
+	RealizedPnL   float64
+	UnrealizedPnL float64
+	MaxDrawdown   float64
+	Sharpe        float64
+	Fills         []Fill
+	EquityCurve   []EquityPoint
+}
+
+// ChartData renders EquityCurve into the []map[string]interface{} shape
+// marketdata.ChartGenerator.CreatePnLChartData consumes, so a backtest
+// report can reuse the same chart pipeline as live PnL.
+func (r Report) ChartData() []map[string]interface{} {
+	data := make([]map[string]interface{}, 0, len(r.EquityCurve))
+	for _, point := range r.EquityCurve {
+		data = append(data, map[string]interface{}{
+			"timestamp":      point.Timestamp.Format(time.RFC3339),
+			"unrealized_pnl": point.Equity,
+		})
+	}
+	return data
+}
+
+// computeDrawdownAndSharpe derives MaxDrawdown (largest peak-to-trough drop
+// in EquityCurve) and an annualization-free Sharpe ratio (mean / stddev of
+// successive equity changes) from the recorded curve.
+func computeDrawdownAndSharpe(curve []EquityPoint) (maxDrawdown, sharpe float64) {
+	if len(curve) == 0 {
+		return 0, 0
+	}
+
+	peak := curve[0].Equity
+	for _, point := range curve {
+		if point.Equity > peak {
+			peak = point.Equity
+		}
+		if drawdown := peak - point.Equity; drawdown > maxDrawdown {
+			maxDrawdown = drawdown
+		}
+	}
+
+	if len(curve) < 2 {
+		return maxDrawdown, 0
+	}
+
+	returns := make([]float64, 0, len(curve)-1)
+	for i := 1; i < len(curve); i++ {
+		returns = append(returns, curve[i].Equity-curve[i-1].Equity)
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns))
+	stddev := math.Sqrt(variance)
+	if stddev == 0 {
+		return maxDrawdown, 0
+	}
+
+	return maxDrawdown, mean / stddev
+}
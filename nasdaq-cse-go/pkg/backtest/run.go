@@ -0,0 +1,85 @@
+package backtest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/marketdata"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/pkg/indicator"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/pkg/strategy"
+)
+
+// BacktestConfig describes one backtest.Run: the symbol and window of
+// history to replay, the bar interval to feed the strategy on, the fee/
+// slippage model, and where to read ticks from.
+type BacktestConfig struct {
+	Symbol    string
+	StartTime time.Time
+	EndTime   time.Time
+	Interval  time.Duration
+	Store     marketdata.TickStore
+
+	// MakerFeeRate, TakerFeeRate, and SlippageBps only matter when Broker
+	// is nil: they configure the SimulatedBroker Run constructs itself.
+	MakerFeeRate float64
+	TakerFeeRate float64
+	SlippageBps  float64
+
+	// Broker lets the caller construct the SimulatedBroker up front (it
+	// has to exist before strat, since strat's constructor takes it as an
+	// OrderSender); Run falls back to building one from the fee/slippage
+	// fields above if this is left nil.
+	Broker *SimulatedBroker
+}
+
+// Run replays cfg.Store's ticks for cfg.Symbol between StartTime and
+// EndTime through strat, matching its orders against a SimulatedBroker the
+// same way simulateExecutionReport fills orders against a live exchange,
+// and returns the resulting PnL report.
+func Run(cfg BacktestConfig, strat strategy.Strategy) (Report, error) {
+	if cfg.Store == nil {
+		return Report{}, fmt.Errorf("backtest: config must set a Store to replay ticks from")
+	}
+
+	ticks, err := cfg.Store.Query(cfg.Symbol, cfg.StartTime)
+	if err != nil {
+		return Report{}, fmt.Errorf("backtest: failed to query ticks for %s: %w", cfg.Symbol, err)
+	}
+
+	broker := cfg.Broker
+	if broker == nil {
+		broker = NewSimulatedBroker(cfg.MakerFeeRate, cfg.TakerFeeRate, cfg.SlippageBps)
+	}
+
+	agg := indicator.NewKLineAggregator(cfg.Interval)
+	agg.OnBarClosed(cfg.Symbol, strat.OnKLine)
+	strat.OnStart()
+
+	var curve []EquityPoint
+	for _, tick := range ticks {
+		if tick.Timestamp.After(cfg.EndTime) {
+			break
+		}
+
+		broker.updateMarket(tick)
+		agg.OnTick(cfg.Symbol, tick.Last, tick.Volume, tick.Timestamp)
+		curve = append(curve, EquityPoint{Timestamp: tick.Timestamp, Equity: broker.equity()})
+	}
+
+	maxDrawdown, sharpe := computeDrawdownAndSharpe(curve)
+
+	broker.mutex.Lock()
+	realizedPnL := broker.realizedPnL
+	unrealizedPnL := broker.unrealizedPnL()
+	fills := append([]Fill(nil), broker.fills...)
+	broker.mutex.Unlock()
+
+	return Report{
+		RealizedPnL:   realizedPnL,
+		UnrealizedPnL: unrealizedPnL,
+		MaxDrawdown:   maxDrawdown,
+		Sharpe:        sharpe,
+		Fills:         fills,
+		EquityCurve:   curve,
+	}, nil
+}
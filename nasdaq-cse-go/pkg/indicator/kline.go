@@ -0,0 +1,101 @@
+package indicator
+
+import (
+	"sync"
+	"time"
+)
+
+// KLine is one aggregated candle for a symbol over a KLineAggregator's
+// configured interval.
+type KLine struct {
+	Symbol    string
+	Interval  time.Duration
+	StartTime time.Time
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    int64
+	Closed    bool
+}
+
+// KLineAggregator folds a stream of ticks into fixed-interval KLines
+// (1m/5m/1h, whatever Interval is configured to) and pushes each bar's
+// close into every UpdatableSeries subscribed for that symbol as the bar
+// closes.
+type KLineAggregator struct {
+	interval time.Duration
+
+	mutex       sync.Mutex
+	current     map[string]*KLine
+	subscribers map[string][]UpdatableSeries
+	listeners   map[string][]func(KLine)
+}
+
+// NewKLineAggregator creates a KLineAggregator bucketing ticks into bars
+// interval wide.
+func NewKLineAggregator(interval time.Duration) *KLineAggregator {
+	return &KLineAggregator{
+		interval:    interval,
+		current:     make(map[string]*KLine),
+		subscribers: make(map[string][]UpdatableSeries),
+		listeners:   make(map[string][]func(KLine)),
+	}
+}
+
+// Subscribe registers series to receive symbol's close price every time a
+// bar closes.
+func (k *KLineAggregator) Subscribe(symbol string, series UpdatableSeries) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.subscribers[symbol] = append(k.subscribers[symbol], series)
+}
+
+// OnBarClosed registers fn to be called with the closed KLine itself
+// (e.g. so a Strategy can implement OnKLine without also owning a series).
+func (k *KLineAggregator) OnBarClosed(symbol string, fn func(KLine)) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.listeners[symbol] = append(k.listeners[symbol], fn)
+}
+
+// OnTick folds one tick into symbol's current bar, closing and publishing
+// the previous bar first if price falls in a new interval bucket.
+func (k *KLineAggregator) OnTick(symbol string, price float64, volume int64, timestamp time.Time) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	bucketStart := timestamp.Truncate(k.interval)
+	bar, ok := k.current[symbol]
+
+	if ok && bar.StartTime.Before(bucketStart) {
+		bar.Closed = true
+		k.publish(symbol, *bar)
+		bar = nil
+	}
+
+	if bar == nil {
+		bar = &KLine{Symbol: symbol, Interval: k.interval, StartTime: bucketStart, Open: price, High: price, Low: price}
+		k.current[symbol] = bar
+	}
+
+	if price > bar.High {
+		bar.High = price
+	}
+	if price < bar.Low {
+		bar.Low = price
+	}
+	bar.Close = price
+	bar.Volume += volume
+}
+
+// publish pushes bar.Close to every subscribed series and invokes every
+// OnBarClosed listener for symbol. Callers must hold k.mutex.
+func (k *KLineAggregator) publish(symbol string, bar KLine) {
+	for _, series := range k.subscribers[symbol] {
+		series.Update(bar.Close)
+	}
+	for _, fn := range k.listeners[symbol] {
+		fn(bar)
+	}
+}
@@ -0,0 +1,305 @@
+// Package indicator provides updatable technical-analysis series (moving
+// averages, volatility, and oscillators) that consume one price at a time
+// and keep their own rolling output history, the way bbgo's indicator
+// package does.
+package indicator
+
+import "math"
+
+// UpdatableSeries is a technical indicator fed one price per call. Index
+// follows bbgo's convention: Index(0) is the most recently pushed value,
+// Index(1) the one before that, and so on.
+type UpdatableSeries interface {
+	Update(price float64) float64
+	Index(i int) float64
+	Length() int
+}
+
+// buffer is the shared ring of computed output values every series below
+// embeds, so Index/Length only need to be written once.
+type buffer struct {
+	values []float64
+}
+
+func (b *buffer) push(v float64) {
+	b.values = append(b.values, v)
+}
+
+// Index returns the i-th most recent value (0 = latest), or 0 if the
+// series hasn't produced that many values yet.
+func (b *buffer) Index(i int) float64 {
+	n := len(b.values)
+	idx := n - 1 - i
+	if idx < 0 || idx >= n {
+		return 0
+	}
+	return b.values[idx]
+}
+
+// Length returns how many values the series has produced.
+func (b *buffer) Length() int {
+	return len(b.values)
+}
+
+// SMA is a simple moving average over the last Window prices.
+type SMA struct {
+	buffer
+	window int
+	prices []float64
+}
+
+// NewSMA creates an SMA over the given window.
+func NewSMA(window int) *SMA {
+	return &SMA{window: window}
+}
+
+// Update folds price into the average and returns the new value.
+func (s *SMA) Update(price float64) float64 {
+	s.prices = append(s.prices, price)
+	if len(s.prices) > s.window {
+		s.prices = s.prices[len(s.prices)-s.window:]
+	}
+
+	sum := 0.0
+	for _, p := range s.prices {
+		sum += p
+	}
+	v := sum / float64(len(s.prices))
+	s.push(v)
+	return v
+}
+
+// EMA is an exponential moving average with the standard 2/(window+1)
+// smoothing factor.
+type EMA struct {
+	buffer
+	multiplier  float64
+	current     float64
+	initialized bool
+}
+
+// NewEMA creates an EMA over the given window.
+func NewEMA(window int) *EMA {
+	return &EMA{multiplier: 2.0 / (float64(window) + 1.0)}
+}
+
+// Update folds price into the average and returns the new value.
+func (e *EMA) Update(price float64) float64 {
+	if !e.initialized {
+		e.current = price
+		e.initialized = true
+	} else {
+		e.current = (price-e.current)*e.multiplier + e.current
+	}
+	e.push(e.current)
+	return e.current
+}
+
+// StdDev is the population standard deviation of the last Window prices.
+type StdDev struct {
+	buffer
+	window int
+	prices []float64
+}
+
+// NewStdDev creates a StdDev over the given window.
+func NewStdDev(window int) *StdDev {
+	return &StdDev{window: window}
+}
+
+// Update folds price into the window and returns the new standard
+// deviation.
+func (s *StdDev) Update(price float64) float64 {
+	s.prices = append(s.prices, price)
+	if len(s.prices) > s.window {
+		s.prices = s.prices[len(s.prices)-s.window:]
+	}
+
+	mean := 0.0
+	for _, p := range s.prices {
+		mean += p
+	}
+	mean /= float64(len(s.prices))
+
+	variance := 0.0
+	for _, p := range s.prices {
+		variance += (p - mean) * (p - mean)
+	}
+	variance /= float64(len(s.prices))
+
+	v := math.Sqrt(variance)
+	s.push(v)
+	return v
+}
+
+// ATR is an average true range over the last Window prices. It's fed a
+// single close per Update (the same close-only stream KLineAggregator
+// publishes to every indicator), so it approximates true range as the
+// absolute close-to-close move rather than using a full OHLC bar's high/
+// low/previous-close — close enough for ATR-pinned stop distances, which
+// is the only thing this gateway uses it for.
+type ATR struct {
+	buffer
+	window      int
+	prevClose   float64
+	initialized bool
+	trueRanges  []float64
+}
+
+// NewATR creates an ATR over the given window.
+func NewATR(window int) *ATR {
+	return &ATR{window: window}
+}
+
+// Update folds price (a close) into the average true range and returns the
+// new value.
+func (a *ATR) Update(price float64) float64 {
+	tr := 0.0
+	if a.initialized {
+		tr = math.Abs(price - a.prevClose)
+	}
+	a.prevClose = price
+	a.initialized = true
+
+	a.trueRanges = append(a.trueRanges, tr)
+	if len(a.trueRanges) > a.window {
+		a.trueRanges = a.trueRanges[len(a.trueRanges)-a.window:]
+	}
+
+	sum := 0.0
+	for _, t := range a.trueRanges {
+		sum += t
+	}
+	v := sum / float64(len(a.trueRanges))
+	a.push(v)
+	return v
+}
+
+// FisherTransform maps the last Window prices' position within their
+// range onto a Gaussian-like curve, the way John Ehlers' Fisher Transform
+// sharpens turning points in an oscillator.
+type FisherTransform struct {
+	buffer
+	window   int
+	prices   []float64
+	prevFish float64
+}
+
+// NewFisherTransform creates a FisherTransform over the given window.
+func NewFisherTransform(window int) *FisherTransform {
+	return &FisherTransform{window: window}
+}
+
+// Update folds price into the window and returns the new Fisher value.
+func (f *FisherTransform) Update(price float64) float64 {
+	f.prices = append(f.prices, price)
+	if len(f.prices) > f.window {
+		f.prices = f.prices[len(f.prices)-f.window:]
+	}
+
+	min, max := f.prices[0], f.prices[0]
+	for _, p := range f.prices {
+		if p < min {
+			min = p
+		}
+		if p > max {
+			max = p
+		}
+	}
+
+	value := 0.0
+	if max != min {
+		value = 2 * ((price-min)/(max-min) - 0.5)
+	}
+	value = clamp(value, -0.999, 0.999)
+
+	fish := 0.5*math.Log((1+value)/(1-value)) + 0.5*f.prevFish
+	f.prevFish = fish
+	f.push(fish)
+	return fish
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// wma is a weighted moving average (most recent sample weighted highest),
+// the building block Drift uses for its Hull-like smoothing.
+type wma struct {
+	window int
+	values []float64
+}
+
+func newWMA(window int) *wma {
+	if window < 1 {
+		window = 1
+	}
+	return &wma{window: window}
+}
+
+func (w *wma) update(v float64) float64 {
+	w.values = append(w.values, v)
+	if len(w.values) > w.window {
+		w.values = w.values[len(w.values)-w.window:]
+	}
+
+	var sum, weightSum float64
+	for i, val := range w.values {
+		weight := float64(i + 1)
+		sum += val * weight
+		weightSum += weight
+	}
+	if weightSum == 0 {
+		return 0
+	}
+	return sum / weightSum
+}
+
+// Drift tracks the weighted log-return of price, smoothed with a Hull-like
+// filter (WMA(2*WMA(n/2) - WMA(n), sqrt(n))) and then Fisher-transformed,
+// so it turns faster than a plain moving average of returns while staying
+// less noisy than the raw return series.
+type Drift struct {
+	buffer
+	halfWMA     *wma
+	fullWMA     *wma
+	hullWMA     *wma
+	fisher      *FisherTransform
+	prevPrice   float64
+	initialized bool
+}
+
+// NewDrift creates a Drift indicator over the given window.
+func NewDrift(window int) *Drift {
+	return &Drift{
+		halfWMA: newWMA(window / 2),
+		fullWMA: newWMA(window),
+		hullWMA: newWMA(int(math.Sqrt(float64(window)))),
+		fisher:  NewFisherTransform(window),
+	}
+}
+
+// Update folds price into the drift calculation and returns the new
+// Fisher-transformed value.
+func (d *Drift) Update(price float64) float64 {
+	logReturn := 0.0
+	if d.initialized {
+		logReturn = math.Log(price / d.prevPrice)
+	}
+	d.prevPrice = price
+	d.initialized = true
+
+	half := d.halfWMA.update(logReturn)
+	full := d.fullWMA.update(logReturn)
+	hull := d.hullWMA.update(2*half - full)
+
+	fish := d.fisher.Update(hull)
+	d.push(fish)
+	return fish
+}
@@ -0,0 +1,99 @@
+package strategy
+
+import (
+	"math"
+	"sync"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/pkg/indicator"
+)
+
+// AtrPinStrategy pins a symmetric stop/take-profit bracket around the
+// current price at a distance of ATR(window)*Multiplier, the atrpin
+// pattern from bbgo-style strategy configs. It only repositions the
+// bracket once price drifts outside MinPriceRange of the last pinned
+// level, so it isn't churning orders on every tick.
+type AtrPinStrategy struct {
+	Symbol        string
+	Quantity      float64
+	Multiplier    float64
+	MinPriceRange float64
+
+	atr  *indicator.ATR
+	comm OrderSender
+
+	mutex             sync.Mutex
+	pinnedPrice       float64
+	stopOrderID       string
+	takeProfitOrderID string
+}
+
+// NewAtrPinStrategy creates an AtrPinStrategy trading quantity-sized
+// brackets on symbol, sized at multiplier*ATR(atrWindow) and repositioned
+// once price moves outside minPriceRange of the last pin. Orders are
+// placed through comm, which can be a live communication.CommunicationManager
+// or (for backtesting) a pkg/backtest.SimulatedBroker.
+func NewAtrPinStrategy(symbol string, quantity, multiplier, minPriceRange float64, atrWindow int, comm OrderSender) *AtrPinStrategy {
+	return &AtrPinStrategy{
+		Symbol:        symbol,
+		Quantity:      quantity,
+		Multiplier:    multiplier,
+		MinPriceRange: minPriceRange,
+		atr:           indicator.NewATR(atrWindow),
+		comm:          comm,
+	}
+}
+
+// OnStart satisfies Strategy; AtrPinStrategy has no setup beyond what its
+// constructor already did.
+func (s *AtrPinStrategy) OnStart() {}
+
+// OnKLine feeds kline's close into the ATR and repositions the bracket
+// once price has drifted outside MinPriceRange of the last pin.
+func (s *AtrPinStrategy) OnKLine(kline indicator.KLine) {
+	atrValue := s.atr.Update(kline.Close)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.pinnedPrice != 0 && math.Abs(kline.Close-s.pinnedPrice) < s.MinPriceRange {
+		return
+	}
+
+	s.repositionBracket(kline.Close, atrValue)
+}
+
+// repositionBracket sends a fresh stop and take-profit order at distance
+// ATR*Multiplier from price. OrderSender only sends new orders, so
+// repositioning submits the new bracket without cancelling the prior one;
+// the previous bracket's orders are left resting.
+func (s *AtrPinStrategy) repositionBracket(price, atrValue float64) {
+	distance := atrValue * s.Multiplier
+	stopPrice := price - distance
+	takeProfitPrice := price + distance
+
+	if orderID, err := s.comm.SendOrder(map[string]interface{}{
+		"symbol":     s.Symbol,
+		"side":       "SELL",
+		"order_type": "LIMIT",
+		"quantity":   s.Quantity,
+		"price":      stopPrice,
+	}); err == nil {
+		s.stopOrderID = orderID
+	}
+
+	if orderID, err := s.comm.SendOrder(map[string]interface{}{
+		"symbol":     s.Symbol,
+		"side":       "SELL",
+		"order_type": "LIMIT",
+		"quantity":   s.Quantity,
+		"price":      takeProfitPrice,
+	}); err == nil {
+		s.takeProfitOrderID = orderID
+	}
+
+	s.pinnedPrice = price
+}
+
+// OnTrade satisfies Strategy; AtrPinStrategy repositions on kline closes
+// rather than reacting to its own fills.
+func (s *AtrPinStrategy) OnTrade(trade Trade) {}
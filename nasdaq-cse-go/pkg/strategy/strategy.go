@@ -0,0 +1,36 @@
+// Package strategy defines the trading-strategy extension point strategies
+// bind against live klines and fills, and a reference AtrPinStrategy.
+package strategy
+
+import (
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/pkg/indicator"
+)
+
+// Trade is one fill a strategy reacts to via OnTrade.
+type Trade struct {
+	Symbol    string
+	Price     float64
+	Quantity  float64
+	Side      string
+	Timestamp time.Time
+}
+
+// Strategy is the extension point a strategy config (e.g. atrpin.yaml)
+// binds to a symbol: OnStart runs once at wire-up, OnKLine
+// runs every time KLineAggregator closes a bar, and OnTrade runs on every
+// fill the strategy receives.
+type Strategy interface {
+	OnStart()
+	OnKLine(kline indicator.KLine)
+	OnTrade(trade Trade)
+}
+
+// OrderSender is the order-placement seam a strategy depends on instead of
+// a concrete communication.CommunicationManager, so the same strategy can
+// run against a live FIX session or (via pkg/backtest) a simulated book.
+// CommunicationManager already satisfies this structurally.
+type OrderSender interface {
+	SendOrder(orderData map[string]interface{}) (string, error)
+}
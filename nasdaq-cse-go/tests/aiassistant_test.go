@@ -2,15 +2,18 @@
 package tests
 
 import (
+	"math"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/aiassistant"
 	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/persistence"
 )
 
 func TestTradingBot_AnalyzeTradeOpportunity(t *testing.T) {
-	bot := aiassistant.NewTradingBot()
+	bot := aiassistant.NewTradingBot("")
 
 	marketData := core.MarketDataResponse{
 		Price:         2050.0,
@@ -22,9 +25,9 @@ func TestTradingBot_AnalyzeTradeOpportunity(t *testing.T) {
 
 	userPositions := []map[string]interface{}{
 		{
-			"quantity":         5.0,
-			"avg_entry_price":  2040.0,
-			"unrealized_pnl":   50.0,
+			"quantity":        5.0,
+			"avg_entry_price": 2040.0,
+			"unrealized_pnl":  50.0,
 		},
 	}
 
@@ -72,26 +75,30 @@ func TestTradingBot_AnalyzeTradeOpportunity(t *testing.T) {
 }
 
 func TestTradingBot_AnalyzeRisk(t *testing.T) {
-	bot := aiassistant.NewTradingBot()
+	bot := aiassistant.NewTradingBot("")
+	// AnalyzeRisk takes no market data, so it checks DataQuality against
+	// whatever tick was last seen for aiassistant.DefaultSymbol - seed one
+	// so this test's RiskLevel isn't forced to CRITICAL by stale data.
+	bot.NotifyPriceUpdate(aiassistant.DefaultSymbol, core.MarketDataResponse{Price: 2050.0})
 
 	userPositions := []map[string]interface{}{
 		{
-			"quantity":         10.0,
-			"avg_entry_price":  2000.0,
-			"unrealized_pnl":   -500.0,
-			"realized_pnl":     100.0,
+			"quantity":        10.0,
+			"avg_entry_price": 2000.0,
+			"unrealized_pnl":  -500.0,
+			"realized_pnl":    100.0,
 		},
 		{
-			"quantity":         -5.0,
-			"avg_entry_price":  2100.0,
-			"unrealized_pnl":   250.0,
-			"realized_pnl":     50.0,
+			"quantity":        -5.0,
+			"avg_entry_price": 2100.0,
+			"unrealized_pnl":  250.0,
+			"realized_pnl":    50.0,
 		},
 	}
 
 	accountBalance := 50000.0
 
-	analysis := bot.AnalyzeRisk(userPositions, accountBalance)
+	analysis := bot.AnalyzeRisk(1, userPositions, accountBalance)
 
 	// Test analysis structure
 	if analysis.AnalysisType != "risk_analysis" {
@@ -134,7 +141,7 @@ func TestTradingBot_AnalyzeRisk(t *testing.T) {
 }
 
 func TestTradingBot_SuggestHedgingStrategy(t *testing.T) {
-	bot := aiassistant.NewTradingBot()
+	bot := aiassistant.NewTradingBot("")
 
 	marketData := core.MarketDataResponse{
 		Price:         2050.0,
@@ -151,7 +158,7 @@ func TestTradingBot_SuggestHedgingStrategy(t *testing.T) {
 		},
 	}
 
-	strategy := bot.SuggestHedgingStrategy(userPositions, marketData)
+	strategy := bot.SuggestHedgingStrategy(1, userPositions, marketData, 100000.0)
 
 	// Test strategy structure
 	if strategy.AnalysisType != "hedging_strategy" {
@@ -184,8 +191,98 @@ func TestTradingBot_SuggestHedgingStrategy(t *testing.T) {
 	}
 }
 
+func TestTradingBot_ConfirmHedgeSizesResidualOnly(t *testing.T) {
+	bot := aiassistant.NewTradingBot("")
+
+	marketData := core.MarketDataResponse{
+		Price: 2050.0,
+	}
+
+	// Net long position of 20 units, held constant across both calls.
+	userPositions := []map[string]interface{}{
+		{"quantity": 20.0},
+	}
+
+	first := bot.SuggestHedgingStrategy(1, userPositions, marketData, 100000.0)
+	if len(first.HedgingSuggestions) == 0 {
+		t.Fatalf("expected a hedging suggestion for net long position")
+	}
+	firstSuggestion := first.HedgingSuggestions[0]
+	if firstSuggestion.Quantity != 10.0 { // 50% of 20
+		t.Errorf("expected first hedge sized at 10 but got %f", firstSuggestion.Quantity)
+	}
+
+	if err := bot.ConfirmHedge(firstSuggestion.SuggestionID, firstSuggestion.Quantity, firstSuggestion.SuggestedPrice); err != nil {
+		t.Fatalf("ConfirmHedge returned error: %v", err)
+	}
+
+	second := bot.SuggestHedgingStrategy(1, userPositions, marketData, 100000.0)
+	if len(second.HedgingSuggestions) == 0 {
+		t.Fatalf("expected a second hedging suggestion for the residual exposure")
+	}
+	secondSuggestion := second.HedgingSuggestions[0]
+	// Residual exposure is 20 - 10 = 10, so the second hedge is 50% of that.
+	if secondSuggestion.Quantity != 5.0 {
+		t.Errorf("expected second hedge sized at 5 (residual after first confirmed hedge) but got %f", secondSuggestion.Quantity)
+	}
+
+	stats := bot.GetProfitStats()
+	if stats.HedgeCount != 1 {
+		t.Errorf("expected 1 confirmed hedge recorded but got %d", stats.HedgeCount)
+	}
+	if stats.HedgeVolume != 10.0 {
+		t.Errorf("expected hedge volume of 10 but got %f", stats.HedgeVolume)
+	}
+}
+
+func TestTradingBot_SuggestHedgingStrategySwitchesToReduceOnlyBelowMinMargin(t *testing.T) {
+	bot := aiassistant.NewTradingBot("")
+
+	marketData := core.MarketDataResponse{
+		Price: 2050.0,
+	}
+
+	// Net long position of 10 units => used margin of 10*2050 = 20500 at 1x leverage.
+	userPositions := []map[string]interface{}{
+		{"quantity": 10.0},
+	}
+
+	// Ample balance keeps margin level (40000/20500 ~= 1.95) above the 1.5 minimum,
+	// so a normal additive SELL hedge is suggested.
+	healthy := bot.SuggestHedgingStrategy(1, userPositions, marketData, 40000.0)
+	foundSell := false
+	for _, suggestion := range healthy.HedgingSuggestions {
+		if suggestion.Action == "SELL" {
+			foundSell = true
+		}
+		if suggestion.Action == "REDUCE_POSITION" {
+			t.Errorf("did not expect REDUCE_POSITION while margin level is healthy")
+		}
+	}
+	if !foundSell {
+		t.Errorf("expected SELL suggestion while margin level is healthy")
+	}
+
+	// Simulated unrealized losses push balance down until margin level
+	// (12000/20500 ~= 0.59) falls below the 1.5 minimum - the bot should
+	// switch from adding a hedge to reducing the existing position instead.
+	thin := bot.SuggestHedgingStrategy(1, userPositions, marketData, 12000.0)
+	foundReduce := false
+	for _, suggestion := range thin.HedgingSuggestions {
+		if suggestion.Action == "SELL" || suggestion.Action == "BUY" {
+			t.Errorf("did not expect an additive %s hedge below the min margin level", suggestion.Action)
+		}
+		if suggestion.Action == "REDUCE_POSITION" {
+			foundReduce = true
+		}
+	}
+	if !foundReduce {
+		t.Errorf("expected REDUCE_POSITION suggestion once margin level drops below the minimum")
+	}
+}
+
 func TestTradingBot_ChatResponse(t *testing.T) {
-	bot := aiassistant.NewTradingBot()
+	bot := aiassistant.NewTradingBot("")
 
 	marketData := core.MarketDataResponse{
 		Price:         2050.0,
@@ -253,7 +350,7 @@ func TestTradingBot_ChatResponse(t *testing.T) {
 }
 
 func TestTradingBot_TechnicalIndicators(t *testing.T) {
-	bot := aiassistant.NewTradingBot()
+	bot := aiassistant.NewTradingBot("")
 
 	marketData := core.MarketDataResponse{
 		Price:         2050.0,
@@ -271,16 +368,372 @@ func TestTradingBot_TechnicalIndicators(t *testing.T) {
 		}
 	}
 
-	// Test volatility calculation
+	// Test volatility calculation. The loop above feeds an unchanging price,
+	// so the real rolling-stddev-of-returns volatility collapses toward 0 --
+	// unlike the old rand.Float64()-based placeholder, it is allowed to be 0.
 	analysis := bot.AnalyzeTradeOpportunity(marketData, []map[string]interface{}{})
 	volatility := analysis.TechnicalIndicators.Volatility
 
 	if volatility < 0 {
 		t.Errorf("Volatility should be non-negative but got %f", volatility)
 	}
+	if volatility > 0.001 {
+		t.Errorf("Volatility should be near-zero for an unchanging price series but got %f", volatility)
+	}
+}
+
+// TestTradingBot_ATRTracksSyntheticRange feeds a synthetic series with a
+// fixed 2.0-wide bid/ask range around a steadily rising close and checks
+// that TechnicalIndicators.ATR converges to that range once the rolling
+// window fills, with PinningRange tracking ATR*Multiplier.
+func TestTradingBot_ATRTracksSyntheticRange(t *testing.T) {
+	bot := aiassistant.NewTradingBot("")
+
+	price := 2000.0
+	var analysis aiassistant.TradeAnalysis
+	for i := 0; i < 20; i++ {
+		price += 1.0
+		analysis = bot.AnalyzeTradeOpportunity(core.MarketDataResponse{
+			Price: price,
+			Bid:   price - 1.0,
+			Ask:   price + 1.0,
+		}, nil)
+	}
+
+	atr := analysis.TechnicalIndicators.ATR
+	if math.Abs(atr-2.0) > 0.01 {
+		t.Errorf("Expected ATR to converge to the synthetic 2.0 bar range, got %f", atr)
+	}
+
+	expectedPinningRange := atr * 100 // DefaultPositionSizeConfig's multiplier
+	if math.Abs(analysis.TechnicalIndicators.PinningRange-expectedPinningRange) > 0.01 {
+		t.Errorf("Expected PinningRange %f (ATR*100), got %f", expectedPinningRange, analysis.TechnicalIndicators.PinningRange)
+	}
+}
+
+// TestTradingBot_SuggestPositionSize checks SuggestPositionSize's ATR-sized
+// quantity, its account-balance/initial-margin clamp, and that a bar whose
+// bid/ask range is too tight is skipped rather than sized.
+func TestTradingBot_SuggestPositionSize(t *testing.T) {
+	contract := core.Contract{Symbol: "GOLD2024DEC", InitialMargin: 1000.0}
+
+	t.Run("sizes quantity as amount/ATR", func(t *testing.T) {
+		bot := aiassistant.NewTradingBot("")
+		price := 2000.0
+		for i := 0; i < 20; i++ {
+			price += 1.0
+			bot.AnalyzeTradeOpportunity(core.MarketDataResponse{Price: price, Bid: price - 1.0, Ask: price + 1.0}, nil)
+		}
+
+		result := bot.SuggestPositionSize(core.MarketDataResponse{Price: price, Bid: price - 3.0, Ask: price + 3.0}, 200.0, 1000000.0, contract)
+		if result.Skipped {
+			t.Fatalf("Expected a sized suggestion, got skipped: %s", result.SkippedReason)
+		}
+
+		expectedQuantity := 200.0 / result.ATR
+		if math.Abs(result.Quantity-expectedQuantity) > 0.01 {
+			t.Errorf("Expected quantity %f (amount/ATR), got %f", expectedQuantity, result.Quantity)
+		}
+	})
+
+	t.Run("clamps quantity to account_balance/initial_margin", func(t *testing.T) {
+		bot := aiassistant.NewTradingBot("")
+		price := 2000.0
+		for i := 0; i < 20; i++ {
+			price += 1.0
+			bot.AnalyzeTradeOpportunity(core.MarketDataResponse{Price: price, Bid: price - 1.0, Ask: price + 1.0}, nil)
+		}
+
+		result := bot.SuggestPositionSize(core.MarketDataResponse{Price: price, Bid: price - 3.0, Ask: price + 3.0}, 200.0, 500.0, contract)
+		if result.Quantity != 0.5 {
+			t.Errorf("Expected quantity clamped to accountBalance/InitialMargin (0.5), got %f", result.Quantity)
+		}
+	})
+
+	t.Run("skips a bar too tight to size", func(t *testing.T) {
+		bot := aiassistant.NewTradingBot("")
+		price := 2000.0
+		for i := 0; i < 20; i++ {
+			price += 1.0
+			bot.AnalyzeTradeOpportunity(core.MarketDataResponse{Price: price, Bid: price - 1.0, Ask: price + 1.0}, nil)
+		}
+
+		result := bot.SuggestPositionSize(core.MarketDataResponse{Price: price, Bid: price, Ask: price}, 200.0, 1000000.0, contract)
+		if !result.Skipped {
+			t.Errorf("Expected a zero-range bar to be skipped")
+		}
+		if result.Quantity != 0 {
+			t.Errorf("Expected Quantity 0 for a skipped suggestion, got %f", result.Quantity)
+		}
+	})
+}
+
+// TestTradingBot_RSIConvergence feeds deterministic synthetic price series
+// into IngestMarketData and checks that RSI converges to the Wilder-smoothed
+// reference values for those series: a flat series reads as neutral (50), an
+// all-gain series saturates at 100, and an all-loss series saturates at 0.
+func TestTradingBot_RSIConvergence(t *testing.T) {
+	t.Run("flat series reads neutral", func(t *testing.T) {
+		bot := aiassistant.NewTradingBot("")
+		price := 2000.0
+		var rsi float64
+		for i := 0; i < 20; i++ {
+			analysis := bot.AnalyzeTradeOpportunity(core.MarketDataResponse{Price: price, Volume: 100}, nil)
+			rsi = analysis.TechnicalIndicators.RSI
+		}
+		if rsi != 50 {
+			t.Errorf("Expected RSI 50 for a flat price series but got %f", rsi)
+		}
+	})
+
+	t.Run("monotonic gains saturate near 100", func(t *testing.T) {
+		bot := aiassistant.NewTradingBot("")
+		price := 2000.0
+		var rsi float64
+		for i := 0; i < 30; i++ {
+			price += 1.0
+			analysis := bot.AnalyzeTradeOpportunity(core.MarketDataResponse{Price: price, Volume: 100}, nil)
+			rsi = analysis.TechnicalIndicators.RSI
+		}
+		if rsi != 100 {
+			t.Errorf("Expected RSI 100 for a monotonically rising price series but got %f", rsi)
+		}
+	})
+
+	t.Run("monotonic losses saturate near 0", func(t *testing.T) {
+		bot := aiassistant.NewTradingBot("")
+		price := 2000.0
+		var rsi float64
+		for i := 0; i < 30; i++ {
+			price -= 1.0
+			analysis := bot.AnalyzeTradeOpportunity(core.MarketDataResponse{Price: price, Volume: 100}, nil)
+			rsi = analysis.TechnicalIndicators.RSI
+		}
+		if rsi != 0 {
+			t.Errorf("Expected RSI 0 for a monotonically falling price series but got %f", rsi)
+		}
+	})
+}
+
+// TestTradingBot_BollingerAndVWAP checks that %b and VWAP are populated
+// sensibly once enough ticks have been ingested to fill a Bollinger window.
+func TestTradingBot_BollingerAndVWAP(t *testing.T) {
+	bot := aiassistant.NewTradingBot("")
+	price := 2000.0
+	var analysis aiassistant.TradeAnalysis
+	for i := 0; i < 25; i++ {
+		price += 0.5
+		analysis = bot.AnalyzeTradeOpportunity(core.MarketDataResponse{Price: price, Volume: 200}, nil)
+	}
+
+	indicators := analysis.TechnicalIndicators
+	if indicators.BBUpper <= indicators.BBLower {
+		t.Errorf("Expected BBUpper > BBLower but got upper=%f lower=%f", indicators.BBUpper, indicators.BBLower)
+	}
+	if indicators.PercentB < 0 || indicators.PercentB > 1.5 {
+		t.Errorf("Expected %%b within a sane range but got %f", indicators.PercentB)
+	}
+	if indicators.VWAP <= 0 {
+		t.Errorf("Expected a positive VWAP but got %f", indicators.VWAP)
+	}
+}
+
+// TestTradingBot_CircuitBreakerTripsOnLosingStreak simulates a losing streak
+// through repeated AnalyzeRisk calls and confirms trade suggestions are
+// suppressed until the breaker's cooldown expires.
+func TestTradingBot_CircuitBreakerTripsOnLosingStreak(t *testing.T) {
+	bot := aiassistant.NewTradingBot("")
+	accountBalance := 100000.0
+
+	// DefaultCircuitBreakerConfig trips after 3 consecutive worsening
+	// combined-PnL observations.
+	pnl := 0.0
+	var lastRisk aiassistant.RiskAnalysis
+	for i := 0; i < 4; i++ {
+		pnl -= 1000.0
+		positions := []map[string]interface{}{
+			{"quantity": 1.0, "avg_entry_price": 2000.0, "unrealized_pnl": pnl, "realized_pnl": 0.0},
+		}
+		lastRisk = bot.AnalyzeRisk(1, positions, accountBalance)
+	}
+
+	if lastRisk.RiskLevel != "CRITICAL" {
+		t.Fatalf("Expected RiskLevel CRITICAL after a losing streak but got %s", lastRisk.RiskLevel)
+	}
+	if lastRisk.CooldownUntil == "" {
+		t.Errorf("Expected a CooldownUntil timestamp once the breaker trips")
+	}
+
+	marketData := core.MarketDataResponse{Price: 2050.0, Volume: 100}
+	analysis := bot.AnalyzeTradeOpportunity(marketData, nil)
+	if analysis.PredictedDirection != "HALT" {
+		t.Errorf("Expected trade suggestions to be halted while the breaker is open but got %s", analysis.PredictedDirection)
+	}
+
+	response := bot.ChatResponse("can I trade?", map[string]interface{}{})
+	if !strings.Contains(strings.ToLower(response), "no") {
+		t.Errorf("Expected ChatResponse to report trading is halted, got: %s", response)
+	}
+}
+
+// TestTradingBot_StaleMarketDataHalts feeds one tick, fast-forwards the
+// bot's clock past StaleAfter, and checks that trade/hedging suggestions
+// refuse to emit a direction and report DataQuality.Stale.
+func TestTradingBot_StaleMarketDataHalts(t *testing.T) {
+	bot := aiassistant.NewTradingBot("")
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	bot.SetClock(clock)
+
+	marketData := core.MarketDataResponse{Price: 2050.0, Volume: 100}
+	fresh := bot.AnalyzeTradeOpportunity(marketData, nil)
+	if fresh.DataQuality.Stale {
+		t.Fatalf("Expected fresh tick to not be stale, got reason: %s", fresh.DataQuality.Reason)
+	}
+	if fresh.PredictedDirection == "HALT" {
+		t.Errorf("Expected a directional prediction on fresh data but got HALT")
+	}
+
+	// Advance well past the default 30s StaleAfter without another tick.
+	now = now.Add(45 * time.Second)
+
+	stale := bot.AnalyzeTradeOpportunity(marketData, nil)
+	if !stale.DataQuality.Stale {
+		t.Errorf("Expected DataQuality.Stale after advancing the clock past StaleAfter")
+	}
+	if stale.PredictedDirection != "HALT" {
+		t.Errorf("Expected PredictedDirection HALT on stale data but got %s", stale.PredictedDirection)
+	}
+	if stale.ConfidenceScore != 0 {
+		t.Errorf("Expected ConfidenceScore 0 on stale data but got %f", stale.ConfidenceScore)
+	}
+
+	userPositions := []map[string]interface{}{{"quantity": 10.0}}
+	hedging := bot.SuggestHedgingStrategy(1, userPositions, marketData, 100000.0)
+	if !hedging.DataQuality.Stale {
+		t.Errorf("Expected hedging strategy to report stale data too")
+	}
+	if len(hedging.HedgingSuggestions) != 1 || hedging.HedgingSuggestions[0].Action != "HALT" {
+		t.Errorf("Expected a single HALT hedging suggestion while data is stale, got %+v", hedging.HedgingSuggestions)
+	}
+}
+
+// TestTradingBot_RepeatedPriceReadsAsStale checks that a frozen feed (same
+// price on every tick) is detected even though ticks keep arriving within
+// StaleAfter of each other.
+func TestTradingBot_RepeatedPriceReadsAsStale(t *testing.T) {
+	bot := aiassistant.NewTradingBot("")
+	marketData := core.MarketDataResponse{Price: 2050.0, Volume: 100}
+
+	var last aiassistant.TradeAnalysis
+	for i := 0; i < 7; i++ {
+		last = bot.AnalyzeTradeOpportunity(marketData, nil)
+	}
+
+	if !last.DataQuality.Stale {
+		t.Errorf("Expected repeated identical prices to be flagged stale")
+	}
+	if last.PredictedDirection != "HALT" {
+		t.Errorf("Expected PredictedDirection HALT once the feed looks frozen but got %s", last.PredictedDirection)
+	}
+}
+
+// TestTradingBot_RecordTradeTracksImbalance checks that RecordTrade
+// classifies trades against bid/ask and that a lopsided sequence of
+// buy-initiated trades pushes AnalyzeTradeOpportunity's prediction bullish
+// with boosted confidence.
+func TestTradingBot_RecordTradeTracksImbalance(t *testing.T) {
+	bot := aiassistant.NewTradingBot("")
+
+	bid, ask := 1999.0, 2001.0
+	for i := 0; i < 5; i++ {
+		bot.RecordTrade(core.Trade{Price: 2001.0, Quantity: 1.0, TradeTime: time.Now()}, bid, ask)
+	}
+	bot.RecordTrade(core.Trade{Price: 1999.0, Quantity: 1.0, TradeTime: time.Now()}, bid, ask)
+
+	flow := bot.LatestOrderFlow()
+	if flow.BuyVolume != 5 || flow.SellVolume != 1 {
+		t.Fatalf("Expected BuyVolume 5 / SellVolume 1, got %f / %f", flow.BuyVolume, flow.SellVolume)
+	}
+	expectedRatio := (5.0 - 1.0) / (5.0 + 1.0)
+	if math.Abs(flow.ImbalanceRatio-expectedRatio) > 0.001 {
+		t.Errorf("Expected ImbalanceRatio %f, got %f", expectedRatio, flow.ImbalanceRatio)
+	}
 
-	// Volatility should be related to price change
-	if volatility < 0.01 { // Should be at least as much as the change percent
-		t.Errorf("Volatility seems too low for the given price change: %f", volatility)
+	analysis := bot.AnalyzeTradeOpportunity(core.MarketDataResponse{Price: 2000.0, Bid: bid, Ask: ask}, nil)
+	if analysis.PredictedDirection != "BULLISH" {
+		t.Errorf("Expected a strong buy-side imbalance to predict BULLISH, got %s", analysis.PredictedDirection)
+	}
+	if analysis.ConfidenceScore <= 50 {
+		t.Errorf("Expected ConfidenceScore boosted above the neutral baseline, got %f", analysis.ConfidenceScore)
+	}
+}
+
+// TestTradingBot_OrderFlowChartData checks that OrderFlowChartData surfaces
+// the in-progress interval with the order_flow_imbalance chart type.
+func TestTradingBot_OrderFlowChartData(t *testing.T) {
+	bot := aiassistant.NewTradingBot("")
+	bot.RecordTrade(core.Trade{Price: 2001.0, Quantity: 2.0, TradeTime: time.Now()}, 1999.0, 2001.0)
+
+	chart := bot.OrderFlowChartData()
+	if chart.Type != "order_flow_imbalance" {
+		t.Errorf("Expected chart type order_flow_imbalance, got %s", chart.Type)
+	}
+	if len(chart.Data) != 1 {
+		t.Fatalf("Expected 1 chart point for the in-progress interval, got %d", len(chart.Data))
+	}
+	if chart.Data[0].Volume != 2 {
+		t.Errorf("Expected chart point Volume 2, got %d", chart.Data[0].Volume)
 	}
-}
\ No newline at end of file
+}
+
+// TestTradingBot_ChatResponseRemembersHistoryAcrossRestart checks that
+// ChatResponse records a chat turn keyed by context's user_id, and that a
+// fresh TradingBot backed by the same store picks the history back up.
+func TestTradingBot_ChatResponseRemembersHistoryAcrossRestart(t *testing.T) {
+	store, err := persistence.NewJSONStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewJSONStore failed: %v", err)
+	}
+
+	bot := aiassistant.NewTradingBotWithStore("", store)
+	bot.ChatResponse("help", map[string]interface{}{"user_id": uint(42)})
+
+	history := bot.ChatHistory(42)
+	if len(history) != 1 || history[0].Message != "help" {
+		t.Fatalf("Expected 1 remembered turn for user 42, got %+v", history)
+	}
+
+	restarted := aiassistant.NewTradingBotWithStore("", store)
+	restartedHistory := restarted.ChatHistory(42)
+	if len(restartedHistory) != 1 || restartedHistory[0].Message != "help" {
+		t.Errorf("Expected chat history to survive a restart against the same store, got %+v", restartedHistory)
+	}
+}
+
+// TestTradingBot_PersistStateRoundTripsPriceSeries checks that a restarted
+// TradingBot backed by the same store recovers ATR/RSI history ingested
+// before the restart, instead of recomputing indicators from scratch.
+func TestTradingBot_PersistStateRoundTripsPriceSeries(t *testing.T) {
+	store, err := persistence.NewJSONStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewJSONStore failed: %v", err)
+	}
+
+	bot := aiassistant.NewTradingBotWithStore("", store)
+	price := 2000.0
+	for i := 0; i < 20; i++ {
+		price += 1.0
+		bot.AnalyzeTradeOpportunity(core.MarketDataResponse{Price: price, Bid: price - 1.0, Ask: price + 1.0}, nil)
+	}
+	if err := bot.PersistState(); err != nil {
+		t.Fatalf("PersistState failed: %v", err)
+	}
+
+	restarted := aiassistant.NewTradingBotWithStore("", store)
+	analysis := restarted.AnalyzeTradeOpportunity(core.MarketDataResponse{Price: price, Bid: price - 1.0, Ask: price + 1.0}, nil)
+	if math.Abs(analysis.TechnicalIndicators.ATR-2.0) > 0.5 {
+		t.Errorf("Expected the restarted bot's ATR to reflect the restored price history, got %f", analysis.TechnicalIndicators.ATR)
+	}
+}
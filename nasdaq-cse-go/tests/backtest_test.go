@@ -0,0 +1,127 @@
+// Package tests provides unit tests for the AI-assistant backtest harness
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/backtest"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+)
+
+// fixedMarketDataSource and fixedTradeSource serve pre-built, in-memory
+// fixtures so the test doesn't depend on the JSON-file sources.
+type fixedMarketDataSource struct {
+	bySymbol map[string][]core.MarketData
+}
+
+func (f fixedMarketDataSource) Query(symbol string, start, end time.Time) ([]core.MarketData, error) {
+	return f.bySymbol[symbol], nil
+}
+
+type fixedTradeSource struct {
+	bySymbol map[string][]core.Trade
+}
+
+func (f fixedTradeSource) Query(symbol string, start, end time.Time) ([]core.Trade, error) {
+	return f.bySymbol[symbol], nil
+}
+
+func TestBacktestRun_RealizedPnLMatchesAnalyticalExpectation(t *testing.T) {
+	const symbol = "GOLD2024DEC"
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	ticks := []core.MarketData{
+		{Price: 2000, Bid: 1999.5, Ask: 2000.5, Volume: 100, Timestamp: base},
+		{Price: 2020, Bid: 2019.5, Ask: 2020.5, Volume: 100, Timestamp: base.Add(time.Minute)},
+	}
+
+	buyPnL := 0.0    // opening fill, no realized PnL yet
+	sellPnL := 200.0 // closes the 10-unit long bought at 2000 against 2020
+	trades := []core.Trade{
+		{TradeID: "t1", ContractID: 1, Quantity: 10, Price: 2000, PnL: &buyPnL, TradeTime: base.Add(30 * time.Second)},
+		{TradeID: "t2", ContractID: 1, Quantity: 10, Price: 2020, PnL: &sellPnL, TradeTime: base.Add(90 * time.Second)},
+	}
+
+	cfg := backtest.Config{
+		Symbols:   []string{symbol},
+		StartTime: base.Add(-time.Minute),
+		EndTime:   base.Add(time.Hour),
+		Accounts: []backtest.AccountConfig{
+			{UserID: 1, StartingBalance: 100000},
+		},
+		MakerFeeRate: 0,
+		TakerFeeRate: 0.001,
+	}
+
+	report, err := backtest.Run(cfg,
+		fixedMarketDataSource{bySymbol: map[string][]core.MarketData{symbol: ticks}},
+		fixedTradeSource{bySymbol: map[string][]core.Trade{symbol: trades}},
+	)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(report.Accounts) != 1 {
+		t.Fatalf("Expected 1 account report, got %d", len(report.Accounts))
+	}
+
+	account := report.Accounts[0]
+	expectedFees := (2000.0 * 10 * cfg.TakerFeeRate) + (2020.0 * 10 * cfg.TakerFeeRate)
+	expectedRealizedPnL := buyPnL + sellPnL - expectedFees
+
+	if diff := account.RealizedPnL - expectedRealizedPnL; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected RealizedPnL %.6f, got %.6f", expectedRealizedPnL, account.RealizedPnL)
+	}
+	if account.UnrealizedPnL != 0 {
+		t.Errorf("Expected no open position left after the closing fill, got UnrealizedPnL %.6f", account.UnrealizedPnL)
+	}
+	if account.WinRate != 1.0 {
+		t.Errorf("Expected a 100%% win rate (1 winning close, 0 losing), got %.2f", account.WinRate)
+	}
+	if len(account.EquityCurve.Data) != len(ticks)+len(trades) {
+		t.Errorf("Expected one equity point per replayed event, got %d", len(account.EquityCurve.Data))
+	}
+}
+
+func TestBacktestRun_RequiresAtLeastOneAccount(t *testing.T) {
+	_, err := backtest.Run(backtest.Config{Symbols: []string{"GOLD2024DEC"}}, fixedMarketDataSource{}, fixedTradeSource{})
+	if err == nil {
+		t.Fatal("Expected Run to reject a config with no accounts")
+	}
+}
+
+func TestBacktestRun_TracksSeparateAccountsIndependently(t *testing.T) {
+	const symbol = "GOLD2024DEC"
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	lossPnL := -50.0
+	trades := []core.Trade{
+		{TradeID: "t1", ContractID: 1, Quantity: 5, Price: 2000, PnL: &lossPnL, TradeTime: base},
+	}
+
+	cfg := backtest.Config{
+		Symbols:   []string{symbol},
+		StartTime: base.Add(-time.Minute),
+		EndTime:   base.Add(time.Hour),
+		Accounts: []backtest.AccountConfig{
+			{UserID: 1, StartingBalance: 100000},
+			{UserID: 2, StartingBalance: 50000},
+		},
+	}
+
+	report, err := backtest.Run(cfg,
+		fixedMarketDataSource{bySymbol: map[string][]core.MarketData{symbol: nil}},
+		fixedTradeSource{bySymbol: map[string][]core.Trade{symbol: trades}},
+	)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(report.Accounts) != 2 {
+		t.Fatalf("Expected 2 account reports, got %d", len(report.Accounts))
+	}
+	for _, account := range report.Accounts {
+		if account.RealizedPnL != lossPnL {
+			t.Errorf("Expected account %d's RealizedPnL to independently replay the same trade (%.2f), got %.2f", account.UserID, lossPnL, account.RealizedPnL)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Package tests provides unit tests for the FIX/FAST communication layer
+package tests
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/communication"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/oms"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupCommunicationTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	err = db.AutoMigrate(
+		&core.User{},
+		&core.Contract{},
+		&core.Order{},
+		&core.Trade{},
+		&core.Position{},
+		&core.OrderFlowSnapshot{},
+		&core.FIXSessionRecord{},
+		&core.FIXPositionTierRecord{},
+	)
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	db.Create(&core.Contract{
+		Symbol:            "GOLD2024DEC",
+		ContractType:      core.ContractTypeGoldFutures,
+		ContractSize:      100.0,
+		TickSize:          0.01,
+		InitialMargin:     5000.0,
+		MaintenanceMargin: 3500.0,
+		IsActive:          true,
+	})
+	db.Create(&core.User{
+		Username:        "test_user",
+		Email:           "test@example.com",
+		AccountBalance:  100000.0,
+		MarginAvailable: 100000.0,
+		IsActive:        true,
+	})
+
+	return db
+}
+
+// TestFIXRoundTrip_MultipleOrdersOverRealSocket sends several NewOrderSingle
+// messages over a real TCP connection to a FIXAcceptor wired to a live
+// OrderManager, and asserts every one of them comes back as its own,
+// distinct ExecutionReport. This is a regression test for a framing bug
+// where a read loop mistook "read up to the first SOH" for "read one whole
+// message": with more than one field, or more than one message queued up
+// back-to-back, that bug corrupted or dropped everything after the first
+// field.
+func TestFIXRoundTrip_MultipleOrdersOverRealSocket(t *testing.T) {
+	const addr = "127.0.0.1:19321"
+
+	db := setupCommunicationTestDB(t)
+	store := communication.NewDatabaseSessionStore(db)
+	gateway := communication.NewFIXOrderGateway(oms.NewOrderManager(db), 1)
+
+	acceptor := communication.NewFIXAcceptor("CSE_EXCHANGE")
+	acceptor.SetSessionStore(store)
+	acceptor.SetOrderGateway(gateway)
+	if err := acceptor.ListenAndServe(addr); err != nil {
+		t.Fatalf("failed to start FIX acceptor: %v", err)
+	}
+	defer acceptor.Close()
+
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatalf("invalid test address %s: %v", addr, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatalf("invalid test port %s: %v", portStr, err)
+	}
+
+	client := communication.NewFIXEngine("TRADER1")
+	client.SetSessionStore(store)
+	client.Configure(host, port, "CSE_EXCHANGE")
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	client.OnExecutionReport(func(report communication.ExecutionReport) {
+		mu.Lock()
+		seen[report.ClOrdID] = true
+		mu.Unlock()
+	})
+
+	if err := client.Logon("trader1", "secret"); err != nil {
+		t.Fatalf("Logon failed: %v", err)
+	}
+	defer client.Logout()
+
+	const orderCount = 5
+	clOrdIDs := make([]string, 0, orderCount)
+	for i := 0; i < orderCount; i++ {
+		price := 2000.0 + float64(i)
+		clOrdID, err := client.SendNewOrderSingle(communication.NewOrderSingle{
+			Symbol:    "GOLD2024DEC",
+			Side:      "BUY",
+			OrderType: "LIMIT",
+			Quantity:  1,
+			Price:     &price,
+		})
+		if err != nil {
+			t.Fatalf("SendNewOrderSingle #%d failed: %v", i, err)
+		}
+		clOrdIDs = append(clOrdIDs, clOrdID)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		count := len(seen)
+		mu.Unlock()
+		if count == orderCount {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seen) != orderCount {
+		t.Fatalf("expected %d distinct ExecutionReports, got %d: %v", orderCount, len(seen), seen)
+	}
+	for _, clOrdID := range clOrdIDs {
+		if !seen[clOrdID] {
+			t.Errorf("never received an ExecutionReport for ClOrdID %s", clOrdID)
+		}
+	}
+}
+
+// TestFASTCodec_DecimalDeltaRoundTrip encodes and decodes a sequence of
+// MarketData messages (template 1's BidPrice/AskPrice/LastPrice fields are
+// all decimal+delta) and checks the decoded prices match, to more than
+// integer precision. This is a regression test for a bug where the delta
+// operator always delta-encoded toInt64(value) regardless of field type,
+// silently truncating decimal prices to whole numbers.
+func TestFASTCodec_DecimalDeltaRoundTrip(t *testing.T) {
+	encoder := communication.NewFASTDecoder()
+	decoder := communication.NewFASTDecoder()
+
+	ticks := []map[string]interface{}{
+		{"Symbol": "GOLD2024DEC", "BidPrice": 2050.25, "AskPrice": 2050.75, "LastPrice": 2050.50, "Volume": uint64(100)},
+		{"Symbol": "GOLD2024DEC", "BidPrice": 2051.50, "AskPrice": 2052.00, "LastPrice": 2051.75, "Volume": uint64(140)},
+		{"Symbol": "GOLD2024DEC", "BidPrice": 2049.75, "AskPrice": 2050.25, "LastPrice": 2050.00, "Volume": uint64(205)},
+	}
+
+	for i, tick := range ticks {
+		encoded, err := encoder.Encode(1, tick)
+		if err != nil {
+			t.Fatalf("tick %d: Encode failed: %v", i, err)
+		}
+
+		decoded, err := decoder.Decode(1, bytes.NewReader(encoded))
+		if err != nil {
+			t.Fatalf("tick %d: Decode failed: %v", i, err)
+		}
+
+		bid, ok := decoded["BidPrice"].(float64)
+		if !ok {
+			t.Fatalf("tick %d: expected BidPrice to decode as float64, got %T", i, decoded["BidPrice"])
+		}
+		if diff := bid - tick["BidPrice"].(float64); diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("tick %d: expected BidPrice %.2f, got %.2f", i, tick["BidPrice"], bid)
+		}
+
+		ask, ok := decoded["AskPrice"].(float64)
+		if !ok {
+			t.Fatalf("tick %d: expected AskPrice to decode as float64, got %T", i, decoded["AskPrice"])
+		}
+		if diff := ask - tick["AskPrice"].(float64); diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("tick %d: expected AskPrice %.2f, got %.2f", i, tick["AskPrice"], ask)
+		}
+	}
+}
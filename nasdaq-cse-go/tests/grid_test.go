@@ -0,0 +1,161 @@
+// Package tests provides unit tests for the grid market-making strategy
+package tests
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/oms"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/storage"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/strategy/grid"
+)
+
+// seedLastTradePrice crosses a resting sell with a market buy so the
+// matching engine's last trade price is set, giving Grid.Start a
+// reference price to build its ladder around.
+func seedLastTradePrice(t *testing.T, orderManager *oms.OrderManager, price float64) {
+	t.Helper()
+	restPrice := price
+	resting := orderManager.SubmitOrder(99, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideSell,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       1.0,
+		Price:          &restPrice,
+	})
+	if !resting.Success {
+		t.Fatalf("failed to seed resting order: %s", resting.Error)
+	}
+
+	taker := orderManager.SubmitOrder(98, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideBuy,
+		OrderType:      core.OrderTypeMarket,
+		Quantity:       1.0,
+	})
+	if !taker.Success {
+		t.Fatalf("failed to seed last trade price: %s", taker.Error)
+	}
+}
+
+func TestGrid_StartBuildsLadderWithinBounds(t *testing.T) {
+	db := setupTestDB(t)
+	orderManager := oms.NewOrderManager(db)
+	seedLastTradePrice(t, orderManager, 2000.0)
+
+	g := grid.NewGrid(orderManager, db, nil, 1, grid.Config{
+		Symbol:     "GOLD2024DEC",
+		GridNum:    4,
+		Margin:     0.01,
+		Quantity:   1.0,
+		UpperPrice: 2100.0,
+		LowerPrice: 1900.0,
+	})
+
+	if err := g.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	orders, err := orderManager.GetUserOrders(1, 10)
+	if err != nil {
+		t.Fatalf("GetUserOrders failed: %v", err)
+	}
+	if len(orders) != 4 {
+		t.Fatalf("Expected 4 ladder orders (2 buy + 2 sell), got %d", len(orders))
+	}
+}
+
+func TestGrid_FillReplacesLevelOneStepOut(t *testing.T) {
+	db := setupTestDB(t)
+	orderManager := oms.NewOrderManager(db)
+	seedLastTradePrice(t, orderManager, 2000.0)
+
+	g := grid.NewGrid(orderManager, db, nil, 1, grid.Config{
+		Symbol:     "GOLD2024DEC",
+		GridNum:    2,
+		Margin:     0.01,
+		Quantity:   1.0,
+		UpperPrice: 2100.0,
+		LowerPrice: 1900.0,
+	})
+	if err := g.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go g.Run(ctx)
+
+	// Cross the grid's resting buy level with a market sell so it fills;
+	// Run should then place a replacement sell one Margin step above it.
+	result := orderManager.SubmitOrder(2, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideSell,
+		OrderType:      core.OrderTypeMarket,
+		Quantity:       1.0,
+	})
+	if !result.Success {
+		t.Fatalf("failed to cross the grid's buy level: %s", result.Error)
+	}
+
+	// The buy level rests at 2000*(1-0.01); its replacement sell goes one
+	// Margin step above that, distinct from the ladder's own 2020 sell.
+	replacementPrice := 2000.0 * (1 - 0.01) * (1 + 0.01)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		orders, err := orderManager.GetUserOrders(1, 10)
+		if err != nil {
+			t.Fatalf("GetUserOrders failed: %v", err)
+		}
+		for _, order := range orders {
+			price, _ := order["price"].(*float64)
+			if order["side"] == string(core.OrderSideSell) && order["status"] == string(core.OrderStatusPending) &&
+				price != nil && math.Abs(*price-replacementPrice) < 1e-6 {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("Expected a replacement sell order at %.2f after the buy level filled", replacementPrice)
+}
+
+func TestGrid_FlattenCancelsRestingOrders(t *testing.T) {
+	db := setupTestDB(t)
+	orderManager := oms.NewOrderManager(db)
+	seedLastTradePrice(t, orderManager, 2000.0)
+
+	jsonStorage, err := storage.NewJSONStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewJSONStorage failed: %v", err)
+	}
+
+	g := grid.NewGrid(orderManager, db, jsonStorage, 1, grid.Config{
+		Symbol:     "GOLD2024DEC",
+		GridNum:    2,
+		Margin:     0.01,
+		Quantity:   1.0,
+		UpperPrice: 2100.0,
+		LowerPrice: 1900.0,
+	})
+	if err := g.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	if err := g.Flatten(false); err != nil {
+		t.Fatalf("Flatten failed: %v", err)
+	}
+
+	orders, err := orderManager.GetUserOrders(1, 10)
+	if err != nil {
+		t.Fatalf("GetUserOrders failed: %v", err)
+	}
+	for _, order := range orders {
+		if order["status"] == string(core.OrderStatusPending) {
+			t.Errorf("Expected no pending orders after Flatten, found %+v", order)
+		}
+	}
+}
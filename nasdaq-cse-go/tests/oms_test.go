@@ -2,7 +2,12 @@
 package tests
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
 	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/oms"
@@ -23,6 +28,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		&core.Order{},
 		&core.Trade{},
 		&core.Position{},
+		&core.OrderFlowSnapshot{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
@@ -102,7 +108,7 @@ func TestOrderManager_SubmitOrder(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := orderManager.SubmitOrder(tt.userID, tt.order)
-			
+
 			if tt.wantErr && result.Success {
 				t.Errorf("Expected error but got success")
 			}
@@ -231,4 +237,631 @@ func TestMatchingEngine_ProcessOrder(t *testing.T) {
 	if trade.Price <= 0 {
 		t.Errorf("Expected positive trade price but got %f", trade.Price)
 	}
-}
\ No newline at end of file
+}
+
+func TestMatchingEngine_RestingLimitOrderPartialFill(t *testing.T) {
+	db := setupTestDB(t)
+	matchingEngine := oms.NewMatchingEngine()
+
+	var contract core.Contract
+	db.First(&contract)
+
+	restPrice := 2100.0
+	restingSell := core.Order{
+		OrderID:    "resting-sell-1",
+		UserID:     1,
+		ContractID: contract.ID,
+		Side:       core.OrderSideSell,
+		OrderType:  core.OrderTypeLimit,
+		Quantity:   3.0,
+		Price:      &restPrice,
+		Status:     core.OrderStatusPending,
+	}
+	if _, err := matchingEngine.ProcessOrder(&restingSell, &contract, db); err != nil {
+		t.Fatalf("ProcessOrder (resting sell) failed: %v", err)
+	}
+	if restingSell.Status != core.OrderStatusPending {
+		t.Fatalf("Expected resting sell to stay PENDING but got %s", restingSell.Status)
+	}
+
+	takerBuy := core.Order{
+		OrderID:    "taker-buy-1",
+		UserID:     2,
+		ContractID: contract.ID,
+		Side:       core.OrderSideBuy,
+		OrderType:  core.OrderTypeLimit,
+		Quantity:   5.0,
+		Price:      &restPrice,
+		Status:     core.OrderStatusPending,
+	}
+	trades, err := matchingEngine.ProcessOrder(&takerBuy, &contract, db)
+	if err != nil {
+		t.Fatalf("ProcessOrder (taker buy) failed: %v", err)
+	}
+
+	if len(trades) != 1 {
+		t.Fatalf("Expected exactly one trade but got %d", len(trades))
+	}
+
+	trade := trades[0]
+	if trade.Quantity != 3.0 {
+		t.Errorf("Expected trade quantity 3.0 (limited by resting size) but got %f", trade.Quantity)
+	}
+	if trade.Price != restPrice {
+		t.Errorf("Expected trade price %f (maker's price) but got %f", restPrice, trade.Price)
+	}
+	if trade.BuyOrderID == nil || *trade.BuyOrderID != takerBuy.OrderID {
+		t.Errorf("Expected trade.BuyOrderID to reference the taker, got %v", trade.BuyOrderID)
+	}
+	if trade.SellOrderID == nil || *trade.SellOrderID != restingSell.OrderID {
+		t.Errorf("Expected trade.SellOrderID to reference the resting order, got %v", trade.SellOrderID)
+	}
+
+	if restingSell.Status != core.OrderStatusFilled {
+		t.Errorf("Expected resting sell to be FILLED but got %s", restingSell.Status)
+	}
+	if takerBuy.Status != core.OrderStatusPartiallyFilled {
+		t.Errorf("Expected taker buy to be PARTIALLY_FILLED but got %s", takerBuy.Status)
+	}
+	if takerBuy.FilledQuantity != 3.0 {
+		t.Errorf("Expected taker buy filled quantity 3.0 but got %f", takerBuy.FilledQuantity)
+	}
+
+	depth := matchingEngine.GetMarketDepth(contract.Symbol)
+	bids := depth["bids"]
+	if bids == nil {
+		t.Fatalf("Expected resting bid depth for the unfilled remainder")
+	}
+}
+
+func TestOrderManager_SubmitOrder_CrossUserMatchUpdatesBothPositions(t *testing.T) {
+	db := setupTestDB(t)
+	orderManager := oms.NewOrderManager(db)
+
+	sellPrice := 2100.0
+	sellResult := orderManager.SubmitOrder(1, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideSell,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       4.0,
+		Price:          &sellPrice,
+	})
+	if !sellResult.Success {
+		t.Fatalf("Failed to submit resting sell order: %s", sellResult.Error)
+	}
+
+	buyResult := orderManager.SubmitOrder(2, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideBuy,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       4.0,
+		Price:          &sellPrice,
+	})
+	if !buyResult.Success {
+		t.Fatalf("Failed to submit crossing buy order: %s", buyResult.Error)
+	}
+
+	sellerPositions, err := orderManager.GetUserPositions(1)
+	if err != nil {
+		t.Fatalf("GetUserPositions (seller) failed: %v", err)
+	}
+	if len(sellerPositions) != 1 || sellerPositions[0]["quantity"].(float64) != -4.0 {
+		t.Errorf("Expected seller position of -4.0, got %+v", sellerPositions)
+	}
+
+	buyerPositions, err := orderManager.GetUserPositions(2)
+	if err != nil {
+		t.Fatalf("GetUserPositions (buyer) failed: %v", err)
+	}
+	if len(buyerPositions) != 1 || buyerPositions[0]["quantity"].(float64) != 4.0 {
+		t.Errorf("Expected buyer position of 4.0, got %+v", buyerPositions)
+	}
+}
+
+func TestMatchingEngine_StopOrderTriggersOnTrade(t *testing.T) {
+	db := setupTestDB(t)
+	matchingEngine := oms.NewMatchingEngine()
+
+	var contract core.Contract
+	db.First(&contract)
+
+	printAt := func(symbol string, price float64) {
+		restPrice := price
+		resting := core.Order{
+			OrderID:    uuidForTest("resting", price),
+			UserID:     3,
+			ContractID: contract.ID,
+			Side:       core.OrderSideSell,
+			OrderType:  core.OrderTypeLimit,
+			Quantity:   1.0,
+			Price:      &restPrice,
+			Status:     core.OrderStatusPending,
+		}
+		if _, err := matchingEngine.ProcessOrder(&resting, &contract, db); err != nil {
+			t.Fatalf("ProcessOrder (resting print at %f) failed: %v", price, err)
+		}
+		taker := core.Order{
+			OrderID:    uuidForTest("taker", price),
+			UserID:     4,
+			ContractID: contract.ID,
+			Side:       core.OrderSideBuy,
+			OrderType:  core.OrderTypeLimit,
+			Quantity:   1.0,
+			Price:      &restPrice,
+			Status:     core.OrderStatusPending,
+		}
+		if _, err := matchingEngine.ProcessOrder(&taker, &contract, db); err != nil {
+			t.Fatalf("ProcessOrder (taker print at %f) failed: %v", price, err)
+		}
+	}
+
+	// Print a trade above the stop's trigger so arming it doesn't
+	// immediately fire, then arm the stop, then print a trade at or
+	// below it and confirm promotion.
+	printAt(contract.Symbol, 2060.0)
+
+	stopPrice := 2050.0
+	stopSell := core.Order{
+		OrderID:    "stop-sell-1",
+		UserID:     1,
+		ContractID: contract.ID,
+		Side:       core.OrderSideSell,
+		OrderType:  core.OrderTypeStop,
+		Quantity:   2.0,
+		StopPrice:  &stopPrice,
+		Status:     core.OrderStatusPending,
+	}
+	if _, err := matchingEngine.ProcessOrder(&stopSell, &contract, db); err != nil {
+		t.Fatalf("ProcessOrder (arm stop) failed: %v", err)
+	}
+	if stopSell.OrderType != core.OrderTypeStop {
+		t.Fatalf("Expected armed stop order to remain untouched until triggered")
+	}
+
+	printAt(contract.Symbol, 2040.0)
+
+	if stopSell.OrderType != core.OrderTypeMarket {
+		t.Errorf("Expected triggered stop to be promoted to a market order, got %s", stopSell.OrderType)
+	}
+	if stopSell.Status != core.OrderStatusFilled {
+		t.Errorf("Expected triggered stop to fill, got status %s", stopSell.Status)
+	}
+}
+
+// TestOrderManager_SubmitOrder_BothLegsClosingSumTheirPnL covers a trade
+// where both the buy and sell legs close out an existing position of their
+// own owner (rather than the usual case where one side opens/adds while
+// only the other closes). Since the two owners trade directly against each
+// other at the same price, their individual realized P&L contributions are
+// opposite and equal in magnitude to the overlapping quantity, so the
+// correct trade-level PnL is their sum: zero. Applying the second leg's
+// contribution by overwriting rather than adding would instead leave
+// whichever leg updates last as the final value.
+func TestOrderManager_SubmitOrder_BothLegsClosingSumTheirPnL(t *testing.T) {
+	db := setupTestDB(t)
+	orderManager := oms.NewOrderManager(db)
+
+	openPrice := 2000.0
+
+	// User 1 opens a 5-lot long against resting liquidity from user 9.
+	openSell := orderManager.SubmitOrder(9, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideSell,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       5.0,
+		Price:          &openPrice,
+	})
+	if !openSell.Success {
+		t.Fatalf("Failed to submit resting sell to open user 1's long: %s", openSell.Error)
+	}
+	openBuy := orderManager.SubmitOrder(1, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideBuy,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       5.0,
+		Price:          &openPrice,
+	})
+	if !openBuy.Success {
+		t.Fatalf("Failed to open user 1's long position: %s", openBuy.Error)
+	}
+
+	// User 2 opens a 2-lot short against resting liquidity from user 9.
+	openBuy2 := orderManager.SubmitOrder(9, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideBuy,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       2.0,
+		Price:          &openPrice,
+	})
+	if !openBuy2.Success {
+		t.Fatalf("Failed to submit resting buy to open user 2's short: %s", openBuy2.Error)
+	}
+	openSell2 := orderManager.SubmitOrder(2, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideSell,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       2.0,
+		Price:          &openPrice,
+	})
+	if !openSell2.Success {
+		t.Fatalf("Failed to open user 2's short position: %s", openSell2.Error)
+	}
+
+	// User 1 and user 2 now trade directly against each other: user 1
+	// sells 2 of their long (closing), user 2 buys 2 to cover their short
+	// (also closing) — both legs of the same trade are closes.
+	closePrice := 2100.0
+	closeSell := orderManager.SubmitOrder(1, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideSell,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       2.0,
+		Price:          &closePrice,
+	})
+	if !closeSell.Success {
+		t.Fatalf("Failed to submit resting closing sell: %s", closeSell.Error)
+	}
+	closeBuy := orderManager.SubmitOrder(2, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideBuy,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       2.0,
+		Price:          &closePrice,
+	})
+	if !closeBuy.Success {
+		t.Fatalf("Failed to submit crossing closing buy: %s", closeBuy.Error)
+	}
+
+	trades, err := orderManager.GetUserTrades(1, 10)
+	if err != nil {
+		t.Fatalf("GetUserTrades failed: %v", err)
+	}
+
+	var closingTrade map[string]interface{}
+	for _, trade := range trades {
+		if trade["price"].(float64) == closePrice {
+			closingTrade = trade
+		}
+	}
+	if closingTrade == nil {
+		t.Fatalf("Expected to find the closing trade at price %f among %+v", closePrice, trades)
+	}
+	pnl, ok := closingTrade["pnl"].(*float64)
+	if !ok || pnl == nil {
+		t.Fatalf("Expected the closing trade to carry a non-nil pnl, got %+v", closingTrade["pnl"])
+	}
+	if math.Abs(*pnl) > 1e-6 {
+		t.Errorf("Expected both legs' closing P&L to sum to 0 (a +200 close and a -200 close), got %f", *pnl)
+	}
+}
+
+// uuidForTest builds a deterministic, unique-enough order ID for the
+// synthetic print helper above without pulling in a real UUID generator.
+func uuidForTest(prefix string, price float64) string {
+	return fmt.Sprintf("%s-%v", prefix, price)
+}
+
+func TestOrderManager_SubmitOrder_RealizedPnLAndStrategyTagging(t *testing.T) {
+	db := setupTestDB(t)
+	orderManager := oms.NewOrderManager(db)
+
+	// User 1 rests a sell at 2000 (tagged "grid"); user 2 crosses it to
+	// open a 2-lot long at an average entry of 2000.
+	openPrice := 2000.0
+	openSell := orderManager.SubmitOrder(1, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideSell,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       2.0,
+		Price:          &openPrice,
+		Strategy:       "grid",
+	})
+	if !openSell.Success {
+		t.Fatalf("Failed to submit resting sell to open a long for the counterparty: %s", openSell.Error)
+	}
+
+	openBuy := orderManager.SubmitOrder(2, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideBuy,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       2.0,
+		Price:          &openPrice,
+	})
+	if !openBuy.Success {
+		t.Fatalf("Failed to open buyer's long position: %s", openBuy.Error)
+	}
+
+	// User 3 rests a buy at 2100 so user 2 can close the long there;
+	// should realize +200 (2 * (2100-2000)).
+	closePrice := 2100.0
+	restingCloseBuy := orderManager.SubmitOrder(3, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideBuy,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       2.0,
+		Price:          &closePrice,
+	})
+	if !restingCloseBuy.Success {
+		t.Fatalf("Failed to submit resting buy at close price: %s", restingCloseBuy.Error)
+	}
+
+	closeSell := orderManager.SubmitOrder(2, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideSell,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       2.0,
+		Price:          &closePrice,
+		Strategy:       "manual",
+	})
+	if !closeSell.Success {
+		t.Fatalf("Failed to submit closing sell: %s", closeSell.Error)
+	}
+
+	trades, err := orderManager.GetUserTrades(2, 10)
+	if err != nil {
+		t.Fatalf("GetUserTrades failed: %v", err)
+	}
+
+	var closingTrade map[string]interface{}
+	for _, trade := range trades {
+		if trade["price"].(float64) == closePrice {
+			closingTrade = trade
+		}
+	}
+	if closingTrade == nil {
+		t.Fatalf("Expected to find the closing trade at price %f among %+v", closePrice, trades)
+	}
+	pnl, ok := closingTrade["pnl"].(*float64)
+	if !ok || pnl == nil {
+		t.Fatalf("Expected the closing trade to carry a non-nil pnl, got %+v", closingTrade["pnl"])
+	}
+	if math.Abs(*pnl-200.0) > 1e-6 {
+		t.Errorf("Expected realized P&L of 200.0, got %f", *pnl)
+	}
+
+	breakdown, err := orderManager.GetUserPnLByStrategy(2, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetUserPnLByStrategy failed: %v", err)
+	}
+	var found bool
+	for _, entry := range breakdown {
+		if entry["strategy"] == "manual" {
+			found = true
+			if math.Abs(entry["total_pnl"].(float64)-200.0) > 1e-6 {
+				t.Errorf("Expected manual strategy total P&L of 200.0, got %v", entry["total_pnl"])
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Expected a 'manual' entry in the P&L breakdown, got %+v", breakdown)
+	}
+}
+
+func TestOrderManager_BatchSubmitOrders(t *testing.T) {
+	db := setupTestDB(t)
+	orderManager := oms.NewOrderManager(db)
+
+	// A 4-level grid: two resting sells above market, two resting buys
+	// below, submitted as a single batch. None of them cross each other,
+	// so all four should come back PENDING with no trades.
+	prices := []float64{2010.0, 2020.0, 1990.0, 1980.0}
+	reqs := []core.OrderCreateRequest{
+		{ContractSymbol: "GOLD2024DEC", Side: core.OrderSideSell, OrderType: core.OrderTypeLimit, Quantity: 1.0, Price: &prices[0], Strategy: "grid"},
+		{ContractSymbol: "GOLD2024DEC", Side: core.OrderSideSell, OrderType: core.OrderTypeLimit, Quantity: 1.0, Price: &prices[1], Strategy: "grid"},
+		{ContractSymbol: "GOLD2024DEC", Side: core.OrderSideBuy, OrderType: core.OrderTypeLimit, Quantity: 1.0, Price: &prices[2], Strategy: "grid"},
+		{ContractSymbol: "GOLD2024DEC", Side: core.OrderSideBuy, OrderType: core.OrderTypeLimit, Quantity: 1.0, Price: &prices[3], Strategy: "grid"},
+	}
+
+	results := orderManager.BatchSubmitOrders(1, reqs)
+	if len(results) != len(reqs) {
+		t.Fatalf("Expected %d results but got %d", len(reqs), len(results))
+	}
+	for i, result := range results {
+		if !result.Success {
+			t.Errorf("Expected entry %d to succeed, got error: %s", i, result.Error)
+		}
+		if result.Status != string(core.OrderStatusPending) {
+			t.Errorf("Expected entry %d to stay PENDING, got %s", i, result.Status)
+		}
+	}
+
+	var orderCount int64
+	db.Model(&core.Order{}).Where("user_id = ?", 1).Count(&orderCount)
+	if orderCount != int64(len(reqs)) {
+		t.Errorf("Expected %d orders persisted but found %d", len(reqs), orderCount)
+	}
+
+	// An unknown symbol should fail just that entry without sinking the
+	// rest of the batch.
+	mixed := orderManager.BatchSubmitOrders(1, []core.OrderCreateRequest{
+		{ContractSymbol: "INVALID", Side: core.OrderSideBuy, OrderType: core.OrderTypeMarket, Quantity: 1.0},
+		{ContractSymbol: "GOLD2024DEC", Side: core.OrderSideBuy, OrderType: core.OrderTypeMarket, Quantity: 1.0},
+	})
+	if mixed[0].Success {
+		t.Errorf("Expected the unknown-symbol entry to fail")
+	}
+	if !mixed[1].Success {
+		t.Errorf("Expected the valid entry to still succeed: %s", mixed[1].Error)
+	}
+}
+
+func TestOrderManager_BatchRetryPlaceOrders(t *testing.T) {
+	db := setupTestDB(t)
+	orderManager := oms.NewOrderManager(db)
+
+	price := 2050.0
+	reqs := []core.OrderCreateRequest{
+		{ContractSymbol: "GOLD2024DEC", Side: core.OrderSideBuy, OrderType: core.OrderTypeLimit, Quantity: 1.0, Price: &price},
+		{ContractSymbol: "INVALID", Side: core.OrderSideBuy, OrderType: core.OrderTypeMarket, Quantity: 1.0},
+	}
+
+	policy := oms.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	results := orderManager.BatchRetryPlaceOrders(context.Background(), 1, reqs, policy)
+
+	if len(results) != len(reqs) {
+		t.Fatalf("Expected %d results but got %d", len(reqs), len(results))
+	}
+	if !results[0].Success {
+		t.Errorf("Expected the valid order to succeed after retry, got error: %s", results[0].Error)
+	}
+	if results[1].Success {
+		t.Errorf("Expected the invalid-symbol entry to keep failing across all attempts")
+	}
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	canceled := orderManager.BatchRetryPlaceOrders(canceledCtx, 1, reqs, policy)
+	for i, result := range canceled {
+		if result.Success {
+			t.Errorf("Expected entry %d to fail on an already-canceled context", i)
+		}
+	}
+}
+
+func TestOrderManager_OrderLifecycleHooks(t *testing.T) {
+	db := setupTestDB(t)
+	orderManager := oms.NewOrderManager(db)
+
+	var mu sync.Mutex
+	var newOrders []string
+	var filledOrders []string
+	var cancelledOrders []string
+	var tradeCount int
+
+	orderManager.OnOrderNew(func(order *core.Order) {
+		mu.Lock()
+		defer mu.Unlock()
+		newOrders = append(newOrders, order.OrderID)
+	})
+	orderManager.OnOrderFilled(func(order *core.Order, trades []core.Trade) {
+		mu.Lock()
+		defer mu.Unlock()
+		filledOrders = append(filledOrders, order.OrderID)
+	})
+	orderManager.OnOrderCancelled(func(order *core.Order) {
+		mu.Lock()
+		defer mu.Unlock()
+		cancelledOrders = append(cancelledOrders, order.OrderID)
+	})
+	orderManager.OnTrade(func(trade core.Trade) {
+		mu.Lock()
+		defer mu.Unlock()
+		tradeCount++
+	})
+
+	price := 2050.0
+	resting := orderManager.SubmitOrder(1, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideSell,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       1.0,
+		Price:          &price,
+	})
+	if !resting.Success {
+		t.Fatalf("failed to submit resting order: %s", resting.Error)
+	}
+
+	cancelResult := orderManager.CancelOrder(resting.OrderID, 1)
+	if cancelResult["success"] != true {
+		t.Fatalf("failed to cancel order: %v", cancelResult["error"])
+	}
+
+	resting2 := orderManager.SubmitOrder(1, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideSell,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       1.0,
+		Price:          &price,
+	})
+	if !resting2.Success {
+		t.Fatalf("failed to submit second resting order: %s", resting2.Error)
+	}
+	taker := orderManager.SubmitOrder(2, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideBuy,
+		OrderType:      core.OrderTypeMarket,
+		Quantity:       1.0,
+	})
+	if !taker.Success {
+		t.Fatalf("failed to submit taker order: %s", taker.Error)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		done := len(newOrders) == 3 && len(cancelledOrders) == 1 && len(filledOrders) == 1 && tradeCount == 1
+		mu.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(newOrders) != 3 {
+		t.Errorf("Expected 3 OnOrderNew callbacks, got %d", len(newOrders))
+	}
+	if len(cancelledOrders) != 1 || cancelledOrders[0] != resting.OrderID {
+		t.Errorf("Expected OnOrderCancelled once for %s, got %v", resting.OrderID, cancelledOrders)
+	}
+	// OnOrderFilled fires for the order SubmitOrder/BatchSubmitOrders was
+	// actually asked to process (taker), not the resting counterparty it
+	// matched against, so only one callback is expected here.
+	if len(filledOrders) != 1 || filledOrders[0] != taker.OrderID {
+		t.Errorf("Expected OnOrderFilled once for the taker order %s, got %v", taker.OrderID, filledOrders)
+	}
+	if tradeCount != 1 {
+		t.Errorf("Expected 1 OnTrade callback for the single trade created, got %d", tradeCount)
+	}
+}
+
+func TestMatchingEngine_Subscribe(t *testing.T) {
+	db := setupTestDB(t)
+	orderManager := oms.NewOrderManager(db)
+
+	events := orderManager.Subscribe()
+
+	price := 2050.0
+	resting := orderManager.SubmitOrder(1, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideSell,
+		OrderType:      core.OrderTypeLimit,
+		Quantity:       1.0,
+		Price:          &price,
+	})
+	if !resting.Success {
+		t.Fatalf("failed to submit resting order: %s", resting.Error)
+	}
+	taker := orderManager.SubmitOrder(2, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideBuy,
+		OrderType:      core.OrderTypeMarket,
+		Quantity:       1.0,
+	})
+	if !taker.Success {
+		t.Fatalf("failed to submit taker order: %s", taker.Error)
+	}
+
+	var sawTrade, sawBookDelta bool
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && !(sawTrade && sawBookDelta) {
+		select {
+		case event := <-events:
+			switch event.Kind {
+			case oms.EventTrade:
+				sawTrade = true
+			case oms.EventBookDelta:
+				sawBookDelta = true
+			}
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	if !sawTrade {
+		t.Errorf("Expected at least one trade Event on the Subscribe channel")
+	}
+	if !sawBookDelta {
+		t.Errorf("Expected at least one book_delta Event on the Subscribe channel")
+	}
+}
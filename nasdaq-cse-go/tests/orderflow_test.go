@@ -0,0 +1,92 @@
+// Package tests provides unit tests for the order-flow imbalance analyzer
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/aiassistant"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+)
+
+func TestOrderFlowAnalyzer_RecordClassifiesAggressor(t *testing.T) {
+	ofa := aiassistant.NewOrderFlowAnalyzer(time.Minute, 10)
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	ofa.Record(core.Trade{Price: 101.0, Quantity: 2.0, TradeTime: base}, 99.0, 101.0)
+	ofa.Record(core.Trade{Price: 99.0, Quantity: 1.0, TradeTime: base.Add(time.Second)}, 99.0, 101.0)
+	ofa.Record(core.Trade{Price: 100.0, Quantity: 5.0, TradeTime: base.Add(2 * time.Second)}, 99.0, 101.0)
+
+	latest := ofa.Latest()
+	if latest.BuyVolume != 2 {
+		t.Errorf("Expected BuyVolume 2 from the at-ask trade, got %f", latest.BuyVolume)
+	}
+	if latest.SellVolume != 1 {
+		t.Errorf("Expected SellVolume 1 from the at-bid trade, got %f", latest.SellVolume)
+	}
+	expectedRatio := (2.0 - 1.0) / (2.0 + 1.0)
+	if latest.ImbalanceRatio != expectedRatio {
+		t.Errorf("Expected ImbalanceRatio %f, got %f", expectedRatio, latest.ImbalanceRatio)
+	}
+}
+
+func TestOrderFlowAnalyzer_IntervalRolloverArchivesAndAccumulatesCVD(t *testing.T) {
+	ofa := aiassistant.NewOrderFlowAnalyzer(time.Minute, 10)
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	ofa.Record(core.Trade{Price: 101.0, Quantity: 3.0, TradeTime: base}, 99.0, 101.0)
+	ofa.Record(core.Trade{Price: 101.0, Quantity: 2.0, TradeTime: base.Add(time.Minute)}, 99.0, 101.0)
+
+	history := ofa.History()
+	if len(history) != 1 {
+		t.Fatalf("Expected the first interval archived after rollover, got %d entries", len(history))
+	}
+	if history[0].BuyVolume != 3 {
+		t.Errorf("Expected the archived interval's BuyVolume 3, got %f", history[0].BuyVolume)
+	}
+	if history[0].CVD != 3 {
+		t.Errorf("Expected the archived interval's CVD 3, got %f", history[0].CVD)
+	}
+
+	latest := ofa.Latest()
+	if latest.CVD != 5 {
+		t.Errorf("Expected the new interval's CVD to carry forward to 5 (3+2), got %f", latest.CVD)
+	}
+}
+
+func TestOrderFlowAnalyzer_HistoryTrimmedToCapacity(t *testing.T) {
+	ofa := aiassistant.NewOrderFlowAnalyzer(time.Minute, 2)
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 4; i++ {
+		ofa.Record(core.Trade{Price: 101.0, Quantity: 1.0, TradeTime: base.Add(time.Duration(i) * time.Minute)}, 99.0, 101.0)
+	}
+
+	history := ofa.History()
+	if len(history) != 2 {
+		t.Fatalf("Expected history trimmed to capacity 2, got %d entries", len(history))
+	}
+}
+
+func TestOrderFlowImbalance_Persist(t *testing.T) {
+	db := setupTestDB(t)
+
+	ofi := aiassistant.OrderFlowImbalance{
+		IntervalStart:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		BuyVolume:      3,
+		SellVolume:     1,
+		CVD:            2,
+		ImbalanceRatio: 0.5,
+	}
+	if err := ofi.Persist(db, 1); err != nil {
+		t.Fatalf("Persist failed: %v", err)
+	}
+
+	var stored core.OrderFlowSnapshot
+	if err := db.First(&stored).Error; err != nil {
+		t.Fatalf("failed to read back the persisted snapshot: %v", err)
+	}
+	if stored.ContractID != 1 || stored.ImbalanceRatio != 0.5 {
+		t.Errorf("Expected the persisted snapshot to match, got %+v", stored)
+	}
+}
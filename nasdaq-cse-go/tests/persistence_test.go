@@ -0,0 +1,79 @@
+// Package tests provides unit tests for the generic persistence store
+package tests
+
+import (
+	"testing"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/persistence"
+)
+
+type persistenceTestValue struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestJSONStore_SaveLoadDelete(t *testing.T) {
+	store, err := persistence.NewJSONStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewJSONStore failed: %v", err)
+	}
+
+	if err := store.Save("widget", persistenceTestValue{Name: "widget", Count: 3}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	var loaded persistenceTestValue
+	if err := store.Load("widget", &loaded); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Name != "widget" || loaded.Count != 3 {
+		t.Errorf("Expected {widget 3}, got %+v", loaded)
+	}
+
+	if err := store.Delete("widget"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := store.Load("widget", &loaded); err != persistence.ErrNotFound {
+		t.Errorf("Expected ErrNotFound after Delete, got %v", err)
+	}
+}
+
+func TestJSONStore_LoadMissingKeyReturnsErrNotFound(t *testing.T) {
+	store, err := persistence.NewJSONStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewJSONStore failed: %v", err)
+	}
+
+	var dest persistenceTestValue
+	if err := store.Load("never-saved", &dest); err != persistence.ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+type taggedTestState struct {
+	Widget  persistenceTestValue `persistence:"widget"`
+	Ignored string
+}
+
+func TestSaveTaggedLoadTagged_RoundTrip(t *testing.T) {
+	store, err := persistence.NewJSONStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewJSONStore failed: %v", err)
+	}
+
+	saved := taggedTestState{Widget: persistenceTestValue{Name: "gear", Count: 7}, Ignored: "not persisted"}
+	if err := persistence.SaveTagged(store, &saved); err != nil {
+		t.Fatalf("SaveTagged failed: %v", err)
+	}
+
+	loaded := taggedTestState{}
+	if err := persistence.LoadTagged(store, &loaded); err != nil {
+		t.Fatalf("LoadTagged failed: %v", err)
+	}
+	if loaded.Widget != saved.Widget {
+		t.Errorf("Expected tagged field to round-trip, got %+v", loaded.Widget)
+	}
+	if loaded.Ignored != "" {
+		t.Errorf("Expected untagged field to stay untouched, got %q", loaded.Ignored)
+	}
+}
@@ -0,0 +1,126 @@
+// Package tests provides unit tests for the per-user risk circuit breaker
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/persistence"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/risk/circuitbreaker"
+)
+
+func losingTrade(pnl float64, at time.Time) core.Trade {
+	return core.Trade{PnL: &pnl, TradeTime: at}
+}
+
+func TestCircuitBreaker_TripsOnConsecutiveLosses(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker(circuitbreaker.Config{
+		Window:               24 * time.Hour,
+		MaxCumulativeLoss:    1_000_000, // effectively disabled for this test
+		MaxConsecutiveLosses: 5,
+		MaxDrawdownPercent:   1.0, // effectively disabled for this test
+		Cooldown:             time.Hour,
+	})
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		cb.RecordTrade(1, losingTrade(-10.0, now.Add(time.Duration(i)*time.Second)), 10000.0)
+	}
+
+	if allowed, reason := cb.CanTrade(1); allowed {
+		t.Errorf("Expected the breaker to trip after 5 consecutive losing trades, got allowed with no reason (%q)", reason)
+	}
+}
+
+func TestCircuitBreaker_WinningTradeResetsStreak(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker(circuitbreaker.Config{
+		Window:               24 * time.Hour,
+		MaxCumulativeLoss:    1_000_000,
+		MaxConsecutiveLosses: 3,
+		MaxDrawdownPercent:   1.0,
+		Cooldown:             time.Hour,
+	})
+
+	now := time.Now()
+	cb.RecordTrade(1, losingTrade(-10.0, now), 10000.0)
+	cb.RecordTrade(1, losingTrade(-10.0, now.Add(time.Second)), 10000.0)
+	cb.RecordTrade(1, losingTrade(20.0, now.Add(2*time.Second)), 10000.0)
+	cb.RecordTrade(1, losingTrade(-10.0, now.Add(3*time.Second)), 10000.0)
+	cb.RecordTrade(1, losingTrade(-10.0, now.Add(4*time.Second)), 10000.0)
+
+	if allowed, _ := cb.CanTrade(1); !allowed {
+		t.Errorf("Expected a winning trade mid-streak to reset the consecutive-loss count, but the breaker tripped")
+	}
+}
+
+func TestCircuitBreaker_TripsOnCumulativeLoss(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker(circuitbreaker.Config{
+		Window:               24 * time.Hour,
+		MaxCumulativeLoss:    100.0,
+		MaxConsecutiveLosses: 1000,
+		MaxDrawdownPercent:   1.0,
+		Cooldown:             time.Hour,
+	})
+
+	cb.RecordTrade(1, losingTrade(-60.0, time.Now()), 10000.0)
+	cb.RecordTrade(1, losingTrade(-60.0, time.Now()), 10000.0)
+
+	if allowed, _ := cb.CanTrade(1); allowed {
+		t.Errorf("Expected cumulative loss over 100 to trip the breaker")
+	}
+}
+
+func TestCircuitBreaker_TrackedPerUser(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker(circuitbreaker.Config{
+		Window: 24 * time.Hour, MaxCumulativeLoss: 1_000_000, MaxConsecutiveLosses: 2, MaxDrawdownPercent: 1.0, Cooldown: time.Hour,
+	})
+
+	now := time.Now()
+	cb.RecordTrade(1, losingTrade(-10.0, now), 10000.0)
+	cb.RecordTrade(1, losingTrade(-10.0, now.Add(time.Second)), 10000.0)
+
+	if allowed, _ := cb.CanTrade(1); allowed {
+		t.Errorf("Expected user 1's breaker to trip")
+	}
+	if allowed, _ := cb.CanTrade(2); !allowed {
+		t.Errorf("Expected user 2's breaker to be unaffected by user 1's losses")
+	}
+}
+
+func TestCircuitBreaker_ResetReopensImmediately(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker(circuitbreaker.Config{
+		Window: 24 * time.Hour, MaxCumulativeLoss: 1_000_000, MaxConsecutiveLosses: 1, MaxDrawdownPercent: 1.0, Cooldown: time.Hour,
+	})
+
+	cb.RecordTrade(1, losingTrade(-10.0, time.Now()), 10000.0)
+	if allowed, _ := cb.CanTrade(1); allowed {
+		t.Fatalf("Expected the breaker to be tripped before Reset")
+	}
+
+	cb.Reset(1)
+	if allowed, _ := cb.CanTrade(1); !allowed {
+		t.Errorf("Expected Reset to reopen the breaker immediately, ignoring cooldown")
+	}
+}
+
+func TestCircuitBreaker_PersistsTripAcrossRestart(t *testing.T) {
+	store, err := persistence.NewJSONStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewJSONStore failed: %v", err)
+	}
+	cfg := circuitbreaker.Config{Window: 24 * time.Hour, MaxCumulativeLoss: 1_000_000, MaxConsecutiveLosses: 1, MaxDrawdownPercent: 1.0, Cooldown: time.Hour}
+
+	cb := circuitbreaker.NewCircuitBreakerWithStore(cfg, store, "test_risk_breaker")
+	cb.RecordTrade(1, losingTrade(-10.0, time.Now()), 10000.0)
+	if allowed, _ := cb.CanTrade(1); allowed {
+		t.Fatalf("Expected the breaker to be tripped before restart")
+	}
+
+	restarted := circuitbreaker.NewCircuitBreakerWithStore(cfg, store, "test_risk_breaker")
+	if allowed, reason := restarted.CanTrade(1); allowed {
+		t.Errorf("Expected trip status to survive a restart against the same store")
+	} else if reason == "" {
+		t.Errorf("Expected a non-empty trip reason to survive the restart")
+	}
+}
@@ -0,0 +1,77 @@
+// Package tests provides unit tests for the strategy plugin runtime
+package tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/exchange"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/strategy"
+)
+
+// fakeStrategy records every callback it receives, for assertions.
+type fakeStrategy struct {
+	symbols []string
+	klines  []exchange.Kline
+	ctx     *strategy.StrategyContext
+}
+
+func (f *fakeStrategy) Subscribe() []string                { return f.symbols }
+func (f *fakeStrategy) Init(ctx *strategy.StrategyContext) { f.ctx = ctx }
+func (f *fakeStrategy) OnKline(kline exchange.Kline)       { f.klines = append(f.klines, kline) }
+func (f *fakeStrategy) OnTrade(trade core.Trade)           {}
+func (f *fakeStrategy) OnOrderUpdate(order core.Order)     {}
+
+func TestStrategyRegistryRoundTrip(t *testing.T) {
+	strategy.Register("fake-test-strategy", func(params map[string]interface{}) (strategy.Strategy, error) {
+		symbol, _ := params["symbol"].(string)
+		return &fakeStrategy{symbols: []string{symbol}}, nil
+	})
+
+	s, err := strategy.NewStrategy("fake-test-strategy", map[string]interface{}{"symbol": "GOLD2024DEC"})
+	if err != nil {
+		t.Fatalf("NewStrategy returned error: %v", err)
+	}
+
+	if got := s.Subscribe(); len(got) != 1 || got[0] != "GOLD2024DEC" {
+		t.Errorf("expected Subscribe() to return [GOLD2024DEC], got %v", got)
+	}
+}
+
+func TestNewStrategyUnknownType(t *testing.T) {
+	if _, err := strategy.NewStrategy("does-not-exist", nil); err == nil {
+		t.Error("expected an error for an unregistered strategy type")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	yaml := `
+exchangeStrategies:
+  - id: my_grid
+    on: sim
+    grid:
+      symbol: GOLD2024DEC
+      gridNum: 10
+`
+	path := t.TempDir() + "/strategies.yaml"
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+
+	cfg, err := strategy.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	if len(cfg.ExchangeStrategies) != 1 {
+		t.Fatalf("expected 1 strategy entry, got %d", len(cfg.ExchangeStrategies))
+	}
+
+	entry := cfg.ExchangeStrategies[0]
+	if entry.ID != "my_grid" || entry.On != "sim" || entry.Type != "grid" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if entry.Params["symbol"] != "GOLD2024DEC" {
+		t.Errorf("expected params.symbol GOLD2024DEC, got %v", entry.Params["symbol"])
+	}
+}
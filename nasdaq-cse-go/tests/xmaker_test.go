@@ -0,0 +1,206 @@
+// Package tests provides unit tests for the cross-exchange market maker
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/core"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/marketdata"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/oms"
+	"github.com/smaruf/python-ai-course/nasdaq-cse-go/internal/strategy/xmaker"
+)
+
+// fakeSource is a marketdata.PriceSource that streams ticks pushed onto it
+// by a test, instead of polling a real exchange.
+type fakeSource struct {
+	ticks chan marketdata.Tick
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{ticks: make(chan marketdata.Tick, 8)}
+}
+
+func (s *fakeSource) Name() string { return "fake" }
+
+func (s *fakeSource) Subscribe(symbol string) (<-chan marketdata.Tick, error) {
+	return s.ticks, nil
+}
+
+func (s *fakeSource) Snapshot(symbol string) (marketdata.Tick, error) {
+	return marketdata.Tick{Symbol: symbol}, nil
+}
+
+// fakeHedger is a xmaker.HedgeExecutor that records every hedge order it's
+// asked to place instead of sending one to a real venue.
+type fakeHedger struct {
+	mu    sync.Mutex
+	calls []core.OrderSide
+}
+
+func (h *fakeHedger) SubmitHedgeOrder(venue, contractSymbol string, side core.OrderSide, quantity, price float64) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls = append(h.calls, side)
+	return nil
+}
+
+func (h *fakeHedger) callCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.calls)
+}
+
+func TestMaker_RunQuotesLadderAroundSourcePrice(t *testing.T) {
+	db := setupTestDB(t)
+	orderManager := oms.NewOrderManager(db)
+	source := newFakeSource()
+
+	m := xmaker.NewMaker(orderManager, db, source, &fakeHedger{}, 1, xmaker.Config{
+		Symbol:          "GOLD2024DEC",
+		Margin:          0.01,
+		NumLayers:       2,
+		PipOffset:       0.5,
+		Quantity:        1.0,
+		MoveThreshold:   1.0,
+		OrdersPerSecond: 50,
+		Burst:           10,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Run(ctx)
+
+	source.ticks <- marketdata.Tick{Symbol: "GOLD2024DEC", Last: 2000.0}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		orders, err := orderManager.GetUserOrders(1, 10)
+		if err != nil {
+			t.Fatalf("GetUserOrders failed: %v", err)
+		}
+		if len(orders) == 4 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("Expected 4 ladder orders (2 buy + 2 sell) after a source tick")
+}
+
+func TestMaker_StopCancelsRestingQuotes(t *testing.T) {
+	db := setupTestDB(t)
+	orderManager := oms.NewOrderManager(db)
+	source := newFakeSource()
+
+	m := xmaker.NewMaker(orderManager, db, source, &fakeHedger{}, 1, xmaker.Config{
+		Symbol:          "GOLD2024DEC",
+		Margin:          0.01,
+		NumLayers:       1,
+		PipOffset:       0.5,
+		Quantity:        1.0,
+		MoveThreshold:   1.0,
+		OrdersPerSecond: 50,
+		Burst:           10,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Run(ctx)
+
+	source.ticks <- marketdata.Tick{Symbol: "GOLD2024DEC", Last: 2000.0}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		orders, err := orderManager.GetUserOrders(1, 10)
+		if err != nil {
+			t.Fatalf("GetUserOrders failed: %v", err)
+		}
+		if len(orders) == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	m.Stop()
+
+	deadline = time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		orders, err := orderManager.GetUserOrders(1, 10)
+		if err != nil {
+			t.Fatalf("GetUserOrders failed: %v", err)
+		}
+		allCancelled := len(orders) > 0
+		for _, order := range orders {
+			if order["status"] == string(core.OrderStatusPending) {
+				allCancelled = false
+			}
+		}
+		if allCancelled {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("Expected Stop to cancel every resting quote")
+}
+
+func TestMaker_HedgeIfNeededOffsetsAccumulatedPosition(t *testing.T) {
+	db := setupTestDB(t)
+	orderManager := oms.NewOrderManager(db)
+	source := newFakeSource()
+	hedger := &fakeHedger{}
+
+	m := xmaker.NewMaker(orderManager, db, source, hedger, 1, xmaker.Config{
+		Symbol:          "GOLD2024DEC",
+		Margin:          0.01,
+		NumLayers:       1,
+		PipOffset:       0.5,
+		Quantity:        1.0,
+		MoveThreshold:   1.0,
+		CoveredPosition: 5,
+		OrdersPerSecond: 50,
+		Burst:           10,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Run(ctx)
+
+	source.ticks <- marketdata.Tick{Symbol: "GOLD2024DEC", Last: 2000.0}
+
+	// Wait for the ladder to go up, then cross its resting bid with a
+	// market sell so Maker accumulates a long position to hedge.
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		orders, err := orderManager.GetUserOrders(1, 10)
+		if err != nil {
+			t.Fatalf("GetUserOrders failed: %v", err)
+		}
+		if len(orders) == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	taker := orderManager.SubmitOrder(2, core.OrderCreateRequest{
+		ContractSymbol: "GOLD2024DEC",
+		Side:           core.OrderSideSell,
+		OrderType:      core.OrderTypeMarket,
+		Quantity:       1.0,
+	})
+	if !taker.Success {
+		t.Fatalf("failed to cross the maker's bid: %s", taker.Error)
+	}
+
+	source.ticks <- marketdata.Tick{Symbol: "GOLD2024DEC", Last: 2000.0}
+
+	deadline = time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if hedger.callCount() > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("Expected hedgeIfNeeded to submit a hedge order once a position accumulated")
+}